@@ -5,6 +5,9 @@ go 1.23.6
 require (
 	github.com/google/go-github/v68 v68.0.0
 	github.com/iures/daivplug v0.0.3
+	golang.org/x/sync v0.10.0
+	golang.org/x/text v0.21.0
+	golang.org/x/time v0.8.0
 )
 
 require github.com/google/go-querystring v1.1.0 // indirect
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CommandRunner runs an external command and returns its standard output
+type CommandRunner func(name string, args ...string) ([]byte, error)
+
+// runCommand is the CommandRunner that executes commands on the host
+func runCommand(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// TokenSource supplies a GitHub token
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func() (string, error)
+
+// Token implements TokenSource
+func (f TokenSourceFunc) Token() (string, error) {
+	return f()
+}
+
+// CLITokenSource reads the token the gh CLI is logged in with
+type CLITokenSource struct {
+	// Run executes gh. A nil Run executes it on the host.
+	Run CommandRunner
+}
+
+// Token implements TokenSource
+func (s CLITokenSource) Token() (string, error) {
+	return runGh(s.Run, "auth", "token")
+}
+
+// runGh runs the gh CLI with the given arguments and returns its trimmed
+// output
+func runGh(run CommandRunner, args ...string) (string, error) {
+	if run == nil {
+		run = runCommand
+	}
+
+	output, err := run("gh", args...)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("gh cli error: %s", string(exitErr.Stderr))
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("gh cli not found: %w", err)
+		}
+		return "", fmt.Errorf("failed to execute gh cli: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
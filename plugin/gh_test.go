@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCLITokenSource(t *testing.T) {
+	t.Run("Returns the gh token", func(t *testing.T) {
+		var gotName string
+		var gotArgs []string
+		source := CLITokenSource{Run: func(name string, args ...string) ([]byte, error) {
+			gotName, gotArgs = name, args
+			return []byte("cli-token\n"), nil
+		}}
+
+		token, err := source.Token()
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if token != "cli-token" {
+			t.Errorf("Expected token 'cli-token', got '%s'", token)
+		}
+		if gotName != "gh" || !slices.Equal(gotArgs, []string{"auth", "token"}) {
+			t.Errorf("Expected 'gh auth token', got '%s %s'", gotName, strings.Join(gotArgs, " "))
+		}
+	})
+
+	t.Run("Reports gh not found", func(t *testing.T) {
+		source := CLITokenSource{Run: func(name string, args ...string) ([]byte, error) {
+			return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+		}}
+
+		_, err := source.Token()
+		if !errors.Is(err, exec.ErrNotFound) {
+			t.Errorf("Expected exec.ErrNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("Reports gh failure output", func(t *testing.T) {
+		source := CLITokenSource{Run: func(name string, args ...string) ([]byte, error) {
+			return nil, fmt.Errorf("exit status 1: %w", &exec.ExitError{Stderr: []byte("not logged in")})
+		}}
+
+		_, err := source.Token()
+		if err == nil || !strings.Contains(err.Error(), "not logged in") {
+			t.Errorf("Expected gh stderr in the error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// extractBodyFields extracts the named fields from a pull request body, such
+// as "Risk: high". Each pattern's first capture group is the field's value,
+// or the whole match when it has none. Fields whose pattern does not match
+// are left out.
+func extractBodyFields(body string, patterns map[string]*regexp.Regexp) map[string]string {
+	if len(patterns) == 0 || body == "" {
+		return nil
+	}
+	
+	var fields map[string]string
+	for name, pattern := range patterns {
+		match := pattern.FindStringSubmatch(body)
+		if match == nil {
+			continue
+		}
+		
+		value := match[0]
+		if len(match) > 1 {
+			value = match[1]
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[name] = strings.TrimSpace(value)
+	}
+	return fields
+}
+
+// sortedFieldNames returns the names of extracted fields in alphabetical
+// order, so they render the same way every time
+func sortedFieldNames(fields map[string]string) []string {
+	return slices.Sorted(maps.Keys(fields))
+}
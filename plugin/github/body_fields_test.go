@@ -0,0 +1,43 @@
+package github
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestExtractBodyFields(t *testing.T) {
+	body := "Moves billing to the new queue.\n\nRisk: high\nRollback: yes\n"
+	patterns := map[string]*regexp.Regexp{
+		"Risk":     regexp.MustCompile(`Risk:\s*(\w+)`),
+		"Rollback": regexp.MustCompile(`Rollback:\s*(\w+)`),
+		"Ticket":   regexp.MustCompile(`PROJ-\d+`),
+	}
+
+	fields := extractBodyFields(body, patterns)
+	if len(fields) != 2 || fields["Risk"] != "high" || fields["Rollback"] != "yes" {
+		t.Fatalf("Expected Risk and Rollback to be extracted, got %v", fields)
+	}
+
+	if fields := extractBodyFields("Fixes PROJ-42", patterns); fields["Ticket"] != "PROJ-42" {
+		t.Errorf("Expected the whole match without a capture group, got %v", fields)
+	}
+
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].ExtractedFields = map[string]string{"Rollback": "yes", "Risk": "high"}
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "**Risk:** high · **Rollback:** yes") {
+		t.Errorf("Expected the fields in name order, got:\n%s", content.Content)
+	}
+
+	content, err = NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "<dt>Risk</dt><dd>high</dd>") {
+		t.Errorf("Expected the fields as a definition list, got:\n%s", content.Content)
+	}
+}
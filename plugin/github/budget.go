@@ -0,0 +1,113 @@
+package github
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// pullRequestIndex locates a pull request in a report by the index of its
+// repository and its index within the repository
+type pullRequestIndex struct {
+	repo int
+	pr   int
+}
+
+// FormatWithinBudget formats the report, trimming it when the output would
+// exceed maxBytes. The bodies of commit messages, reviews, and comments are
+// dropped first, and then the oldest pull requests, always keeping the most
+// recent one, so trimming loses detail before it loses whole pull requests.
+// The trimmed report is marked as truncated, so the formatter renders a note
+// and the output stays valid in its format. When even the most trimmed
+// report exceeds the budget it is returned anyway. A maxBytes of zero or
+// less formats the report untrimmed.
+func FormatWithinBudget(formatter ReportFormatter, report *ActivityReport, maxBytes int) (*FormattedContent, error) {
+	content, err := formatter.Format(report)
+	if err != nil || maxBytes <= 0 || len(content.Content) <= maxBytes {
+		return content, err
+	}
+	
+	// Each step strips the bodies and drops one more of the oldest pull
+	// requests, trimming more than the last, so search for the first that
+	// fits
+	oldest := pullRequestsByAge(report)
+	steps := max(len(oldest), 1)
+	var searchErr error
+	dropped := sort.Search(steps, func(i int) bool {
+		content, err := formatter.Format(trimReport(report, oldest[:i]))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return len(content.Content) <= maxBytes
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	
+	return formatter.Format(trimReport(report, oldest[:min(dropped, steps-1)]))
+}
+
+// pullRequestsByAge returns the pull requests of the report, least recently
+// updated first
+func pullRequestsByAge(report *ActivityReport) []pullRequestIndex {
+	var indexes []pullRequestIndex
+	for i, repo := range report.Repositories {
+		for j := range repo.PullRequests {
+			indexes = append(indexes, pullRequestIndex{repo: i, pr: j})
+		}
+	}
+	
+	sort.SliceStable(indexes, func(a, b int) bool {
+		prA := report.Repositories[indexes[a].repo].PullRequests[indexes[a].pr]
+		prB := report.Repositories[indexes[b].repo].PullRequests[indexes[b].pr]
+		return prA.UpdatedAt.Before(prB.UpdatedAt)
+	})
+	return indexes
+}
+
+// trimReport returns a truncated copy of the report without the dropped pull
+// requests and without commit, review, and comment bodies. The report itself
+// is left unchanged.
+func trimReport(report *ActivityReport, dropped []pullRequestIndex) *ActivityReport {
+	isDropped := make(map[pullRequestIndex]bool, len(dropped))
+	for _, index := range dropped {
+		isDropped[index] = true
+	}
+	
+	trimmed := *report
+	trimmed.Truncated = true
+	trimmed.Repositories = make([]Repository, len(report.Repositories))
+	for i, repo := range report.Repositories {
+		prs := make([]PullRequest, 0, len(repo.PullRequests))
+		for j, pr := range repo.PullRequests {
+			if isDropped[pullRequestIndex{repo: i, pr: j}] {
+				continue
+			}
+			prs = append(prs, stripPullRequestBodies(pr))
+		}
+		repo.PullRequests = prs
+		trimmed.Repositories[i] = repo
+	}
+	return &trimmed
+}
+
+// stripPullRequestBodies returns a copy of the pull request with commit
+// messages cut to their subject line and review and comment bodies removed
+func stripPullRequestBodies(pr PullRequest) PullRequest {
+	pr.Commits = slices.Clone(pr.Commits)
+	for i := range pr.Commits {
+		pr.Commits[i].Message, _, _ = strings.Cut(pr.Commits[i].Message, "\n")
+	}
+	
+	pr.Reviews = slices.Clone(pr.Reviews)
+	for i := range pr.Reviews {
+		pr.Reviews[i].Body = ""
+	}
+	
+	pr.Comments = slices.Clone(pr.Comments)
+	for i := range pr.Comments {
+		pr.Comments[i].Body = ""
+	}
+	return pr
+}
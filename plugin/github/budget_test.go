@@ -0,0 +1,128 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createLargeActivityReport builds a report with many pull requests, each
+// with long commit messages and comments, updated a day apart
+func createLargeActivityReport() *ActivityReport {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	body := strings.Repeat("Lorem ipsum dolor sit amet. ", 40)
+
+	prs := make([]PullRequest, 0, 30)
+	for i := 1; i <= 30; i++ {
+		prs = append(prs, PullRequest{
+			Number:     i,
+			Title:      fmt.Sprintf("Change %d", i),
+			URL:        fmt.Sprintf("https://github.com/testorg/testrepo/pull/%d", i),
+			State:      "open",
+			UpdatedAt:  start.Add(time.Duration(i) * 24 * time.Hour),
+			IsAuthored: true,
+			Commits:    []Commit{{SHA: fmt.Sprintf("abc%04d", i), Message: "Subject line\n\n" + body, Timestamp: start}},
+			Comments:   []Comment{{ID: int64(i), Author: "reviewer", Body: body, Timestamp: start}},
+		})
+	}
+
+	return &ActivityReport{
+		TimeRange:    TimeRange{Start: start, End: start.Add(60 * 24 * time.Hour)},
+		User:         User{Username: "testuser"},
+		Repositories: []Repository{{Name: "testrepo", Organization: "testorg", PullRequests: prs}},
+	}
+}
+
+func TestFormatWithinBudget(t *testing.T) {
+	const maxBytes = 4000
+
+	for _, formatter := range []ReportFormatter{NewJSONFormatter(), NewMarkdownFormatter(), NewHTMLFormatter(), NewTeamsFormatter()} {
+		t.Run(formatter.Name(), func(t *testing.T) {
+			report := createLargeActivityReport()
+			full, err := formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+			if len(full.Content) <= maxBytes {
+				t.Fatalf("Expected the full report to exceed %d bytes, got %d", maxBytes, len(full.Content))
+			}
+
+			content, err := FormatWithinBudget(formatter, report, maxBytes)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+			if len(content.Content) > maxBytes {
+				t.Errorf("Expected at most %d bytes, got %d", maxBytes, len(content.Content))
+			}
+			oldestKept := strings.Contains(content.Content, "Change 1 ") ||
+				strings.Contains(content.Content, "Change 1\"") ||
+				strings.Contains(content.Content, "Change 1<") ||
+				strings.Contains(content.Content, "Change 1]")
+			if !strings.Contains(content.Content, "Change 30") || oldestKept {
+				t.Errorf("Expected the oldest pull requests to be dropped first, got:\n%s", content.Content)
+			}
+			if strings.Contains(content.Content, "Lorem ipsum") {
+				t.Errorf("Expected bodies to be stripped before pull requests are dropped, got:\n%s", content.Content)
+			}
+			if len(report.Repositories[0].PullRequests) != 30 {
+				t.Errorf("Expected the report to be left unchanged, got %d pull requests", len(report.Repositories[0].PullRequests))
+			}
+
+			switch formatter.Name() {
+			case "teams":
+				if !json.Valid([]byte(content.Content)) || !strings.Contains(content.Content, "Truncated to fit budget") {
+					t.Errorf("Expected a valid card with a truncation note, got:\n%s", content.Content)
+				}
+			case "json":
+				var decoded ActivityReport
+				if err := json.Unmarshal([]byte(content.Content), &decoded); err != nil {
+					t.Fatalf("Expected valid JSON, got %v", err)
+				}
+				if !decoded.Truncated {
+					t.Error("Expected the JSON report to be marked as truncated")
+				}
+			case "html":
+				if !strings.HasSuffix(content.Content, "</html>") || !strings.Contains(content.Content, "Truncated to fit budget") {
+					t.Errorf("Expected a complete HTML document with a truncation note, got:\n%s", content.Content)
+				}
+			default:
+				if !strings.Contains(content.Content, "Truncated to fit budget") {
+					t.Errorf("Expected a truncation note, got:\n%s", content.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatWithinBudget_StripsBodies(t *testing.T) {
+	report := createLargeActivityReport()
+	report.Repositories[0].PullRequests = report.Repositories[0].PullRequests[29:]
+
+	content, err := FormatWithinBudget(NewMarkdownFormatter(), report, 1500)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(content.Content, "Lorem ipsum") || !strings.Contains(content.Content, "Subject line") {
+		t.Errorf("Expected bodies to be dropped and commit subjects kept, got:\n%s", content.Content)
+	}
+
+	// Stripping the bodies is enough to fit every pull request
+	full := createLargeActivityReport()
+	stripped, err := FormatWithinBudget(NewMarkdownFormatter(), full, 6000)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(stripped.Content, "Lorem ipsum") || !strings.Contains(stripped.Content, "Change 1 ") {
+		t.Errorf("Expected bodies to be dropped while keeping every pull request, got:\n%s", stripped.Content)
+	}
+
+	untrimmed, err := FormatWithinBudget(NewMarkdownFormatter(), report, 0)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(untrimmed.Content, "Lorem ipsum") || strings.Contains(untrimmed.Content, "Truncated") {
+		t.Errorf("Expected no budget to leave the report untrimmed, got:\n%s", untrimmed.Content)
+	}
+}
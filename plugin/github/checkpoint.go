@@ -0,0 +1,123 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// checkpointData is the on-disk representation of a partially completed report
+type checkpointData struct {
+	TimeRange    TimeRange    `json:"time_range"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// checkpoint records completed repositories so an interrupted report can be
+// resumed. It is safe for concurrent use.
+type checkpoint struct {
+	mu   sync.Mutex
+	path string
+	data checkpointData
+}
+
+// loadCheckpoint opens the checkpoint at path for the given time range. A
+// checkpoint written for a different time range is stale and is discarded.
+func loadCheckpoint(path string, timeRange TimeRange) (*checkpoint, error) {
+	cp := &checkpoint{
+		path: path,
+		data: checkpointData{TimeRange: timeRange},
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var data checkpointData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+
+	if data.TimeRange.Start.Equal(timeRange.Start) && data.TimeRange.End.Equal(timeRange.End) {
+		cp.data.Repositories = data.Repositories
+	}
+
+	return cp, nil
+}
+
+// completed returns the repositories already recorded in the checkpoint
+func (c *checkpoint) completed() []Repository {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Repository(nil), c.data.Repositories...)
+}
+
+// isCompleted reports whether the named repository is already recorded.
+// Names match ignoring case, as recorded names may be canonicalized.
+func (c *checkpoint) isCompleted(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, repo := range c.data.Repositories {
+		if strings.EqualFold(repo.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// record adds a completed repository and persists the checkpoint
+func (c *checkpoint) record(repo Repository) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data.Repositories = append(c.data.Repositories, repo)
+	return c.save()
+}
+
+// save writes the checkpoint atomically so an interruption mid-write cannot
+// corrupt it. Callers must hold the lock.
+func (c *checkpoint) save() error {
+	content, err := json.Marshal(c.data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// remove deletes the checkpoint once the report has fully completed
+func (c *checkpoint) remove() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	timeRange := TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	// A missing checkpoint starts empty
+	cp, err := loadCheckpoint(path, timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(cp.completed()) != 0 {
+		t.Fatalf("Expected empty checkpoint, got %d repositories", len(cp.completed()))
+	}
+
+	if err := cp.record(Repository{Name: "repo1", Organization: "testorg"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// The same time range resumes the recorded repositories
+	cp, err = loadCheckpoint(path, timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !cp.isCompleted("repo1") {
+		t.Errorf("Expected repo1 to be recorded as completed")
+	}
+
+	// A different time range makes the checkpoint stale
+	staleRange := TimeRange{Start: timeRange.Start.AddDate(0, 0, 1), End: timeRange.End.AddDate(0, 0, 1)}
+	cp, err = loadCheckpoint(path, staleRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if cp.isCompleted("repo1") {
+		t.Errorf("Expected stale checkpoint to be discarded")
+	}
+}
@@ -1,10 +1,13 @@
 package github
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	externalGithub "github.com/google/go-github/v68/github"
 	plug "github.com/iures/daivplug"
@@ -17,6 +20,106 @@ type GitHubConfig struct {
 	Organization string
 	Repositories []string
 	QueryOptions QueryOptions
+	UserAgent    string
+	
+	// CustomQuery, when set, is a raw search query run instead of the
+	// built-in per-repository queries, so Organization, Repositories, and
+	// the base branch are not used
+	CustomQuery string
+	
+	// PullRequestURL, when set, reports on this single pull request instead
+	// of searching, keeping it even without activity in the time range
+	PullRequestURL string
+	
+	// CheckpointFile, when set, records completed repositories so an
+	// interrupted report can be resumed
+	CheckpointFile string
+	
+	// Resume skips repositories already recorded in CheckpointFile for the
+	// same time range
+	Resume bool
+	
+	// FastFail aborts the report on the first repository or enrichment
+	// error instead of skipping the failure and continuing
+	FastFail bool
+	
+	// DebugDumpDir, when set, receives the raw body of every search, commits,
+	// comments, and reviews API response
+	DebugDumpDir string
+	
+	// AuthoredActivity and ReviewedActivity define what counts as reportable
+	// activity for authored and reviewed pull requests. The zero value
+	// reports every pull request the search matches.
+	AuthoredActivity ActivityCriteria
+	ReviewedActivity ActivityCriteria
+	
+	// RateLimit caps the average number of API requests per second, with
+	// bursts of up to RateLimitBurst requests. Zero is unlimited.
+	RateLimit      float64
+	RateLimitBurst int
+	
+	// RateLimitStrategy handles the primary rate limit running out mid-run:
+	// RateLimitStrategyWait sleeps until it resets and retries, and
+	// RateLimitStrategyFail fails the request with a RateLimitExhaustedError.
+	// Empty leaves go-github to report it. FastFail turns waiting into
+	// failing.
+	RateLimitStrategy string
+	
+	// Middleware decorates the client's transport, applied in order after
+	// the built-in debug dump and rate limit middleware, so the last one
+	// sees each request first
+	Middleware []Middleware
+	
+	// MinActivityItems replaces reports with fewer commits, reviews, and
+	// comments than this with a short quiet period message. Zero disables it.
+	MinActivityItems int
+	
+	// OnlyOpen drops merged and closed pull requests from the report,
+	// leaving the work still in flight
+	OnlyOpen bool
+	
+	// Explain attaches the reason each pull request was included, the
+	// searches it matched and its activity, for rendering as an annotation
+	Explain bool
+	
+	// ArchivedRepos checks each repository for being archived or disabled,
+	// costing one cached API call per repository. ArchivedReposSkip skips
+	// them with a note and ArchivedReposTag includes them with a tag. Empty
+	// disables the check.
+	ArchivedRepos string
+	
+	// CanonicalRepoNames resolves each configured repository name to its
+	// canonical casing, so a configured MyRepo is searched and reported as
+	// myrepo. Costs one cached API call per repository, shared with the
+	// ArchivedRepos check.
+	CanonicalRepoNames bool
+	
+	// WorkingHours, when set, filters or tags the commits, reviews, and
+	// comments made outside working hours
+	WorkingHours WorkingHours
+	
+	// PostMerge, when set, treats the reviews and comments made after a
+	// pull request was merged: PostMergeTag marks them as post-merge and
+	// PostMergeFilter leaves them out. Empty keeps them unmarked.
+	PostMerge string
+	
+	// CacheDir, when set, caches search and enrichment results on disk for
+	// CacheTTL, so reports run again soon after reuse them. Zero TTL uses
+	// DefaultCacheTTL.
+	CacheDir string
+	CacheTTL time.Duration
+	
+	// TotalBudget caps how long a report may take. Once it runs out, the
+	// remaining repositories are skipped with ErrBudgetExceeded and the
+	// report holds the repositories completed so far. Pull request URL and
+	// custom query reports fail with ErrBudgetExceeded. Zero is unlimited.
+	TotalBudget time.Duration
+	
+	// OnProgress, when set, is called as each repository of a report
+	// completes, successfully or not, with the number of repositories done
+	// so far and the total. Calls are never concurrent, and done increases
+	// by one on each call.
+	OnProgress func(done, total int)
 }
 
 // GitHubClient provides a client for interacting with GitHub
@@ -33,7 +136,26 @@ func NewGitHubClient(config *GitHubConfig) (*GitHubClient, error) {
 		Password: config.Token,
 	}
 	
-	client := externalGithub.NewClient(authToken.Client())
+	var middleware []Middleware
+	if config.DebugDumpDir != "" {
+		middleware = append(middleware, dumpMiddleware(config.DebugDumpDir))
+	}
+	if strategy := rateLimitStrategyFor(config); strategy != "" {
+		middleware = append(middleware, rateLimitStrategyMiddleware(strategy))
+	}
+	if config.RateLimit > 0 {
+		middleware = append(middleware, rateLimitMiddleware(config.RateLimit, config.RateLimitBurst))
+	}
+	middleware = append(middleware, config.Middleware...)
+	
+	httpClient := authToken.Client()
+	httpClient.Transport = chainMiddleware(httpClient.Transport, middleware...)
+	
+	client := externalGithub.NewClient(httpClient)
+	client.UserAgent = DefaultUserAgent
+	if config.UserAgent != "" {
+		client.UserAgent = config.UserAgent
+	}
 	
 	githubClient := &GitHubClient{
 		client: client,
@@ -42,7 +164,11 @@ func NewGitHubClient(config *GitHubConfig) (*GitHubClient, error) {
 	
 	// Create the repository
 	repository := NewGitHubAPIRepository(client, config.Username)
+	repository.rateLimitStrategy = rateLimitStrategyFor(config)
 	githubClient.repository = repository
+	if config.CacheDir != "" {
+		githubClient.repository = newCachingRepository(repository, config.Username, config.CacheDir, config.CacheTTL)
+	}
 	
 	return githubClient, nil
 }
@@ -52,11 +178,59 @@ func (g *GitHubClient) GetRepository() GitHubRepository {
 	return g.repository
 }
 
+// HealthStatus describes the result of a connectivity check
+type HealthStatus struct {
+	Login              string
+	RateLimit          int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+	BaseURL            string
+}
+
+// Ping verifies authentication and connectivity by fetching the
+// authenticated user, and reports the current rate limit and API endpoint
+func (g *GitHubClient) Ping(ctx context.Context) (*HealthStatus, error) {
+	user, resp, err := g.client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authenticated user: %w", wrapAPIError(err))
+	}
+	
+	return &HealthStatus{
+		Login:              user.GetLogin(),
+		RateLimit:          resp.Rate.Limit,
+		RateLimitRemaining: resp.Rate.Remaining,
+		RateLimitReset:     resp.Rate.Reset.Time,
+		BaseURL:            g.client.BaseURL.String(),
+	}, nil
+}
+
 type GithubClientSettings struct {
 	Username string
 	Token string
 	Org string
 	Repos []string
+	// LinkCommits renders commits with a short SHA linking to GitHub
+	LinkCommits bool
+	// MaxConcurrency bounds how many repositories, and how many reviewed
+	// pull requests across all of them, are rendered at once. Zero uses
+	// defaultMaxConcurrency; one renders sequentially.
+	MaxConcurrency int
+	// UseAuthorDate places commits in the time range by their author date
+	// instead of their committer date
+	UseAuthorDate bool
+	// ReviewStates limits the reviews rendered to these states. Empty
+	// renders reviews in every state.
+	ReviewStates []string
+}
+
+// defaultMaxConcurrency is the number of concurrent API workers used when
+// no explicit limit is configured
+const defaultMaxConcurrency = 4
+
+// reviewedSection is the rendered output for a single reviewed pull request
+type reviewedSection struct {
+	number  int
+	content string
 }
 
 type GithubClient struct {
@@ -80,65 +254,163 @@ func (gc *GithubClient) Init(settings GithubClientSettings) {
 }
 
 func (gc *GithubClient) GetStandupContext(timeRange plug.TimeRange) (string, error) {
-	var report strings.Builder
-
-	for _, repo := range gc.Settings.Repos {
-		repoHasContent := false
-		repoSection := &strings.Builder{}
-		fmt.Fprintf(repoSection, "\n# Repository: %s\n", repo)
+	// Repositories are rendered by a bounded pool of workers into their
+	// slot, so the report keeps the configured repository order
+	sections := make([]string, len(gc.Settings.Repos))
+	errs := make([]error, len(gc.Settings.Repos))
+
+	// Reviewed pull requests of every repository share one pool, so the
+	// pools do not multiply
+	reviewSem := make(chan struct{}, gc.maxConcurrency())
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, gc.maxConcurrency())
+	for i, repo := range gc.Settings.Repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repo string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sections[i], errs[i] = gc.renderRepository(repo, timeRange, reviewSem)
+		}(i, repo)
+	}
+	wg.Wait()
 
-		authoredPRs, err := gc.renderAuthoredPullRequestCommits(repo, timeRange)
+	for _, err := range errs {
 		if err != nil {
-			return "", fmt.Errorf("error rendering authored pull request commits for %s/%s: %v", gc.Settings.Org, repo, err)
-		}
-		if authoredPRs != "" {
-			repoHasContent = true
-			repoSection.WriteString(authoredPRs)
+			return "", err
 		}
+	}
 
-		issuesReviewed, err := gc.searchReviewedPullRequests(repo, timeRange)
-		if err != nil {
-			return "", fmt.Errorf("error searching reviewed PRs for %s/%s: %v", gc.Settings.Org, repo, err)
+	var report strings.Builder
+	for _, section := range sections {
+		report.WriteString(section)
+	}
+
+	if report.Len() == 0 {
+		report.WriteString("\nNo GitHub activity found in the specified time period.\n")
+	}
+
+	return report.String(), nil
+}
+
+// renderRepository renders the authored and reviewed pull requests of a
+// repository, returning an empty section if it has neither. Reviewed pull
+// requests are rendered by the workers of reviewSem.
+func (gc *GithubClient) renderRepository(repo string, timeRange plug.TimeRange, reviewSem chan struct{}) (string, error) {
+	repoHasContent := false
+	repoSection := &strings.Builder{}
+	fmt.Fprintf(repoSection, "\n# Repository: %s\n", repo)
+
+	authoredPRs, err := gc.renderAuthoredPullRequestCommits(repo, timeRange)
+	if err != nil {
+		return "", fmt.Errorf("error rendering authored pull request commits for %s/%s: %v", gc.Settings.Org, repo, err)
+	}
+	if authoredPRs != "" {
+		repoHasContent = true
+		repoSection.WriteString(authoredPRs)
+	}
+
+	issuesReviewed, err := gc.searchReviewedPullRequests(repo, timeRange)
+	if err != nil {
+		return "", fmt.Errorf("error searching reviewed PRs for %s/%s: %v", gc.Settings.Org, repo, err)
+	}
+
+	reviewedSections, err := gc.renderReviewedPullRequests(repo, issuesReviewed, timeRange, reviewSem)
+	if err != nil {
+		return "", err
+	}
+
+	// Reviewed PRs without in-range reviews render nothing, so the
+	// heading is only written when at least one section remains
+	if len(reviewedSections) > 0 {
+		repoHasContent = true
+		repoSection.WriteString("\n## Reviewed Pull Requests\n")
+
+		for _, section := range reviewedSections {
+			repoSection.WriteString(section.content)
 		}
+	}
 
-		if len(issuesReviewed) > 0 {
-			repoHasContent = true
-			repoSection.WriteString("\n## Reviewed Pull Requests\n")
-			
-			var hasReviewsInPeriod bool
-			for _, issue := range issuesReviewed {
-				reviewReport, err := gc.renderReviews(repo, issue, timeRange)
-				if err != nil {
-					return "", fmt.Errorf("error fetching reviews for PR #%d in %s/%s: %v", issue.GetNumber(), gc.Settings.Org, repo, err)
-				}
-				if reviewReport != "" {
-					hasReviewsInPeriod = true
-					fmt.Fprintln(repoSection, formatPullRequestFromIssue(issue))
-					repoSection.WriteString(reviewReport)
-
-					reviewCommentReport, err := gc.renderPrComments(repo, issue.GetNumber(), timeRange)
-					if err != nil {
-						return "", fmt.Errorf("error fetching comments for PR #%d in %s/%s: %v", issue.GetNumber(), gc.Settings.Org, repo, err)
-					}
-					repoSection.WriteString(reviewCommentReport)
-				}
-			}
+	if !repoHasContent {
+		return "", nil
+	}
+	return repoSection.String(), nil
+}
 
-			if !hasReviewsInPeriod {
-				repoSection.WriteString("No reviews found in the specified time period.\n")
-			}
+// renderReviewedPullRequests renders the reviews and comments of each reviewed
+// pull request using the bounded pool of workers of sem. Pull requests
+// without reviews in the time range are dropped, and the remaining sections
+// are sorted by PR number so the output does not depend on scheduling.
+func (gc *GithubClient) renderReviewedPullRequests(repo string, issues []*externalGithub.Issue, timeRange plug.TimeRange, sem chan struct{}) ([]reviewedSection, error) {
+	sections := make([]reviewedSection, len(issues))
+	errs := make([]error, len(issues))
+
+	var wg sync.WaitGroup
+	for i, issue := range issues {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, issue *externalGithub.Issue) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			sections[i], errs[i] = gc.renderReviewedPullRequest(repo, issue, timeRange)
+		}(i, issue)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if repoHasContent {
-			report.WriteString(repoSection.String())
+	rendered := make([]reviewedSection, 0, len(sections))
+	for _, section := range sections {
+		if section.content != "" {
+			rendered = append(rendered, section)
 		}
 	}
 
-	if report.Len() == 0 {
-		report.WriteString("\nNo GitHub activity found in the specified time period.\n")
+	slices.SortFunc(rendered, func(a, b reviewedSection) int {
+		return a.number - b.number
+	})
+
+	return rendered, nil
+}
+
+// renderReviewedPullRequest renders a single reviewed pull request, returning
+// an empty section if the user has no reviews on it in the time range
+func (gc *GithubClient) renderReviewedPullRequest(repo string, issue *externalGithub.Issue, timeRange plug.TimeRange) (reviewedSection, error) {
+	section := reviewedSection{number: issue.GetNumber()}
+
+	reviewReport, err := gc.renderReviews(repo, issue, timeRange)
+	if err != nil {
+		return section, fmt.Errorf("error fetching reviews for PR #%d in %s/%s: %v", issue.GetNumber(), gc.Settings.Org, repo, err)
+	}
+	if reviewReport == "" {
+		return section, nil
 	}
 
-	return report.String(), nil
+	reviewCommentReport, err := gc.renderPrComments(repo, issue.GetNumber(), timeRange)
+	if err != nil {
+		return section, fmt.Errorf("error fetching comments for PR #%d in %s/%s: %v", issue.GetNumber(), gc.Settings.Org, repo, err)
+	}
+
+	var content strings.Builder
+	fmt.Fprintln(&content, formatPullRequestFromIssue(issue))
+	content.WriteString(reviewReport)
+	content.WriteString(reviewCommentReport)
+	section.content = content.String()
+
+	return section, nil
+}
+
+// maxConcurrency returns the configured worker limit or the default
+func (gc *GithubClient) maxConcurrency() int {
+	if gc.Settings.MaxConcurrency > 0 {
+		return gc.Settings.MaxConcurrency
+	}
+	return defaultMaxConcurrency
 }
 
 func (gc *GithubClient) renderAuthoredPullRequestCommits(repo string, timeRange plug.TimeRange) (string, error) {
@@ -247,15 +519,15 @@ func (gc *GithubClient) renderCommits(repo string, prNumber int, timeRange plug.
 	}
 
 	slices.SortFunc(prCommits, func(a, b *externalGithub.RepositoryCommit) int {
-		return a.GetCommit().GetCommitter().GetDate().Compare(b.GetCommit().GetCommitter().GetDate().Time)
+		return commitDate(a, gc.Settings.UseAuthorDate).Compare(commitDate(b, gc.Settings.UseAuthorDate))
 	})
 
 	var commitReport strings.Builder
-	relevantCommits := filterRelevantCommits(prCommits, gc.Settings.Username, timeRange)
+	relevantCommits := filterRelevantCommits(prCommits, gc.Settings.Username, timeRange, gc.Settings.UseAuthorDate)
 	if len(relevantCommits) > 0 {
 		commitReport.WriteString("#### Commits:\n")
 		for _, commit := range relevantCommits {
-			commitReport.WriteString(formatCommit(commit))
+			commitReport.WriteString(formatCommit(gc.Settings.Org, repo, commit, gc.Settings.LinkCommits))
 		}
 	}
 
@@ -265,7 +537,7 @@ func (gc *GithubClient) renderCommits(repo string, prNumber int, timeRange plug.
 func (gc *GithubClient) renderPrComments(repo string, prNumber int, timeRange plug.TimeRange) (string, error) {
 	ctx := context.Background()
 
-	comments, _, err := gc.Client.PullRequests.ListComments(ctx, gc.Settings.Org, repo, prNumber, nil)
+	comments, err := listPullRequestComments(ctx, gc.Client.PullRequests, gc.Settings.Org, repo, prNumber, timeRange.Start)
 	if err != nil {
 		return "", err
 	}
@@ -273,6 +545,13 @@ func (gc *GithubClient) renderPrComments(repo string, prNumber int, timeRange pl
 	var commentReport strings.Builder
 	relevantComments := filterRelevantPRComments(comments, gc.Settings.Username, timeRange)
 	if len(relevantComments) > 0 {
+		slices.SortStableFunc(relevantComments, func(a, b *externalGithub.PullRequestComment) int {
+			if c := a.GetCreatedAt().Compare(b.GetCreatedAt().Time); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.GetID(), b.GetID())
+		})
+
 		commentReport.WriteString("### Comments:\n")
 		for _, comment := range relevantComments {
 			commentReport.WriteString(formatComment(comment))
@@ -293,11 +572,11 @@ func filterRelevantPRComments(comments []*externalGithub.PullRequestComment, use
 	return relevant
 }
 
-func filterRelevantCommits(commits []*externalGithub.RepositoryCommit, username string, timeRange plug.TimeRange) []*externalGithub.RepositoryCommit {
+func filterRelevantCommits(commits []*externalGithub.RepositoryCommit, username string, timeRange plug.TimeRange, useAuthorDate bool) []*externalGithub.RepositoryCommit {
 	var relevant []*externalGithub.RepositoryCommit
 	for _, commit := range commits {
 		if commit.Author != nil && commit.Author.GetLogin() == username &&
-			timeRange.IsInRange(commit.GetCommit().GetCommitter().GetDate().Time) {
+			timeRange.IsInRange(commitDate(commit, useAuthorDate)) {
 			relevant = append(relevant, commit)
 		}
 	}
@@ -312,7 +591,16 @@ func formatPullRequestFromIssue(issue *externalGithub.Issue) string {
 	)
 }
 
-func formatCommit(commit *externalGithub.RepositoryCommit) string {
+func formatCommit(org string, repo string, commit *externalGithub.RepositoryCommit, linkCommits bool) string {
+	if linkCommits && commit.GetSHA() != "" {
+		return fmt.Sprintf(
+			"##### [`%s`](%s) %s\n\n",
+			shortSHA(commit.GetSHA()),
+			commitURL(Repository{Organization: org, Name: repo}, commit.GetSHA()),
+			commit.GetCommit().GetMessage(),
+		)
+	}
+
 	return fmt.Sprintf(
 		"##### %s\n\n",
 		commit.GetCommit().GetMessage(),
@@ -341,7 +629,7 @@ func (gc *GithubClient) renderReviews(repo string, issue *externalGithub.Issue,
 
 	// First collect all relevant reviews
 	for _, review := range reviews {
-		if review.User != nil && review.User.GetLogin() == gc.Settings.Username  {
+		if review.User != nil && review.User.GetLogin() == gc.Settings.Username && reviewStateCounts(review.GetState(), gc.Settings.ReviewStates) {
 			if review.GetSubmittedAt().IsZero() || !timeRange.IsInRange(review.GetSubmittedAt().Time) {
 				continue
 			}
@@ -350,8 +638,11 @@ func (gc *GithubClient) renderReviews(repo string, issue *externalGithub.Issue,
 	}
 
 	if len(relevantReviews) > 0 {
-		slices.SortFunc(relevantReviews, func(a, b *externalGithub.PullRequestReview) int {
-			return a.GetSubmittedAt().Compare(b.GetSubmittedAt().Time)
+		slices.SortStableFunc(relevantReviews, func(a, b *externalGithub.PullRequestReview) int {
+			if c := a.GetSubmittedAt().Compare(b.GetSubmittedAt().Time); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.GetID(), b.GetID())
 		})
 
 		for _, review := range relevantReviews {
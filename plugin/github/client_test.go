@@ -0,0 +1,332 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	externalGithub "github.com/google/go-github/v68/github"
+	plug "github.com/iures/daivplug"
+)
+
+// newTestGithubClient creates a legacy GithubClient pointed at a local test server
+func newTestGithubClient(t *testing.T, settings GithubClientSettings) (*GithubClient, *http.ServeMux) {
+	t.Helper()
+
+	client, mux := newTestClient(t)
+	return &GithubClient{Client: client, Settings: settings}, mux
+}
+
+// testPluginTimeRange returns the plugin time range used by the client tests
+func testPluginTimeRange() plug.TimeRange {
+	return plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// handleReviewedSearch serves an empty authored search and the given PR
+// numbers for the reviewed search
+func handleReviewedSearch(t *testing.T, mux *http.ServeMux, numbers ...int) {
+	t.Helper()
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "reviewed-by:") {
+			writeJSON(t, w, searchResult(numbers...))
+			return
+		}
+		writeJSON(t, w, searchResult())
+	})
+}
+
+func TestGithubClient_ReviewedOutputIsDeterministic(t *testing.T) {
+	render := func(maxConcurrency int) string {
+		gc, mux := newTestGithubClient(t, GithubClientSettings{
+			Username:       "testuser",
+			Org:            "testorg",
+			Repos:          []string{"repo1"},
+			MaxConcurrency: maxConcurrency,
+		})
+
+		numbers := []int{5, 2, 9, 1, 7, 3}
+		handleReviewedSearch(t, mux, numbers...)
+
+		for _, number := range numbers {
+			mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d/reviews", number), func(w http.ResponseWriter, r *http.Request) {
+				// Stagger responses so concurrent workers finish out of order
+				time.Sleep(time.Duration(10-number) * time.Millisecond)
+				writeJSON(t, w, []map[string]any{
+					{
+						"id":           number,
+						"user":         map[string]any{"login": "testuser"},
+						"state":        "APPROVED",
+						"body":         fmt.Sprintf("Looks good %d", number),
+						"submitted_at": "2023-01-01T12:00:00Z",
+					},
+				})
+			})
+			mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d/comments", number), func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, []map[string]any{
+					{
+						"id":         number,
+						"user":       map[string]any{"login": "testuser"},
+						"body":       fmt.Sprintf("Comment %d", number),
+						"created_at": "2023-01-01T13:00:00Z",
+					},
+				})
+			})
+		}
+
+		output, err := gc.GetStandupContext(testPluginTimeRange())
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		return output
+	}
+
+	sequential := render(1)
+	concurrent := render(4)
+
+	if sequential != concurrent {
+		t.Errorf("Expected concurrent output to match sequential output.\nSequential:\n%s\nConcurrent:\n%s", sequential, concurrent)
+	}
+
+	// Sections are ordered by PR number
+	lastIndex := -1
+	for _, number := range []int{1, 2, 3, 5, 7, 9} {
+		index := strings.Index(concurrent, fmt.Sprintf("#%d: PR %d", number, number))
+		if index == -1 {
+			t.Fatalf("Expected PR #%d in output", number)
+		}
+		if index < lastIndex {
+			t.Errorf("Expected PR #%d to appear after the previous PR", number)
+		}
+		lastIndex = index
+	}
+}
+
+func TestGithubClient_RepositoriesRenderedConcurrentlyInOrder(t *testing.T) {
+	repos := []string{"repo1", "repo2", "repo3", "repo4"}
+
+	render := func(maxConcurrency int) string {
+		gc, mux := newTestGithubClient(t, GithubClientSettings{
+			Username:       "testuser",
+			Org:            "testorg",
+			Repos:          repos,
+			MaxConcurrency: maxConcurrency,
+		})
+
+		// Each repository has a single reviewed PR numbered after it
+		mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query().Get("q")
+			for i, repo := range repos {
+				if strings.Contains(query, "reviewed-by:") && strings.Contains(query, "repo:testorg/"+repo) {
+					writeJSON(t, w, searchResult((i+1)*10))
+					return
+				}
+			}
+			writeJSON(t, w, searchResult())
+		})
+
+		for i, repo := range repos {
+			number := (i + 1) * 10
+			mux.HandleFunc(fmt.Sprintf("/repos/testorg/%s/pulls/%d/reviews", repo, number), func(w http.ResponseWriter, r *http.Request) {
+				// Later repositories respond first, so concurrent workers
+				// finish out of order
+				time.Sleep(time.Duration(len(repos)-i) * 5 * time.Millisecond)
+				writeJSON(t, w, []map[string]any{
+					{
+						"id":           number,
+						"user":         map[string]any{"login": "testuser"},
+						"state":        "APPROVED",
+						"body":         fmt.Sprintf("Looks good %d", number),
+						"submitted_at": "2023-01-01T12:00:00Z",
+					},
+				})
+			})
+			mux.HandleFunc(fmt.Sprintf("/repos/testorg/%s/pulls/%d/comments", repo, number), func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, []map[string]any{})
+			})
+		}
+
+		output, err := gc.GetStandupContext(testPluginTimeRange())
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		return output
+	}
+
+	sequential := render(1)
+	concurrent := render(4)
+
+	if sequential != concurrent {
+		t.Errorf("Expected concurrent output to match sequential output.\nSequential:\n%s\nConcurrent:\n%s", sequential, concurrent)
+	}
+
+	// Repositories keep their configured order
+	lastIndex := -1
+	for _, repo := range repos {
+		index := strings.Index(concurrent, "# Repository: "+repo+"\n")
+		if index == -1 {
+			t.Fatalf("Expected %s in output:\n%s", repo, concurrent)
+		}
+		if index < lastIndex {
+			t.Errorf("Expected %s to appear after the previous repository", repo)
+		}
+		lastIndex = index
+	}
+}
+
+func TestGithubClient_SkipsCommentsWithoutReviews(t *testing.T) {
+	gc, mux := newTestGithubClient(t, GithubClientSettings{
+		Username: "testuser",
+		Org:      "testorg",
+		Repos:    []string{"repo1"},
+	})
+
+	handleReviewedSearch(t, mux, 1)
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "state": "APPROVED", "submitted_at": "2022-12-01T12:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no comment fetch for a reviewed PR without in-range reviews")
+		writeJSON(t, w, []map[string]any{})
+	})
+
+	if _, err := gc.GetStandupContext(testPluginTimeRange()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+}
+
+func TestGithubClient_OmitsEmptyReviewedSection(t *testing.T) {
+	gc, mux := newTestGithubClient(t, GithubClientSettings{
+		Username: "testuser",
+		Org:      "testorg",
+		Repos:    []string{"repo1"},
+	})
+
+	handleReviewedSearch(t, mux, 1)
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "state": "APPROVED", "submitted_at": "2022-12-01T12:00:00Z"},
+		})
+	})
+
+	output, err := gc.GetStandupContext(testPluginTimeRange())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if strings.Contains(output, "No reviews found") {
+		t.Errorf("Expected no placeholder line, got:\n%s", output)
+	}
+	if strings.Contains(output, "## Reviewed Pull Requests") {
+		t.Errorf("Expected no reviewed section without in-range reviews, got:\n%s", output)
+	}
+	if !strings.Contains(output, "No GitHub activity found") {
+		t.Errorf("Expected the empty report message, got:\n%s", output)
+	}
+}
+
+func TestNewGitHubClient_UserAgent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		expected  string
+	}{
+		{
+			name:     "Default user agent",
+			expected: DefaultUserAgent,
+		},
+		{
+			name:      "Configured user agent",
+			userAgent: "acme-standup/1.2",
+			expected:  "acme-standup/1.2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := NewGitHubClient(&GitHubConfig{
+				Username:  "testuser",
+				Token:     "testtoken",
+				UserAgent: tc.userAgent,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			if client.client.UserAgent != tc.expected {
+				t.Errorf("Expected user agent %q, got %q", tc.expected, client.client.UserAgent)
+			}
+		})
+	}
+}
+
+func TestFormatCommit_LinkCommits(t *testing.T) {
+	sha := "1234567890abcdef1234567890abcdef12345678"
+	message := "Fix the thing"
+	commit := &externalGithub.RepositoryCommit{
+		SHA:    &sha,
+		Commit: &externalGithub.Commit{Message: &message},
+	}
+
+	linked := formatCommit("testorg", "repo1", commit, true)
+	expected := "[`1234567`](https://github.com/testorg/repo1/commit/" + sha + ") Fix the thing"
+	if !strings.Contains(linked, expected) {
+		t.Errorf("Expected %q in %q", expected, linked)
+	}
+
+	plain := formatCommit("testorg", "repo1", commit, false)
+	if plain != "##### Fix the thing\n\n" {
+		t.Errorf("Expected plain commit rendering, got %q", plain)
+	}
+}
+
+func TestGitHubClient_Ping(t *testing.T) {
+	client, mux := newTestClient(t)
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4987")
+		w.Header().Set("X-RateLimit-Reset", "1672574400")
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	githubClient := &GitHubClient{client: client, config: &GitHubConfig{Username: "testuser"}}
+	status, err := githubClient.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if status.Login != "testuser" {
+		t.Errorf("Expected login 'testuser', got '%s'", status.Login)
+	}
+	if status.RateLimit != 5000 || status.RateLimitRemaining != 4987 {
+		t.Errorf("Expected rate limit 4987/5000, got %d/%d", status.RateLimitRemaining, status.RateLimit)
+	}
+	if !status.RateLimitReset.Equal(time.Unix(1672574400, 0)) {
+		t.Errorf("Expected rate limit reset %v, got %v", time.Unix(1672574400, 0), status.RateLimitReset)
+	}
+	if status.BaseURL != client.BaseURL.String() {
+		t.Errorf("Expected base URL %s, got %s", client.BaseURL, status.BaseURL)
+	}
+}
+
+func TestGitHubClient_PingUnauthorized(t *testing.T) {
+	client, mux := newTestClient(t)
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "Bad credentials"}`))
+	})
+
+	githubClient := &GitHubClient{client: client, config: &GitHubConfig{Username: "testuser"}}
+	if _, err := githubClient.Ping(context.Background()); !errors.Is(err, ErrAuth) {
+		t.Errorf("Expected ErrAuth, got: %v", err)
+	}
+}
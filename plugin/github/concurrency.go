@@ -0,0 +1,42 @@
+package github
+
+import "context"
+
+// concurrencyLimiter bounds how many enrichment API calls run at once. One
+// limiter is shared by a whole report, so the bound holds however many
+// repositories and pull requests are enriched concurrently.
+type concurrencyLimiter chan struct{}
+
+// newConcurrencyLimiter creates a limiter allowing n concurrent calls. Zero
+// uses defaultMaxConcurrency.
+func newConcurrencyLimiter(n int) concurrencyLimiter {
+	if n <= 0 {
+		n = defaultMaxConcurrency
+	}
+	return make(concurrencyLimiter, n)
+}
+
+// do runs fn once a slot is free
+func (l concurrencyLimiter) do(fn func()) {
+	l <- struct{}{}
+	defer func() { <-l }()
+	fn()
+}
+
+// concurrencyLimiterKey is the context key of a report's limiter
+type concurrencyLimiterKey struct{}
+
+// withConcurrencyLimiter returns a context carrying a limiter of n concurrent
+// calls, shared by every enrichment made with it
+func withConcurrencyLimiter(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, concurrencyLimiterKey{}, newConcurrencyLimiter(n))
+}
+
+// concurrencyLimiterFrom returns the limiter carried by ctx, or a new limiter
+// of n concurrent calls when there is none
+func concurrencyLimiterFrom(ctx context.Context, n int) concurrencyLimiter {
+	if limiter, ok := ctx.Value(concurrencyLimiterKey{}).(concurrencyLimiter); ok {
+		return limiter
+	}
+	return newConcurrencyLimiter(n)
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// conventionalCommitPattern matches the type(scope): prefix of a
+// conventional commit subject, with an optional ! marking a breaking change
+var conventionalCommitPattern = regexp.MustCompile(`^([a-zA-Z]+)(\([^()]*\))?!?: \S`)
+
+// ConventionalTypeOther is the type of commits that do not follow the
+// conventional commit format
+const ConventionalTypeOther = "other"
+
+// conventionalCommitType returns the lowercased type of a conventional commit
+// message, such as "feat" for "feat(api): add endpoint", or
+// ConventionalTypeOther when the message does not follow the convention
+func conventionalCommitType(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	match := conventionalCommitPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil {
+		return ConventionalTypeOther
+	}
+	return strings.ToLower(match[1])
+}
+
+// commitTypeCount is the number of commits of one conventional type
+type commitTypeCount struct {
+	Type  string
+	Count int
+}
+
+// tallyCommitTypes counts commits by conventional type, most frequent first
+// and alphabetically among equal counts. Commits without a type count as
+// ConventionalTypeOther.
+func tallyCommitTypes(commits []Commit) []commitTypeCount {
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		commitType := commit.ConventionalType
+		if commitType == "" {
+			commitType = ConventionalTypeOther
+		}
+		counts[commitType]++
+	}
+
+	tally := make([]commitTypeCount, 0, len(counts))
+	for commitType, count := range counts {
+		tally = append(tally, commitTypeCount{Type: commitType, Count: count})
+	}
+	sort.Slice(tally, func(i, j int) bool {
+		if tally[i].Count != tally[j].Count {
+			return tally[i].Count > tally[j].Count
+		}
+		return tally[i].Type < tally[j].Type
+	})
+	return tally
+}
+
+// formatCommitTypeTally renders a tally as "feat: 3, fix: 2, other: 1"
+func formatCommitTypeTally(tally []commitTypeCount) string {
+	parts := make([]string, 0, len(tally))
+	for _, entry := range tally {
+		parts = append(parts, fmt.Sprintf("%s: %d", entry.Type, entry.Count))
+	}
+	return strings.Join(parts, ", ")
+}
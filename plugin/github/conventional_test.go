@@ -0,0 +1,67 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConventionalCommitType(t *testing.T) {
+	testCases := []struct {
+		message  string
+		expected string
+	}{
+		{"feat: add endpoint", "feat"},
+		{"fix(api): handle nil user", "fix"},
+		{"Refactor!: drop legacy flag\n\nBREAKING CHANGE: removed", "refactor"},
+		{"Update README", ConventionalTypeOther},
+		{"feat:missing space", ConventionalTypeOther},
+		{"Merge branch 'main' into feature", ConventionalTypeOther},
+	}
+
+	for _, tc := range testCases {
+		if result := conventionalCommitType(tc.message); result != tc.expected {
+			t.Errorf("Expected type %q for %q, got %q", tc.expected, tc.message, result)
+		}
+	}
+}
+
+func TestFormatters_CommitTypeTally(t *testing.T) {
+	messages := []string{
+		"feat: add endpoint",
+		"fix: handle nil user",
+		"feat(api): paginate results",
+		"Update README",
+		"fix(ui): align button",
+		"fix: typo",
+		"chore: bump deps",
+	}
+	var commits []Commit
+	for _, message := range messages {
+		commits = append(commits, Commit{Message: message, ConventionalType: conventionalCommitType(message)})
+	}
+
+	expected := "fix: 3, feat: 2, chore: 1, other: 1"
+	if tally := formatCommitTypeTally(tallyCommitTypes(commits)); tally != expected {
+		t.Errorf("Expected tally %q, got %q", expected, tally)
+	}
+
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Commits = commits
+	options := FormatterOptions{ShowCommitTypes: true}
+
+	markdown, err := (&MarkdownFormatter{Options: options}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(markdown.Content, "_"+expected+"_") {
+		t.Errorf("Expected the tally in Markdown output, got:\n%s", markdown.Content)
+	}
+
+	html, err := (&HTMLFormatter{Options: options}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(html.Content, "<p class=\"commit-types\">"+expected+"</p>") {
+		t.Errorf("Expected the tally in HTML output, got:\n%s", html.Content)
+	}
+}
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// dumpTransport writes the raw body of selected API responses to a directory
+// before go-github decodes them, so surprising results can be inspected
+type dumpTransport struct {
+	base http.RoundTripper
+	dir  string
+	seq  atomic.Int64
+}
+
+// newDumpTransport wraps base so responses are dumped to dir. A nil base uses
+// http.DefaultTransport.
+func newDumpTransport(base http.RoundTripper, dir string) *dumpTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &dumpTransport{base: base, dir: dir}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *dumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	
+	kind := dumpKind(req.URL.Path)
+	if kind == "" {
+		return resp, nil
+	}
+	
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	
+	if err := t.write(kind, body); err != nil {
+		// Dumping is a debugging aid and must not fail the request
+		Logger.Printf("Error writing debug dump: %v\n", err)
+	}
+	
+	return resp, nil
+}
+
+// write stores body in a timestamped file named after the response kind
+func (t *dumpTransport) write(kind string, body []byte) error {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create dump directory: %w", err)
+	}
+	
+	name := fmt.Sprintf("%s-%04d-%s.json", time.Now().UTC().Format("20060102T150405.000000000Z"), t.seq.Add(1), kind)
+	if err := os.WriteFile(filepath.Join(t.dir, name), body, 0o644); err != nil {
+		return fmt.Errorf("failed to write dump file: %w", err)
+	}
+	return nil
+}
+
+// dumpKind classifies an API path as one of the dumped response kinds, or
+// returns an empty string for responses that are not dumped
+func dumpKind(path string) string {
+	switch {
+	case strings.HasSuffix(path, "/search/issues"):
+		return "search"
+	case strings.HasSuffix(path, "/commits"):
+		return "commits"
+	case strings.HasSuffix(path, "/comments"):
+		return "comments"
+	case strings.HasSuffix(path, "/reviews"):
+		return "reviews"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+func TestDumpTransport(t *testing.T) {
+	testClient, mux := newTestClient(t)
+	dumpDir := filepath.Join(t.TempDir(), "dump")
+
+	client := externalGithub.NewClient(&http.Client{Transport: newDumpTransport(nil, dumpDir)})
+	client.BaseURL = testClient.BaseURL
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"sha": "abc", "commit": map[string]any{"message": "Work", "committer": map[string]any{"date": "2023-01-01T12:00:00Z"}}},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "body": "Note", "created_at": "2023-01-01T13:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// Dumping must not consume the body go-github decodes
+	if len(prs) != 1 || len(prs[0].Commits) != 1 || len(prs[0].Comments) != 1 {
+		t.Fatalf("Expected 1 fully enriched PR, got %+v", prs)
+	}
+
+	entries, err := os.ReadDir(dumpDir)
+	if err != nil {
+		t.Fatalf("Expected dump directory to exist: %v", err)
+	}
+
+	kinds := map[string]bool{}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		kinds[name[strings.LastIndex(name, "-")+1:]] = true
+
+		content, err := os.ReadFile(filepath.Join(dumpDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read dump file: %v", err)
+		}
+		if len(content) == 0 {
+			t.Errorf("Expected dump file %s to contain the response body", entry.Name())
+		}
+	}
+
+	for _, kind := range []string{"search", "commits", "comments"} {
+		if !kinds[kind] {
+			t.Errorf("Expected a %s dump file, got %v", kind, entries)
+		}
+	}
+}
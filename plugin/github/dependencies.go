@@ -0,0 +1,60 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// dependsOnPattern matches a "depends on" note followed by one or more pull
+// request references, such as "Depends on #12, org/repo#34"
+var dependsOnPattern = regexp.MustCompile(`(?i)\bdepends on:?\s+((?:[\w.-]+/[\w.-]+)?#\d+(?:\s*(?:,|and)\s*(?:[\w.-]+/[\w.-]+)?#\d+)*)`)
+
+// dependencyRefPattern matches a single reference within a "depends on" note
+var dependencyRefPattern = regexp.MustCompile(`(?:([\w.-]+)/([\w.-]+))?#(\d+)`)
+
+// PRRef references a pull request. Organization and Repository are empty for
+// a reference to the same repository, such as #12.
+type PRRef struct {
+	Organization string `json:"organization,omitempty"`
+	Repository   string `json:"repository,omitempty"`
+	Number       int    `json:"number"`
+}
+
+// String renders the reference as written on GitHub: #12 or org/repo#12
+func (ref PRRef) String() string {
+	if ref.Repository == "" {
+		return fmt.Sprintf("#%d", ref.Number)
+	}
+	return fmt.Sprintf("%s/%s#%d", ref.Organization, ref.Repository, ref.Number)
+}
+
+// URL returns the address of the referenced pull request, resolving
+// same-repository references against repo
+func (ref PRRef) URL(repo Repository) string {
+	if ref.Repository != "" {
+		repo = Repository{Organization: ref.Organization, Name: ref.Repository}
+	}
+	return issueURL(repo, strconv.Itoa(ref.Number))
+}
+
+// parseDependencies extracts the pull requests a body says it depends on, in
+// order of appearance and without duplicates
+func parseDependencies(body string) []PRRef {
+	var refs []PRRef
+	seen := make(map[PRRef]bool)
+	for _, note := range dependsOnPattern.FindAllStringSubmatch(body, -1) {
+		for _, match := range dependencyRefPattern.FindAllStringSubmatch(note[1], -1) {
+			number, err := strconv.Atoi(match[3])
+			if err != nil {
+				continue
+			}
+			ref := PRRef{Organization: match[1], Repository: match[2], Number: number}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseDependencies(t *testing.T) {
+	body := "Adds the endpoint.\n\nDepends on #12 and otherorg/api#34.\ndepends on: #12, #56\n\nUnrelated #78"
+
+	expected := []PRRef{
+		{Number: 12},
+		{Organization: "otherorg", Repository: "api", Number: 34},
+		{Number: 56},
+	}
+	if refs := parseDependencies(body); !slices.Equal(refs, expected) {
+		t.Errorf("Expected dependencies %+v, got %+v", expected, refs)
+	}
+
+	if refs := parseDependencies("No dependencies here, see #12"); len(refs) != 0 {
+		t.Errorf("Expected no dependencies, got %+v", refs)
+	}
+}
+
+func TestFormatters_Dependencies(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].DependsOn = []PRRef{
+		{Number: 12},
+		{Organization: "otherorg", Repository: "api", Number: 34},
+	}
+	options := FormatterOptions{ShowDependencies: true}
+
+	markdown, err := (&MarkdownFormatter{Options: options}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	expected := "Depends on: [#12](https://github.com/testorg/testrepo/issues/12), [otherorg/api#34](https://github.com/otherorg/api/issues/34)"
+	if !strings.Contains(markdown.Content, expected) {
+		t.Errorf("Expected %q in Markdown output, got:\n%s", expected, markdown.Content)
+	}
+
+	html, err := (&HTMLFormatter{Options: options}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(html.Content, `<a href="https://github.com/otherorg/api/issues/34">otherorg/api#34</a>`) {
+		t.Errorf("Expected a cross-repository dependency link in HTML output, got:\n%s", html.Content)
+	}
+}
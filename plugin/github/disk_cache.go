@@ -0,0 +1,250 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached results are used when no TTL is
+// configured
+const DefaultCacheTTL = time.Hour
+
+// cacheEntry is the on-disk representation of a cached result
+type cacheEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// cachingRepository wraps a GitHubRepository with a disk cache, so reports
+// run several times a day reuse the results of earlier runs instead of
+// refetching them. Results are keyed by the user, the method, and its
+// arguments, including the time range, so changing the window misses the
+// cache. Failed calls and partially enriched pull requests are not cached.
+type cachingRepository struct {
+	repository GitHubRepository
+	username   string
+	dir        string
+	ttl        time.Duration
+	
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// newCachingRepository wraps repository with a cache in dir whose entries
+// expire after ttl. A ttl of zero uses DefaultCacheTTL.
+func newCachingRepository(repository GitHubRepository, username string, dir string, ttl time.Duration) *cachingRepository {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &cachingRepository{
+		repository: repository,
+		username:   username,
+		dir:        dir,
+		ttl:        ttl,
+		now:        time.Now,
+	}
+}
+
+// cacheOptions are the query options as part of a cache key. Patterns have
+// no JSON form of their own, so their sources are used instead.
+type cacheOptions struct {
+	QueryOptions
+	TitlePattern      string
+	BodyFieldPatterns map[string]string
+}
+
+// keyOptions returns the cache key form of the query options
+func keyOptions(options QueryOptions) cacheOptions {
+	key := cacheOptions{QueryOptions: options}
+	if options.TitlePattern != nil {
+		key.TitlePattern = options.TitlePattern.String()
+	}
+	for name, pattern := range options.BodyFieldPatterns {
+		if key.BodyFieldPatterns == nil {
+			key.BodyFieldPatterns = make(map[string]string)
+		}
+		key.BodyFieldPatterns[name] = pattern.String()
+	}
+	return key
+}
+
+// cached returns the result of fetch, read from the cache when a fresh entry
+// exists and stored in it otherwise. Results for which store returns false
+// are not cached. Cache read and write errors fall back to fetching.
+func cached[T any](c *cachingRepository, store func(T) bool, fetch func() (T, error), method string, args ...any) (T, error) {
+	path, err := c.path(method, args...)
+	if err != nil {
+		return fetch()
+	}
+	
+	var value T
+	if c.read(path, &value) {
+		return value, nil
+	}
+	
+	value, err = fetch()
+	if err != nil || !store(value) {
+		return value, err
+	}
+	if err := c.write(path, value); err != nil {
+		Logger.Printf("Error writing cache entry %s: %v\n", path, err)
+	}
+	return value, nil
+}
+
+// always caches every result
+func always[T any](T) bool {
+	return true
+}
+
+// path returns the cache file of a call
+func (c *cachingRepository) path(method string, args ...any) (string, error) {
+	key, err := json.Marshal(append([]any{c.username, method}, args...))
+	if err != nil {
+		return "", fmt.Errorf("failed to build cache key: %w", err)
+	}
+	sum := sha256.Sum256(key)
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// read decodes the cache entry at path into value, reporting whether a fresh
+// entry was found
+func (c *cachingRepository) read(path string, value any) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			Logger.Printf("Error reading cache entry %s: %v\n", path, err)
+		}
+		return false
+	}
+	
+	var entry cacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		Logger.Printf("Error parsing cache entry %s: %v\n", path, err)
+		return false
+	}
+	if c.now().Sub(entry.StoredAt) > c.ttl {
+		return false
+	}
+	return json.Unmarshal(entry.Value, value) == nil
+}
+
+// write stores value in the cache entry at path, atomically so concurrent
+// reports never read a partial entry
+func (c *cachingRepository) write(path string, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(cacheEntry{StoredAt: c.now(), Value: encoded})
+	if err != nil {
+		return err
+	}
+	
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fullyEnriched reports whether none of the pull requests is missing data
+// because enriching it failed
+func fullyEnriched(prs []PullRequest) bool {
+	for _, pr := range prs {
+		if len(pr.EnrichmentErrors) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetUser implements the GitHubRepository interface
+func (c *cachingRepository) GetUser(ctx context.Context) (*User, error) {
+	return cached(c, always[*User], func() (*User, error) {
+		return c.repository.GetUser(ctx)
+	}, "GetUser")
+}
+
+// ForUser implements the UserScopedRepository interface. The other user's
+// results are cached under their name, so they never mix with this user's.
+// The wrapped repository must implement UserScopedRepository.
+func (c *cachingRepository) ForUser(username string) GitHubRepository {
+	return &cachingRepository{
+		repository: c.repository.(UserScopedRepository).ForUser(username),
+		username:   username,
+		dir:        c.dir,
+		ttl:        c.ttl,
+		now:        c.now,
+	}
+}
+
+// GetPullRequests implements the GitHubRepository interface
+func (c *cachingRepository) GetPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+	return cached(c, fullyEnriched, func() ([]PullRequest, error) {
+		return c.repository.GetPullRequests(ctx, org, repo, timeRange, options)
+	}, "GetPullRequests", org, repo, timeRange, keyOptions(options))
+}
+
+// SearchPullRequests implements the GitHubRepository interface
+func (c *cachingRepository) SearchPullRequests(ctx context.Context, query string, timeRange TimeRange, options QueryOptions) ([]Repository, error) {
+	return cached(c, func(repositories []Repository) bool {
+		for _, repository := range repositories {
+			if !fullyEnriched(repository.PullRequests) {
+				return false
+			}
+		}
+		return true
+	}, func() ([]Repository, error) {
+		return c.repository.SearchPullRequests(ctx, query, timeRange, options)
+	}, "SearchPullRequests", query, timeRange, keyOptions(options))
+}
+
+// GetPullRequest implements the GitHubRepository interface
+func (c *cachingRepository) GetPullRequest(ctx context.Context, org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error) {
+	return cached(c, func(pr PullRequest) bool {
+		return len(pr.EnrichmentErrors) == 0
+	}, func() (PullRequest, error) {
+		return c.repository.GetPullRequest(ctx, org, repo, number, timeRange, options)
+	}, "GetPullRequest", org, repo, number, timeRange, keyOptions(options))
+}
+
+// GetDirectCommits implements the GitHubRepository interface
+func (c *cachingRepository) GetDirectCommits(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Commit, error) {
+	return cached(c, always[[]Commit], func() ([]Commit, error) {
+		return c.repository.GetDirectCommits(ctx, org, repo, timeRange, options)
+	}, "GetDirectCommits", org, repo, timeRange, keyOptions(options))
+}
+
+// GetAssignedIssues implements the GitHubRepository interface
+func (c *cachingRepository) GetAssignedIssues(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Issue, error) {
+	return cached(c, always[[]Issue], func() ([]Issue, error) {
+		return c.repository.GetAssignedIssues(ctx, org, repo, timeRange, options)
+	}, "GetAssignedIssues", org, repo, timeRange, keyOptions(options))
+}
+
+// GetRepositoryMetadata implements the GitHubRepository interface
+func (c *cachingRepository) GetRepositoryMetadata(ctx context.Context, org string, repo string) (RepositoryMetadata, error) {
+	return cached(c, always[RepositoryMetadata], func() (RepositoryMetadata, error) {
+		return c.repository.GetRepositoryMetadata(ctx, org, repo)
+	}, "GetRepositoryMetadata", org, repo)
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestCachingRepository(t *testing.T) {
+	var calls atomic.Int64
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			calls.Add(1)
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			calls.Add(1)
+			return []PullRequest{{Number: 1, Title: "Test PR", State: "open", IsAuthored: true}}, nil
+		},
+	}
+
+	now := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+	repository := newCachingRepository(mockRepo, "testuser", t.TempDir(), time.Hour)
+	repository.now = func() time.Time { return now }
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"testrepo"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+	run := func(timeRange plug.TimeRange) *ActivityReport {
+		t.Helper()
+		// A new service per run, as a separate standup would use
+		report, err := NewActivityService(repository, config).GetActivityReport(timeRange)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		return report
+	}
+
+	day := plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	run(day)
+	if calls.Load() != 2 {
+		t.Fatalf("Expected the first run to call the API twice, got %d", calls.Load())
+	}
+
+	calls.Store(0)
+	report := run(day)
+	if calls.Load() != 0 {
+		t.Errorf("Expected the second run to be served from the cache, got %d API calls", calls.Load())
+	}
+	if prs := report.Repositories[0].PullRequests; len(prs) != 1 || prs[0].Title != "Test PR" {
+		t.Errorf("Expected the cached pull request, got %+v", prs)
+	}
+
+	run(plug.TimeRange{Start: day.Start, End: day.End.Add(24 * time.Hour)})
+	if calls.Load() != 1 {
+		t.Errorf("Expected a different time range to bypass the cached pull requests, got %d API calls", calls.Load())
+	}
+
+	calls.Store(0)
+	now = now.Add(2 * time.Hour)
+	run(day)
+	if calls.Load() != 2 {
+		t.Errorf("Expected expired entries to be refetched, got %d API calls", calls.Load())
+	}
+}
+
+func TestCachingRepository_PartialEnrichment(t *testing.T) {
+	var calls atomic.Int64
+	mockRepo := &MockGitHubRepository{
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			calls.Add(1)
+			return []PullRequest{{Number: 1, EnrichmentErrors: []string{"commits: timeout"}}}, nil
+		},
+	}
+	repository := newCachingRepository(mockRepo, "testuser", t.TempDir(), 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := repository.GetPullRequests(context.Background(), "testorg", "testrepo", testTimeRange(), DefaultQueryOptions()); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected partially enriched results not to be cached, got %d API calls", calls.Load())
+	}
+}
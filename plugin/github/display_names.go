@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// resolveDisplayNames sets the AuthorName of each pull request to its
+// author's display name. Each distinct login is looked up once per
+// repository client, concurrently within the report's limit, and cached.
+// Authors without a display name, or whose lookup fails, keep showing their
+// login.
+func (r *GitHubAPIRepository) resolveDisplayNames(ctx context.Context, prs []PullRequest, maxConcurrency int) {
+	limiter := concurrencyLimiterFrom(ctx, maxConcurrency)
+	
+	// Collect the logins not resolved yet
+	r.namesMu.Lock()
+	if r.names == nil {
+		r.names = make(map[string]string)
+	}
+	pending := make(map[string]bool)
+	for _, pr := range prs {
+		if _, ok := r.names[pr.Author]; !ok && pr.Author != "" {
+			pending[pr.Author] = true
+		}
+	}
+	r.namesMu.Unlock()
+	
+	g := new(errgroup.Group)
+	for login := range pending {
+		g.Go(func() error {
+			limiter.do(func() {
+				name := ""
+				user, _, err := r.users.Get(ctx, login)
+				if err != nil {
+					Logger.Printf("Error resolving the display name of %s: %v\n", login, wrapAPIError(err))
+				} else {
+					name = user.GetName()
+				}
+				
+				// Failed lookups are cached too, so they are not retried
+				r.namesMu.Lock()
+				r.names[login] = name
+				r.namesMu.Unlock()
+			})
+			return nil
+		})
+	}
+	g.Wait()
+	
+	r.namesMu.Lock()
+	defer r.namesMu.Unlock()
+	for i := range prs {
+		prs[i].AuthorName = r.names[prs[i].Author]
+	}
+}
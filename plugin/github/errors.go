@@ -0,0 +1,145 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+// Sentinel errors for the failure kinds callers usually need to tell apart.
+// Errors returned by the repository layer wrap one of these when the
+// underlying GitHub API failure can be classified, so callers can use
+// errors.Is to branch on them.
+var (
+	ErrAuth         = errors.New("github authentication failed")
+	ErrRateLimited  = errors.New("github rate limit exceeded")
+	ErrRepoNotFound = errors.New("github repository not found")
+	
+	// ErrRepoArchived marks repositories skipped because they are archived
+	// or disabled
+	ErrRepoArchived = errors.New("github repository is archived or disabled")
+	
+	// ErrSearchForbidden marks searches rejected because the token may not
+	// use the search API, as with fine-grained tokens limited to reading
+	// repositories. Every repository fails the same way, so it ends the
+	// report with a single error. It is a kind of ErrAuth.
+	ErrSearchForbidden = fmt.Errorf("%w: the token cannot search pull requests; use a token with read access to the repositories' pull requests (the repo scope for classic tokens, or Pull requests: Read for fine-grained tokens)", ErrAuth)
+	
+	// ErrBudgetExceeded marks repositories abandoned because the report ran
+	// past its total time budget
+	ErrBudgetExceeded = errors.New("total time budget exceeded")
+)
+
+// Error kinds reported for skipped repositories, for callers that need a
+// machine-readable classification rather than an error value
+const (
+	ErrorKindNotFound    = "not_found"
+	ErrorKindAuth        = "auth"
+	ErrorKindRateLimited = "rate_limited"
+	ErrorKindTimeout     = "timeout"
+	ErrorKindBudget      = "budget_exceeded"
+	ErrorKindArchived    = "archived"
+	ErrorKindOther       = "other"
+)
+
+// ErrorKind classifies an error into one of the ErrorKind values
+func ErrorKind(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrRepoNotFound):
+		return ErrorKindNotFound
+	case errors.Is(err, ErrAuth):
+		return ErrorKindAuth
+	case errors.Is(err, ErrRateLimited):
+		return ErrorKindRateLimited
+	case errors.Is(err, ErrBudgetExceeded):
+		return ErrorKindBudget
+	case errors.Is(err, ErrRepoArchived):
+		return ErrorKindArchived
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorKindTimeout
+	}
+	return ErrorKindOther
+}
+
+// APIError is a classified GitHub API failure. Kind is one of the sentinel
+// errors above, or a RateLimitExhaustedError when the primary rate limit ran
+// out, and Err is the original error returned by go-github.
+type APIError struct {
+	Kind       error
+	StatusCode int
+	Err        error
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Unwrap exposes both the kind and the original error to errors.Is/errors.As
+func (e *APIError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// wrapAPIError classifies an error returned by go-github into an APIError.
+// Errors that cannot be classified are returned unchanged.
+func wrapAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rateLimitErr *externalGithub.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		// go-github refuses requests itself, without reaching the transport,
+		// until a known exhausted limit resets
+		if reset := rateLimitErr.Rate.Reset.Time; !reset.IsZero() {
+			return &APIError{Kind: &RateLimitExhaustedError{Reset: reset}, StatusCode: statusCode(rateLimitErr.Response), Err: err}
+		}
+		return &APIError{Kind: ErrRateLimited, StatusCode: statusCode(rateLimitErr.Response), Err: err}
+	}
+
+	var abuseErr *externalGithub.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return &APIError{Kind: ErrRateLimited, StatusCode: statusCode(abuseErr.Response), Err: err}
+	}
+
+	var errResp *externalGithub.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return err
+	}
+
+	code := statusCode(errResp.Response)
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &APIError{Kind: ErrAuth, StatusCode: code, Err: err}
+	case http.StatusNotFound:
+		return &APIError{Kind: ErrRepoNotFound, StatusCode: code, Err: err}
+	}
+
+	return err
+}
+
+// wrapSearchError classifies an error returned by the search API like
+// wrapAPIError, reporting a 403 as ErrSearchForbidden. Rate limits also
+// answer 403 but are classified as ErrRateLimited first.
+func wrapSearchError(err error) error {
+	wrapped := wrapAPIError(err)
+	
+	var apiErr *APIError
+	if errors.As(wrapped, &apiErr) && apiErr.Kind == ErrAuth && apiErr.StatusCode == http.StatusForbidden {
+		apiErr.Kind = ErrSearchForbidden
+	}
+	return wrapped
+}
+
+// statusCode returns the status code of a response, or 0 if there is none
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
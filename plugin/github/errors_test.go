@@ -0,0 +1,110 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+func TestWrapAPIError(t *testing.T) {
+	newErrorResponse := func(code int) error {
+		return &externalGithub.ErrorResponse{
+			Response: &http.Response{StatusCode: code, Request: &http.Request{}},
+			Message:  http.StatusText(code),
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{
+			name:     "Unauthorized",
+			err:      newErrorResponse(http.StatusUnauthorized),
+			expected: ErrAuth,
+		},
+		{
+			name:     "Forbidden without rate limit",
+			err:      newErrorResponse(http.StatusForbidden),
+			expected: ErrAuth,
+		},
+		{
+			name:     "Not found",
+			err:      newErrorResponse(http.StatusNotFound),
+			expected: ErrRepoNotFound,
+		},
+		{
+			name:     "Rate limit",
+			err:      &externalGithub.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			expected: ErrRateLimited,
+		},
+		{
+			name:     "Secondary rate limit",
+			err:      &externalGithub.AbuseRateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			expected: ErrRateLimited,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := wrapAPIError(tc.err)
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected original error to be preserved, got: %v", err)
+			}
+		})
+	}
+
+	// Unclassified errors are returned unchanged
+	plain := errors.New("boom")
+	if err := wrapAPIError(plain); err != plain {
+		t.Errorf("Expected unclassified error to be returned unchanged, got: %v", err)
+	}
+
+	if err := wrapAPIError(nil); err != nil {
+		t.Errorf("Expected nil, got: %v", err)
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected string
+	}{
+		{err: &APIError{Kind: ErrRepoNotFound, Err: errors.New("404")}, expected: ErrorKindNotFound},
+		{err: fmt.Errorf("wrapped: %w", &APIError{Kind: ErrAuth, Err: errors.New("401")}), expected: ErrorKindAuth},
+		{err: &APIError{Kind: ErrRateLimited, Err: errors.New("403")}, expected: ErrorKindRateLimited},
+		{err: fmt.Errorf("request failed: %w", context.DeadlineExceeded), expected: ErrorKindTimeout},
+		{err: fmt.Errorf("%w after 5s", ErrBudgetExceeded), expected: ErrorKindBudget},
+		{err: fmt.Errorf("%w: testorg/old", ErrRepoArchived), expected: ErrorKindArchived},
+		{err: errors.New("boom"), expected: ErrorKindOther},
+	}
+
+	for _, tc := range testCases {
+		if kind := ErrorKind(tc.err); kind != tc.expected {
+			t.Errorf("Expected kind %q for %v, got %q", tc.expected, tc.err, kind)
+		}
+	}
+}
+
+func TestWrapSearchError(t *testing.T) {
+	forbidden := &externalGithub.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusForbidden, Request: &http.Request{}},
+		Message:  http.StatusText(http.StatusForbidden),
+	}
+	if err := wrapSearchError(forbidden); !errors.Is(err, ErrSearchForbidden) || ErrorKind(err) != ErrorKindAuth {
+		t.Errorf("Expected a forbidden search to be ErrSearchForbidden, got: %v", err)
+	}
+
+	rateLimited := &externalGithub.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	if err := wrapSearchError(rateLimited); errors.Is(err, ErrSearchForbidden) || !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected a rate limited search to stay ErrRateLimited, got: %v", err)
+	}
+}
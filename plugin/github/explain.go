@@ -0,0 +1,57 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// inclusionReason describes why a pull request is in the report: the
+// searches that matched it and the activity it had in the time range, such
+// as "authored; 2 commits, 1 comment in range"
+func inclusionReason(pr PullRequest) string {
+	var sources []string
+	if pr.IsAuthored {
+		sources = append(sources, "authored")
+	}
+	if pr.IsReviewed {
+		sources = append(sources, "reviewed")
+	}
+	if pr.IsClosedByUser {
+		sources = append(sources, "closed by you")
+	}
+	if pr.IsMetadataEdited {
+		sources = append(sources, "metadata edited")
+	}
+	if len(sources) == 0 {
+		sources = append(sources, "matched search")
+	}
+
+	var activity []string
+	if n := len(pr.Commits); n > 0 {
+		activity = append(activity, countNoun(n, "commit"))
+	}
+	if n := len(pr.Reviews); n > 0 {
+		activity = append(activity, countNoun(n, "review"))
+	}
+	if n := len(pr.Comments); n > 0 {
+		activity = append(activity, countNoun(n, "comment"))
+	}
+	if n := len(pr.MetadataActions); n > 0 {
+		activity = append(activity, countNoun(n, "metadata action"))
+	}
+	activity = append(activity, pr.StateTransitions...)
+
+	reason := strings.Join(sources, ", ")
+	if len(activity) == 0 {
+		return reason + "; no activity in range"
+	}
+	return fmt.Sprintf("%s; %s in range", reason, strings.Join(activity, ", "))
+}
+
+// countNoun formats a count with its noun, adding an s unless it is one
+func countNoun(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestActivityService_Explain(t *testing.T) {
+	inRange := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{
+				{
+					Number:     1,
+					Title:      "Authored PR",
+					IsAuthored: true,
+					CreatedAt:  inRange,
+					Commits:    []Commit{{SHA: "aaa", Timestamp: inRange}, {SHA: "bbb", Timestamp: inRange}},
+				},
+				{
+					Number:     2,
+					Title:      "Reviewed PR",
+					IsReviewed: true,
+					Reviews:    []Review{{ID: 10, State: "APPROVED", Timestamp: inRange}},
+					Comments:   []Comment{{ID: 100, Timestamp: inRange}},
+				},
+			}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+		Explain:      true,
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := map[int]string{
+		1: "authored; 2 commits, opened in range",
+		2: "reviewed; 1 review, 1 comment in range",
+	}
+	for _, pr := range report.Repositories[0].PullRequests {
+		if pr.InclusionReason != expected[pr.Number] {
+			t.Errorf("Expected PR #%d reason %q, got %q", pr.Number, expected[pr.Number], pr.InclusionReason)
+		}
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "<sub>Included: authored; 2 commits, opened in range</sub>") {
+		t.Errorf("Expected the reason as an annotation, got:\n%s", content.Content)
+	}
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// getFeedbackReceived fetches the reviews and review comments others left on
+// one of the user's pull requests within the time range
+func (r *GitHubAPIRepository) getFeedbackReceived(ctx context.Context, org string, repo string, prNumber int, timeRange TimeRange, options QueryOptions) ([]Review, []Comment, error) {
+	reviews, err := r.getReviews(ctx, org, repo, prNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	prComments, err := listPullRequestComments(ctx, r.pullRequests, org, repo, prNumber, timeRange.Start)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list comments for PR #%d: %w", prNumber, wrapAPIError(err))
+	}
+	
+	comments := make([]Comment, 0, len(prComments))
+	for _, prComment := range prComments {
+		if options.PathPrefix != "" && !strings.HasPrefix(prComment.GetPath(), options.PathPrefix) {
+			continue
+		}
+		if matchesAnyPath(prComment.GetPath(), options.ExcludePaths) {
+			continue
+		}
+		comments = append(comments, commentFromPullRequestComment(prComment))
+	}
+	
+	receivedReviews, receivedComments := feedbackReceived(reviews, comments, r.username, timeRange)
+	return receivedReviews, receivedComments, nil
+}
+
+// feedbackReceived keeps the submitted reviews and the comments made within
+// the time range by anyone but the user, so the user's own replies in a
+// thread are not mistaken for feedback they received
+func feedbackReceived(reviews []Review, comments []Comment, username string, timeRange TimeRange) ([]Review, []Comment) {
+	var receivedReviews []Review
+	for _, review := range reviews {
+		if review.Author == username || review.State == ReviewStatePending || !timeRange.IsInRange(review.Timestamp) {
+			continue
+		}
+		receivedReviews = append(receivedReviews, review)
+	}
+	
+	var receivedComments []Comment
+	for _, comment := range comments {
+		if comment.Author == username || !timeRange.IsInRange(comment.Timestamp) {
+			continue
+		}
+		receivedComments = append(receivedComments, comment)
+	}
+	
+	sortReviews(receivedReviews)
+	sortComments(receivedComments)
+	return receivedReviews, receivedComments
+}
@@ -0,0 +1,48 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedbackReceived(t *testing.T) {
+	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	reviews := []Review{
+		{ID: 1, Author: "reviewer", State: "CHANGES_REQUESTED", Body: "Needs tests", Timestamp: timestamp},
+		{ID: 2, Author: "testuser", State: "COMMENTED", Body: "Self note", Timestamp: timestamp},
+		{ID: 3, Author: "reviewer", State: ReviewStatePending, Timestamp: timestamp},
+	}
+	comments := []Comment{
+		{ID: 10, Author: "reviewer", Body: "Why a mutex here?", Timestamp: timestamp, Path: "main.go", Kind: CommentKindReview},
+		{ID: 11, Author: "testuser", Body: "It guards the cache", Timestamp: timestamp.Add(time.Minute), InReplyTo: 10, Kind: CommentKindReview},
+		{ID: 12, Author: "reviewer", Body: "Too old", Timestamp: timestamp.AddDate(0, 0, -3), Kind: CommentKindReview},
+	}
+
+	receivedReviews, receivedComments := feedbackReceived(reviews, comments, "testuser", testTimeRange())
+	if len(receivedReviews) != 1 || receivedReviews[0].ID != 1 {
+		t.Errorf("Expected only the other reviewer's submitted review, got %+v", receivedReviews)
+	}
+	if len(receivedComments) != 1 || receivedComments[0].ID != 10 {
+		t.Fatalf("Expected only the other reviewer's comment in range, got %+v", receivedComments)
+	}
+
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].ReceivedReviews = receivedReviews
+	report.Repositories[0].PullRequests[0].ReceivedComments = receivedComments
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	_, received, found := strings.Cut(content.Content, "**Feedback received:**")
+	if !found {
+		t.Fatalf("Expected a feedback received block, got:\n%s", content.Content)
+	}
+	if !strings.Contains(received, "Why a mutex here?") || !strings.Contains(received, "Needs tests") {
+		t.Errorf("Expected the reviewer's feedback under received, got:\n%s", received)
+	}
+	if strings.Contains(received, "It guards the cache") || strings.Contains(received, "Self note") {
+		t.Errorf("Expected the user's own replies to be left out of received, got:\n%s", received)
+	}
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// fingerprintContent is the part of a report covered by its fingerprint
+type fingerprintContent struct {
+	User                User          `json:"user"`
+	Repositories        []Repository  `json:"repositories"`
+	SkippedRepositories []SkippedRepo `json:"skipped_repositories"`
+}
+
+// Fingerprint returns a stable hash of the report's activity, so hosts can
+// tell a report has not changed since it was last posted. The time range is
+// left out, since a rolling window moves on every run. Repositories, pull
+// requests, and their items are sorted first, so the order the API returned
+// them in does not change the hash.
+func (r *ActivityReport) Fingerprint() string {
+	content := fingerprintContent{
+		User:                r.User,
+		Repositories:        make([]Repository, len(r.Repositories)),
+		SkippedRepositories: append([]SkippedRepo(nil), r.SkippedRepositories...),
+	}
+
+	for i, repo := range r.Repositories {
+		repo.PullRequests = append([]PullRequest(nil), repo.PullRequests...)
+		for j := range repo.PullRequests {
+			sortPullRequestItems(&repo.PullRequests[j])
+		}
+		sort.SliceStable(repo.PullRequests, func(a, b int) bool {
+			return repo.PullRequests[a].Number < repo.PullRequests[b].Number
+		})
+		content.Repositories[i] = repo
+	}
+	sort.SliceStable(content.Repositories, func(a, b int) bool {
+		ra, rb := content.Repositories[a], content.Repositories[b]
+		if ra.Organization != rb.Organization {
+			return ra.Organization < rb.Organization
+		}
+		return ra.Name < rb.Name
+	})
+	sort.SliceStable(content.SkippedRepositories, func(a, b int) bool {
+		return content.SkippedRepositories[a].Name < content.SkippedRepositories[b].Name
+	})
+
+	// Marshaling plain structs cannot fail
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sortPullRequestItems replaces the pull request's commits, reviews, and
+// comments with sorted copies, leaving the original slices untouched
+func sortPullRequestItems(pr *PullRequest) {
+	pr.Commits = append([]Commit(nil), pr.Commits...)
+	sort.SliceStable(pr.Commits, func(a, b int) bool {
+		if !pr.Commits[a].Timestamp.Equal(pr.Commits[b].Timestamp) {
+			return pr.Commits[a].Timestamp.Before(pr.Commits[b].Timestamp)
+		}
+		return pr.Commits[a].SHA < pr.Commits[b].SHA
+	})
+
+	pr.Reviews = append([]Review(nil), pr.Reviews...)
+	sort.SliceStable(pr.Reviews, func(a, b int) bool {
+		return pr.Reviews[a].ID < pr.Reviews[b].ID
+	})
+
+	pr.Comments = append([]Comment(nil), pr.Comments...)
+	sort.SliceStable(pr.Comments, func(a, b int) bool {
+		return pr.Comments[a].ID < pr.Comments[b].ID
+	})
+}
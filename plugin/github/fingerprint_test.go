@@ -0,0 +1,50 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+// createFingerprintReport builds a report with a pull request from each of
+// two repositories, each with a commit and a comment
+func createFingerprintReport() *ActivityReport {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Commits = []Commit{
+		{SHA: "abc123", Message: "Fix bug", Timestamp: time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC)},
+	}
+	report.Repositories[0].PullRequests[0].Comments = []Comment{
+		{ID: 1, Author: "testuser", Body: "Looks good", Timestamp: time.Date(2023, 1, 1, 14, 0, 0, 0, time.UTC)},
+	}
+	report.Repositories = append(report.Repositories, Repository{
+		Name:         "otherrepo",
+		Organization: "testorg",
+		PullRequests: []PullRequest{
+			{Number: 7, Title: "Other PR", State: "open", IsAuthored: true},
+		},
+	})
+	return report
+}
+
+func TestActivityReport_Fingerprint(t *testing.T) {
+	report := createFingerprintReport()
+	fingerprint := report.Fingerprint()
+
+	// Identical activity over a later window, returned in a different order
+	same := createFingerprintReport()
+	same.TimeRange.Start = same.TimeRange.Start.Add(time.Hour)
+	same.TimeRange.End = same.TimeRange.End.Add(time.Hour)
+	same.Repositories[0], same.Repositories[1] = same.Repositories[1], same.Repositories[0]
+	if same.Fingerprint() != fingerprint {
+		t.Errorf("Expected identical activity to have the same fingerprint")
+	}
+
+	changed := createFingerprintReport()
+	changed.Repositories[0].PullRequests[0].Comments[0].Body = "Needs work"
+	if changed.Fingerprint() == fingerprint {
+		t.Errorf("Expected a changed comment to change the fingerprint")
+	}
+
+	if report.Repositories[0].Name != "testrepo" {
+		t.Errorf("Expected the report to be left unmodified")
+	}
+}
@@ -3,7 +3,13 @@ package github
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+
+	"golang.org/x/text/language"
 )
 
 // FormattedContent represents formatted content with its content type
@@ -18,8 +24,109 @@ type ReportFormatter interface {
 	Name() string // Returns the name of the formatter
 }
 
+// StreamingFormatter is implemented by formatters that can write a report
+// directly to a writer, without buffering the whole output in memory
+type StreamingFormatter interface {
+	FormatTo(w io.Writer, report *ActivityReport) error
+}
+
+// FormatterOptions configures optional rendering behaviour shared by the
+// Markdown and HTML formatters
+type FormatterOptions struct {
+	// Render commits with a short SHA linking to the commit on GitHub
+	LinkCommits bool
+	
+	// Locale for weekday and month names in the report time range. The zero
+	// value renders ISO dates.
+	Locale language.Tag
+	
+	// Omit the report title and metadata block, for embedding the output in
+	// a larger document
+	OmitHeader bool
+	
+	// Render a roll-up of pull request counts per active repository
+	IncludeSummary bool
+	
+	// Maximum number of repositories listed in the summary, most active
+	// first. Zero lists every active repository.
+	MaxReposInSummary int
+	
+	// Mark each commit as verified or unverified by its GPG or SSH signature
+	ShowVerification bool
+	
+	// Render each repository's pull requests in a single section, tagged
+	// with the user's roles, instead of authored, reviewed, and closed
+	// sections
+	CombinedPRs bool
+	
+	// Prepend a UTF-8 byte order mark and end lines with CRLF instead of
+	// LF in text output, for Windows tools such as Excel that expect them
+	ByteOrderMark bool
+	CRLF          bool
+	
+	// Tally the conventional commit types (feat, fix, ...) of each pull
+	// request's commits
+	ShowCommitTypes bool
+	
+	// Rewrite #123 and GH-123 references in commit messages, reviews, and
+	// comments into links to the issue or pull request
+	LinkReferences bool
+	
+	// Render commit, review, and comment timestamps relative to Now, such
+	// as "3h ago". Items older than RelativeTimesThreshold keep their
+	// absolute timestamp; zero uses DefaultRelativeTimesThreshold.
+	RelativeTimes          bool
+	RelativeTimesThreshold time.Duration
+	
+	// Render the pull requests each pull request depends on, as noted with
+	// "depends on #12" or "depends on org/repo#12" in its body
+	ShowDependencies bool
+	
+	// Prefix pull request titles in Markdown output with an emoji for their
+	// state: 🟢 open, 🟣 merged, 🔴 closed, or ⚪ draft
+	UseEmoji bool
+	
+	// Render pull request titles in Markdown output as GitHub task list
+	// items, checked once the pull request is merged or closed
+	TaskList bool
+	
+	// Render pull request links as org/repo#123 display text pointing at
+	// the URL instead of printing the raw URL on its own line
+	ShortLinks bool
+	
+	// Show each pull request author's avatar and a link to their profile
+	// in HTML output
+	ShowAuthorAvatars bool
+	
+	// Render a weekday by hour heatmap of the report's commits, reviews,
+	// and comments in HTML output, like GitHub's contribution graph
+	Heatmap bool
+	
+	// End Markdown and HTML output with a footer noting when the report was
+	// generated, the time range it covers, and the plugin version, for
+	// auditing where a report came from
+	Footer bool
+	
+	// Omit indentation and newlines from JSON output, for smaller payloads
+	CompactJSON bool
+	
+	// The time relative timestamps are measured from. The zero value uses
+	// the time the report is formatted.
+	Now time.Time
+}
+
+// truncatedNote ends reports trimmed to fit an output size budget
+const truncatedNote = "Truncated to fit budget: older pull requests and commit, review, and comment bodies may be left out."
+
+// DefaultRelativeTimesThreshold is the age beyond which relative timestamps
+// fall back to absolute ones when no threshold is configured
+const DefaultRelativeTimesThreshold = 7 * 24 * time.Hour
+
 // JSONFormatter formats activity reports as JSON
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	// Omit indentation and newlines, for smaller payloads sent over the wire
+	Compact bool
+}
 
 // NewJSONFormatter creates a new JSON formatter
 func NewJSONFormatter() *JSONFormatter {
@@ -33,15 +140,21 @@ func (f *JSONFormatter) Name() string {
 
 // Format formats an activity report as JSON
 func (f *JSONFormatter) Format(report *ActivityReport) (*FormattedContent, error) {
-	if len(report.Repositories) == 0 || allRepositoriesEmpty(report.Repositories) {
+	if emptyJSONReport(report) {
 		return &FormattedContent{
 			ContentType: "application/json",
 			Content:     "{}",
 		}, nil
 	}
 
-	// Marshal to JSON with proper indentation
-	output, err := json.MarshalIndent(report, "", "  ")
+	// Marshal to JSON with proper indentation, unless compact
+	var output []byte
+	var err error
+	if f.Compact {
+		output, err = json.Marshal(report)
+	} else {
+		output, err = json.MarshalIndent(report, "", "  ")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
 	}
@@ -52,8 +165,36 @@ func (f *JSONFormatter) Format(report *ActivityReport) (*FormattedContent, error
 	}, nil
 }
 
+// FormatTo writes an activity report to w as JSON, encoding it as it goes
+// rather than marshaling it into memory first. The output matches Format,
+// followed by a newline.
+func (f *JSONFormatter) FormatTo(w io.Writer, report *ActivityReport) error {
+	if emptyJSONReport(report) {
+		_, err := io.WriteString(w, "{}\n")
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	if !f.Compact {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// emptyJSONReport reports whether a report is rendered as "{}": it has no
+// activity and no skipped repositories to explain why
+func emptyJSONReport(report *ActivityReport) bool {
+	hasActivity := len(report.Repositories) > 0 && !allRepositoriesEmpty(report.Repositories)
+	return !hasActivity && len(report.SkippedRepositories) == 0
+}
+
 // MarkdownFormatter formats activity reports as Markdown
-type MarkdownFormatter struct{}
+type MarkdownFormatter struct {
+	Options FormatterOptions
+}
 
 // NewMarkdownFormatter creates a new Markdown formatter
 func NewMarkdownFormatter() *MarkdownFormatter {
@@ -68,69 +209,61 @@ func (f *MarkdownFormatter) Name() string {
 // Format formats an activity report as Markdown
 func (f *MarkdownFormatter) Format(report *ActivityReport) (*FormattedContent, error) {
 	if len(report.Repositories) == 0 || allRepositoriesEmpty(report.Repositories) {
+		content := "No GitHub activity found for the specified time range."
+		if len(report.SkippedRepositories) > 0 {
+			var sb strings.Builder
+			sb.WriteString(content + "\n\n")
+			writeMarkdownSkipped(&sb, report.SkippedRepositories)
+			content = sb.String()
+		}
 		return &FormattedContent{
 			ContentType: "text/markdown",
-			Content:     "No GitHub activity found for the specified time range.",
+			Content:     f.Options.encodeText(content),
 		}, nil
 	}
 
+	if f.Options.LinkReferences {
+		report = linkReportReferences(report, markdownReferenceLink)
+	}
+
+	times := f.Options.timestampFormatter()
+
 	var sb strings.Builder
 
 	// Add report header
-	sb.WriteString(fmt.Sprintf("# GitHub Activity Report\n\n"))
-	sb.WriteString(fmt.Sprintf("**Time Range:** %s to %s\n\n", 
-		report.TimeRange.Start.Format("2006-01-02"),
-		report.TimeRange.End.Format("2006-01-02")))
-	sb.WriteString(fmt.Sprintf("**User:** %s\n\n", report.User.Username))
+	if !f.Options.OmitHeader {
+		f.writeHeader(&sb, report)
+	}
+	
+	writeMarkdownHighlights(&sb, highlights(report.Repositories))
+	
+	if f.Options.IncludeSummary {
+		writeMarkdownSummary(&sb, summarizeRepositories(report.Repositories), f.Options.MaxReposInSummary)
+	}
 	
 	// Process each repository
 	for _, repo := range report.Repositories {
-		if len(repo.PullRequests) == 0 {
+		if f.Options.CombinedPRs {
+			f.writeCombinedRepository(&sb, repo, times)
 			continue
 		}
-
-		sb.WriteString(fmt.Sprintf("## Repository: %s/%s\n\n", repo.Organization, repo.Name))
 		
-		// Group PRs by authored/reviewed
-		var authoredPRs, reviewedPRs []PullRequest
-		for _, pr := range repo.PullRequests {
-			if pr.IsAuthored {
-				authoredPRs = append(authoredPRs, pr)
-			}
-			if pr.IsReviewed {
-				reviewedPRs = append(reviewedPRs, pr)
-			}
+		// Group PRs by authored/reviewed/closed/edited, skipping
+		// repositories with nothing to show in any section
+		authoredPRs, reviewedPRs, closedPRs, editedPRs := groupPullRequests(repo.PullRequests)
+		if len(authoredPRs) == 0 && len(reviewedPRs) == 0 && len(closedPRs) == 0 && len(editedPRs) == 0 && len(repo.DirectCommits) == 0 && len(repo.AssignedIssues) == 0 {
+			continue
 		}
+
+		sb.WriteString(fmt.Sprintf("## Repository: %s\n\n", repositoryTitle(repo)))
 		
 		// Add authored PRs section
 		if len(authoredPRs) > 0 {
 			sb.WriteString("### Authored Pull Requests\n\n")
 			for _, pr := range authoredPRs {
-				sb.WriteString(fmt.Sprintf("#### [#%d] %s (%s)\n\n", 
-					pr.Number, pr.Title, pr.State))
-				sb.WriteString(fmt.Sprintf("URL: %s\n\n", pr.URL))
-				
-				// Add commits
-				if len(pr.Commits) > 0 {
-					sb.WriteString("**Commits:**\n\n")
-					for _, commit := range pr.Commits {
-						sb.WriteString(fmt.Sprintf("- %s: %s\n", 
-							commit.Timestamp.Format("2006-01-02 15:04"),
-							commit.Message))
-					}
-					sb.WriteString("\n")
-				}
+				f.writePullRequestHeader(&sb, repo, pr)
 				
-				// Add comments
-				if len(pr.Comments) > 0 {
-					sb.WriteString("**Comments:**\n\n")
-					for _, comment := range pr.Comments {
-						sb.WriteString(fmt.Sprintf("- %s: %s\n", 
-							comment.Timestamp.Format("2006-01-02 15:04"),
-							comment.Body))
-					}
-					sb.WriteString("\n")
-				}
+				f.writePullRequestActivity(&sb, repo, pr, times, true)
 				
 				sb.WriteString("---\n\n")
 			}
@@ -140,46 +273,206 @@ func (f *MarkdownFormatter) Format(report *ActivityReport) (*FormattedContent, e
 		if len(reviewedPRs) > 0 {
 			sb.WriteString("### Reviewed Pull Requests\n\n")
 			for _, pr := range reviewedPRs {
-				sb.WriteString(fmt.Sprintf("#### [#%d] %s (%s)\n\n", 
-					pr.Number, pr.Title, pr.State))
-				sb.WriteString(fmt.Sprintf("URL: %s\n\n", pr.URL))
+				f.writePullRequestHeader(&sb, repo, pr)
 				
-				// Add reviews
-				if len(pr.Reviews) > 0 {
-					sb.WriteString("**Reviews:**\n\n")
-					for _, review := range pr.Reviews {
-						sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", 
-							review.Timestamp.Format("2006-01-02 15:04"),
-							review.State,
-							review.Body))
-					}
-					sb.WriteString("\n")
-				}
+				f.writePullRequestActivity(&sb, repo, pr, times, false)
 				
-				// Add comments
-				if len(pr.Comments) > 0 {
-					sb.WriteString("**Comments:**\n\n")
-					for _, comment := range pr.Comments {
-						sb.WriteString(fmt.Sprintf("- %s: %s\n", 
-							comment.Timestamp.Format("2006-01-02 15:04"),
-							comment.Body))
-					}
-					sb.WriteString("\n")
-				}
+				sb.WriteString("---\n\n")
+			}
+		}
+		
+		// Add closed PRs section
+		if len(closedPRs) > 0 {
+			sb.WriteString("### Closed Pull Requests\n\n")
+			for _, pr := range closedPRs {
+				f.writePullRequestHeader(&sb, repo, pr)
+				sb.WriteString(fmt.Sprintf("Author: %s\n\n", pr.AuthorDisplayName()))
+				sb.WriteString("---\n\n")
+			}
+		}
+		
+		// Add metadata edited PRs section
+		if len(editedPRs) > 0 {
+			sb.WriteString("### Pull Requests with Metadata Changes\n\n")
+			for _, pr := range editedPRs {
+				f.writePullRequestHeader(&sb, repo, pr)
+				sb.WriteString(fmt.Sprintf("Author: %s\n\n", pr.AuthorDisplayName()))
+				
+				f.writePullRequestActivity(&sb, repo, pr, times, false)
 				
 				sb.WriteString("---\n\n")
 			}
 		}
+		
+		f.writeDirectCommits(&sb, repo, times)
+		
+		f.writeAssignedIssues(&sb, repo)
+	}
+	
+	writeMarkdownSkipped(&sb, report.SkippedRepositories)
+	
+	if report.Truncated {
+		sb.WriteString(fmt.Sprintf("_%s_\n", truncatedNote))
+	}
+	
+	if f.Options.Footer {
+		sb.WriteString(fmt.Sprintf("\n_%s_\n", f.Options.footer(report)))
 	}
 
 	return &FormattedContent{
 		ContentType: "text/markdown",
-		Content:     sb.String(),
+		Content:     f.Options.encodeText(sb.String()),
 	}, nil
 }
 
+// writeCombinedRepository writes a repository's pull requests in a single
+// section, each tagged with the user's roles on it
+func (f *MarkdownFormatter) writeCombinedRepository(sb *strings.Builder, repo Repository, times timestampFormatter) {
+	prs := combinePullRequests(repo.PullRequests)
+	if len(prs) == 0 && len(repo.DirectCommits) == 0 && len(repo.AssignedIssues) == 0 {
+		return
+	}
+	
+	sb.WriteString(fmt.Sprintf("## Repository: %s\n\n", repositoryTitle(repo)))
+	if len(prs) > 0 {
+		sb.WriteString("### Pull Requests\n\n")
+	}
+	for _, pr := range prs {
+		f.writePullRequestHeader(sb, repo, pr)
+		sb.WriteString(fmt.Sprintf("_%s_\n\n", strings.Join(pullRequestRoles(pr), ", ")))
+		f.writePullRequestActivity(sb, repo, pr, times, pr.IsAuthored)
+		sb.WriteString("---\n\n")
+	}
+	
+	f.writeDirectCommits(sb, repo, times)
+	
+	f.writeAssignedIssues(sb, repo)
+}
+
+// writeDirectCommits writes the commits pushed to the repository without a
+// pull request, if any
+func (f *MarkdownFormatter) writeDirectCommits(sb *strings.Builder, repo Repository, times timestampFormatter) {
+	if len(repo.DirectCommits) == 0 {
+		return
+	}
+	
+	sb.WriteString("### Direct Commits\n\n")
+	f.writeCommits(sb, repo, repo.DirectCommits, times)
+	sb.WriteString("\n")
+}
+
+// writeAssignedIssues writes the open issues assigned to the user, if any
+func (f *MarkdownFormatter) writeAssignedIssues(sb *strings.Builder, repo Repository) {
+	if len(repo.AssignedIssues) == 0 {
+		return
+	}
+	
+	sb.WriteString("### In Progress\n\n")
+	for _, issue := range repo.AssignedIssues {
+		sb.WriteString(fmt.Sprintf("- [#%d](%s) %s", issue.Number, issue.URL, issue.Title))
+		if len(issue.Labels) > 0 {
+			names := make([]string, 0, len(issue.Labels))
+			for _, label := range issue.Labels {
+				names = append(names, label.Name)
+			}
+			sb.WriteString(fmt.Sprintf(" _%s_", strings.Join(names, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+// writeCommits writes a list item per commit with its timestamp and message
+func (f *MarkdownFormatter) writeCommits(sb *strings.Builder, repo Repository, commits []Commit, times timestampFormatter) {
+	for _, commit := range commits {
+		message := commit.Message
+		if f.Options.ShowVerification {
+			message += fmt.Sprintf(" _(%s)_", verificationLabel(commit))
+		}
+		if f.Options.LinkCommits && commit.SHA != "" {
+			sb.WriteString(fmt.Sprintf("- %s [`%s`](%s): %s\n", 
+				times.format(commit.Timestamp, "2006-01-02 15:04", commit.Range) + afterHoursLabel(commit.AfterHours),
+				shortSHA(commit.SHA),
+				commitURL(repo, commit.SHA),
+				message))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", 
+			times.format(commit.Timestamp, "2006-01-02 15:04", commit.Range) + afterHoursLabel(commit.AfterHours),
+			message))
+	}
+}
+
+// writePullRequestActivity writes the commits of a pull request, when
+// included, followed by its reviews and comments
+func (f *MarkdownFormatter) writePullRequestActivity(sb *strings.Builder, repo Repository, pr PullRequest, times timestampFormatter, includeCommits bool) {
+	if f.Options.ShowDependencies && len(pr.DependsOn) > 0 {
+		links := make([]string, 0, len(pr.DependsOn))
+		for _, ref := range pr.DependsOn {
+			links = append(links, fmt.Sprintf("[%s](%s)", ref, ref.URL(repo)))
+		}
+		sb.WriteString(fmt.Sprintf("Depends on: %s\n\n", strings.Join(links, ", ")))
+	}
+	
+	// Add commits
+	if includeCommits && len(pr.Commits) > 0 {
+		sb.WriteString("**Commits:**\n\n")
+		if f.Options.ShowCommitTypes {
+			sb.WriteString(fmt.Sprintf("_%s_\n\n", formatCommitTypeTally(tallyCommitTypes(pr.Commits))))
+		}
+		f.writeCommits(sb, repo, pr.Commits, times)
+		sb.WriteString("\n")
+	}
+	
+	// Add reviews and comments, with inline comments
+	// under the review they were submitted with
+	reviews, threads := associateReviewComments(pr.Reviews, pr.Comments)
+	if len(reviews) > 0 {
+		sb.WriteString("**Reviews:**\n\n")
+		writeMarkdownReviews(sb, reviews, times)
+		sb.WriteString("\n")
+	}
+	
+	if len(threads) > 0 {
+		sb.WriteString("**Comments:**\n\n")
+		writeMarkdownThreads(sb, threads, "", times)
+		sb.WriteString("\n")
+	}
+	
+	receivedReviews, receivedThreads := associateReviewComments(pr.ReceivedReviews, pr.ReceivedComments)
+	if len(receivedReviews) > 0 || len(receivedThreads) > 0 {
+		sb.WriteString("**Feedback received:**\n\n")
+		writeMarkdownReviews(sb, receivedReviews, times)
+		writeMarkdownThreads(sb, receivedThreads, "", times)
+		sb.WriteString("\n")
+	}
+	
+	if len(pr.MetadataActions) > 0 {
+		sb.WriteString("**Metadata actions:**\n\n")
+		for _, action := range pr.MetadataActions {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", times.format(action.Timestamp, "2006-01-02 15:04", action.Range), action.Description()))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// writeHeader writes the report title and metadata block
+func (f *MarkdownFormatter) writeHeader(sb *strings.Builder, report *ActivityReport) {
+	sb.WriteString(fmt.Sprintf("# GitHub Activity Report\n\n"))
+	if len(report.Ranges) > 1 {
+		sb.WriteString(fmt.Sprintf("**Time Ranges:** %s\n\n", joinTimeRanges(report.Ranges)))
+	} else {
+		sb.WriteString(fmt.Sprintf("**Time Range:** %s to %s\n\n", 
+			formatReportDate(report.TimeRange.Start, f.Options.Locale),
+			formatReportDate(report.TimeRange.End, f.Options.Locale)))
+	}
+	sb.WriteString(fmt.Sprintf("**User:** %s\n\n", report.User.Username))
+}
+
 // HTMLFormatter formats activity reports as HTML
-type HTMLFormatter struct{}
+type HTMLFormatter struct {
+	Options FormatterOptions
+}
 
 // NewHTMLFormatter creates a new HTML formatter
 func NewHTMLFormatter() *HTMLFormatter {
@@ -194,12 +487,22 @@ func (f *HTMLFormatter) Name() string {
 // Format formats an activity report as HTML
 func (f *HTMLFormatter) Format(report *ActivityReport) (*FormattedContent, error) {
 	if len(report.Repositories) == 0 || allRepositoriesEmpty(report.Repositories) {
+		var sb strings.Builder
+		sb.WriteString("<html><body><h1>GitHub Activity Report</h1><p>No activity found for the specified time range.</p>")
+		writeHTMLSkipped(&sb, report.SkippedRepositories)
+		sb.WriteString("</body></html>")
 		return &FormattedContent{
 			ContentType: "text/html",
-			Content:     "<html><body><h1>GitHub Activity Report</h1><p>No activity found for the specified time range.</p></body></html>",
+			Content:     sb.String(),
 		}, nil
 	}
 
+	if f.Options.LinkReferences {
+		report = linkReportReferences(report, htmlReferenceLink)
+	}
+
+	times := f.Options.timestampFormatter()
+
 	var sb strings.Builder
 
 	// Start HTML document
@@ -220,82 +523,65 @@ func (f *HTMLFormatter) Format(report *ActivityReport) (*FormattedContent, error
 	sb.WriteString(".commits, .reviews, .comments { margin-top: 10px; }\n")
 	sb.WriteString(".commit, .review, .comment { background-color: white; border: 1px solid #e1e4e8; padding: 10px; margin-bottom: 8px; }\n")
 	sb.WriteString(".timestamp { color: #586069; font-size: 12px; }\n")
+	sb.WriteString(".comment .reply { margin: 8px 0 0 20px; }\n")
+	sb.WriteString(".skipped { color: #586069; font-size: 14px; border-top: 1px solid #e1e4e8; margin-top: 20px; }\n")
+	sb.WriteString(".conflicts { color: #d73a49; font-weight: bold; }\n")
+	sb.WriteString(".pending-review { color: #b08800; font-weight: bold; }\n")
+	sb.WriteString(".author { color: #586069; font-size: 14px; }\n")
+	sb.WriteString(".depends-on { color: #586069; font-size: 14px; }\n")
+	sb.WriteString(".fields { display: grid; grid-template-columns: max-content auto; gap: 2px 10px; font-size: 14px; }\n")
+	sb.WriteString(".fields dt { font-weight: bold; }\n")
+	sb.WriteString(".fields dd { margin: 0; }\n")
+	sb.WriteString(".avatar { border-radius: 50%; vertical-align: middle; }\n")
+	sb.WriteString(".explain { color: #959da5; font-size: 12px; }\n")
+	sb.WriteString(".verified { color: #28a745; font-size: 12px; }\n")
+	sb.WriteString(".unverified { color: #586069; font-size: 12px; }\n")
+	sb.WriteString(".commit-types { color: #586069; font-style: italic; }\n")
+	sb.WriteString(".roles { color: #586069; font-size: 12px; }\n")
+	sb.WriteString(".size { border: 1px solid #e1e4e8; border-radius: 3px; padding: 0 4px; font-size: 12px; color: #586069; }\n")
+	sb.WriteString(".label { display: inline-block; border-radius: 12px; padding: 2px 8px; margin-right: 4px; font-size: 12px; font-weight: bold; }\n")
 	sb.WriteString("</style>\n")
 	sb.WriteString("</head>\n<body>\n")
 
 	// Add report header
-	sb.WriteString("<h1>GitHub Activity Report</h1>\n")
-	sb.WriteString("<div class=\"metadata\">\n")
-	sb.WriteString(fmt.Sprintf("<p><strong>Time Range:</strong> %s to %s</p>\n", 
-		report.TimeRange.Start.Format("2006-01-02"),
-		report.TimeRange.End.Format("2006-01-02")))
-	sb.WriteString(fmt.Sprintf("<p><strong>User:</strong> %s</p>\n", report.User.Username))
-	sb.WriteString("</div>\n")
+	if !f.Options.OmitHeader {
+		f.writeHeader(&sb, report)
+	}
+	
+	writeHTMLHighlights(&sb, highlights(report.Repositories))
+	
+	if f.Options.IncludeSummary {
+		writeHTMLSummary(&sb, summarizeRepositories(report.Repositories), f.Options.MaxReposInSummary)
+	}
+	
+	if f.Options.Heatmap {
+		writeHTMLHeatmap(&sb, report)
+	}
 	
 	// Process each repository
 	for _, repo := range report.Repositories {
-		if len(repo.PullRequests) == 0 {
+		if f.Options.CombinedPRs {
+			f.writeCombinedRepository(&sb, repo, times)
 			continue
 		}
-
-		sb.WriteString(fmt.Sprintf("<h2>Repository: %s/%s</h2>\n", repo.Organization, repo.Name))
 		
-		// Group PRs by authored/reviewed
-		var authoredPRs, reviewedPRs []PullRequest
-		for _, pr := range repo.PullRequests {
-			if pr.IsAuthored {
-				authoredPRs = append(authoredPRs, pr)
-			}
-			if pr.IsReviewed {
-				reviewedPRs = append(reviewedPRs, pr)
-			}
+		// Group PRs by authored/reviewed/closed/edited, skipping
+		// repositories with nothing to show in any section
+		authoredPRs, reviewedPRs, closedPRs, editedPRs := groupPullRequests(repo.PullRequests)
+		if len(authoredPRs) == 0 && len(reviewedPRs) == 0 && len(closedPRs) == 0 && len(editedPRs) == 0 && len(repo.DirectCommits) == 0 && len(repo.AssignedIssues) == 0 {
+			continue
 		}
+
+		sb.WriteString(fmt.Sprintf("<h2>Repository: %s</h2>\n", repositoryTitle(repo)))
 		
 		// Add authored PRs section
 		if len(authoredPRs) > 0 {
 			sb.WriteString("<h3>Authored Pull Requests</h3>\n")
 			for _, pr := range authoredPRs {
 				sb.WriteString("<div class=\"pr\">\n")
+				f.writeHTMLPullRequestHeader(&sb, repo, pr)
 				
-				// Add PR state class
-				stateClass := "pr-state-open"
-				if pr.State == "closed" {
-					stateClass = "pr-state-closed"
-				} else if pr.State == "merged" {
-					stateClass = "pr-state-merged"
-				}
-				
-				sb.WriteString(fmt.Sprintf("<h4><span class=\"pr-number\">#%d</span> <span class=\"pr-title\">%s</span> <span class=\"%s\">(%s)</span></h4>\n", 
-					pr.Number, pr.Title, stateClass, pr.State))
-				sb.WriteString(fmt.Sprintf("<p><a href=\"%s\">%s</a></p>\n", pr.URL, pr.URL))
-				
-				// Add commits
-				if len(pr.Commits) > 0 {
-					sb.WriteString("<div class=\"commits\">\n")
-					sb.WriteString("<h5>Commits</h5>\n")
-					for _, commit := range pr.Commits {
-						sb.WriteString("<div class=\"commit\">\n")
-						sb.WriteString(fmt.Sprintf("<p>%s</p>\n", commit.Message))
-						sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
-							commit.Timestamp.Format("2006-01-02 15:04:05")))
-						sb.WriteString("</div>\n")
-					}
-					sb.WriteString("</div>\n")
-				}
-				
-				// Add comments
-				if len(pr.Comments) > 0 {
-					sb.WriteString("<div class=\"comments\">\n")
-					sb.WriteString("<h5>Comments</h5>\n")
-					for _, comment := range pr.Comments {
-						sb.WriteString("<div class=\"comment\">\n")
-						sb.WriteString(fmt.Sprintf("<p>%s</p>\n", comment.Body))
-						sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
-							comment.Timestamp.Format("2006-01-02 15:04:05")))
-						sb.WriteString("</div>\n")
-					}
-					sb.WriteString("</div>\n")
-				}
+				f.writePullRequestActivity(&sb, repo, pr, times, true)
 				
 				sb.WriteString("</div>\n")
 			}
@@ -306,53 +592,60 @@ func (f *HTMLFormatter) Format(report *ActivityReport) (*FormattedContent, error
 			sb.WriteString("<h3>Reviewed Pull Requests</h3>\n")
 			for _, pr := range reviewedPRs {
 				sb.WriteString("<div class=\"pr\">\n")
+				f.writeHTMLPullRequestHeader(&sb, repo, pr)
 				
-				// Add PR state class
-				stateClass := "pr-state-open"
-				if pr.State == "closed" {
-					stateClass = "pr-state-closed"
-				} else if pr.State == "merged" {
-					stateClass = "pr-state-merged"
-				}
-				
-				sb.WriteString(fmt.Sprintf("<h4><span class=\"pr-number\">#%d</span> <span class=\"pr-title\">%s</span> <span class=\"%s\">(%s)</span></h4>\n", 
-					pr.Number, pr.Title, stateClass, pr.State))
-				sb.WriteString(fmt.Sprintf("<p><a href=\"%s\">%s</a></p>\n", pr.URL, pr.URL))
+				f.writePullRequestActivity(&sb, repo, pr, times, false)
 				
-				// Add reviews
-				if len(pr.Reviews) > 0 {
-					sb.WriteString("<div class=\"reviews\">\n")
-					sb.WriteString("<h5>Reviews</h5>\n")
-					for _, review := range pr.Reviews {
-						sb.WriteString("<div class=\"review\">\n")
-						sb.WriteString(fmt.Sprintf("<p><strong>%s</strong></p>\n", review.State))
-						if review.Body != "" {
-							sb.WriteString(fmt.Sprintf("<p>%s</p>\n", review.Body))
-						}
-						sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
-							review.Timestamp.Format("2006-01-02 15:04:05")))
-						sb.WriteString("</div>\n")
-					}
-					sb.WriteString("</div>\n")
+				sb.WriteString("</div>\n")
+			}
+		}
+		
+		// Add closed PRs section
+		if len(closedPRs) > 0 {
+			sb.WriteString("<h3>Closed Pull Requests</h3>\n")
+			for _, pr := range closedPRs {
+				sb.WriteString("<div class=\"pr\">\n")
+				f.writeHTMLPullRequestHeader(&sb, repo, pr)
+				if f.Options.ShowAuthorAvatars {
+					writeHTMLAuthor(&sb, pr)
+				} else {
+					sb.WriteString(fmt.Sprintf("<p class=\"metadata\">Author: %s</p>\n", pr.AuthorDisplayName()))
 				}
-				
-				// Add comments
-				if len(pr.Comments) > 0 {
-					sb.WriteString("<div class=\"comments\">\n")
-					sb.WriteString("<h5>Comments</h5>\n")
-					for _, comment := range pr.Comments {
-						sb.WriteString("<div class=\"comment\">\n")
-						sb.WriteString(fmt.Sprintf("<p>%s</p>\n", comment.Body))
-						sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
-							comment.Timestamp.Format("2006-01-02 15:04:05")))
-						sb.WriteString("</div>\n")
-					}
-					sb.WriteString("</div>\n")
+				sb.WriteString("</div>\n")
+			}
+		}
+		
+		// Add metadata edited PRs section
+		if len(editedPRs) > 0 {
+			sb.WriteString("<h3>Pull Requests with Metadata Changes</h3>\n")
+			for _, pr := range editedPRs {
+				sb.WriteString("<div class=\"pr\">\n")
+				f.writeHTMLPullRequestHeader(&sb, repo, pr)
+				if f.Options.ShowAuthorAvatars {
+					writeHTMLAuthor(&sb, pr)
+				} else {
+					sb.WriteString(fmt.Sprintf("<p class=\"metadata\">Author: %s</p>\n", pr.AuthorDisplayName()))
 				}
 				
+				f.writePullRequestActivity(&sb, repo, pr, times, false)
+				
 				sb.WriteString("</div>\n")
 			}
 		}
+		
+		f.writeDirectCommits(&sb, repo, times)
+		
+		f.writeAssignedIssues(&sb, repo)
+	}
+	
+	writeHTMLSkipped(&sb, report.SkippedRepositories)
+	
+	if report.Truncated {
+		sb.WriteString(fmt.Sprintf("<p class=\"truncated\"><em>%s</em></p>\n", truncatedNote))
+	}
+	
+	if f.Options.Footer {
+		sb.WriteString(fmt.Sprintf("<footer class=\"generated\"><em>%s</em></footer>\n", f.Options.footer(report)))
 	}
 	
 	// Close HTML document
@@ -364,12 +657,1086 @@ func (f *HTMLFormatter) Format(report *ActivityReport) (*FormattedContent, error
 	}, nil
 }
 
-// Helper function to check if all repositories are empty
-func allRepositoriesEmpty(repositories []Repository) bool {
-	for _, repo := range repositories {
-		if len(repo.PullRequests) > 0 {
-			return false
+// TeamsFormatter formats activity reports as a Microsoft Teams Adaptive Card
+type TeamsFormatter struct {
+	Options FormatterOptions
+}
+
+// NewTeamsFormatter creates a new Teams formatter
+func NewTeamsFormatter() *TeamsFormatter {
+	return &TeamsFormatter{}
+}
+
+// Name returns the name of the formatter
+func (f *TeamsFormatter) Name() string {
+	return "teams"
+}
+
+// adaptiveCardVersion is the Adaptive Card schema version Teams renders
+const adaptiveCardVersion = "1.4"
+
+// adaptiveCard is the root of an Adaptive Card payload
+type adaptiveCard struct {
+	Type    string        `json:"type"`
+	Schema  string        `json:"$schema"`
+	Version string        `json:"version"`
+	Body    []cardElement `json:"body"`
+}
+
+// cardElement is an Adaptive Card body element. Only the fields of the
+// element's type are set.
+type cardElement struct {
+	Type      string        `json:"type"`
+	Text      string        `json:"text,omitempty"`
+	Size      string        `json:"size,omitempty"`
+	Weight    string        `json:"weight,omitempty"`
+	Wrap      bool          `json:"wrap,omitempty"`
+	Separator bool          `json:"separator,omitempty"`
+	Facts     []cardFact    `json:"facts,omitempty"`
+	Items     []cardElement `json:"items,omitempty"`
+}
+
+// cardFact is a title/value pair in a FactSet
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Format formats an activity report as Adaptive Card JSON
+func (f *TeamsFormatter) Format(report *ActivityReport) (*FormattedContent, error) {
+	card := adaptiveCard{
+		Type:    "AdaptiveCard",
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Version: adaptiveCardVersion,
+		Body: []cardElement{
+			{Type: "TextBlock", Text: "GitHub Activity Report", Size: "Large", Weight: "Bolder", Wrap: true},
+		},
+	}
+	
+	if len(report.Repositories) == 0 || allRepositoriesEmpty(report.Repositories) {
+		card.Body = append(card.Body, cardElement{Type: "TextBlock", Text: "No GitHub activity found for the specified time range.", Wrap: true})
+		card.Body = append(card.Body, teamsSkipped(report.SkippedRepositories)...)
+		return marshalAdaptiveCard(card)
+	}
+	
+	timeRange := fmt.Sprintf("%s to %s",
+		formatReportDate(report.TimeRange.Start, f.Options.Locale),
+		formatReportDate(report.TimeRange.End, f.Options.Locale))
+	if len(report.Ranges) > 1 {
+		timeRange = joinTimeRanges(report.Ranges)
+	}
+	card.Body = append(card.Body, cardElement{
+		Type: "FactSet",
+		Facts: []cardFact{
+			{Title: "Time Range", Value: timeRange},
+			{Title: "User", Value: report.User.Username},
+		},
+	})
+	
+	for _, repo := range report.Repositories {
+		if len(repo.PullRequests) == 0 {
+			continue
+		}
+		
+		facts := make([]cardFact, 0, len(repo.PullRequests))
+		for _, pr := range repo.PullRequests {
+			facts = append(facts, cardFact{
+				Title: fmt.Sprintf("#%d (%s)", pr.Number, pr.State),
+				Value: fmt.Sprintf("[%s](%s) — %s", pr.Title, pr.URL, teamsPullRequestActivity(pr)),
+			})
 		}
+		
+		card.Body = append(card.Body, cardElement{
+			Type:      "Container",
+			Separator: true,
+			Items: []cardElement{
+				{Type: "TextBlock", Text: repositoryTitle(repo), Size: "Medium", Weight: "Bolder", Wrap: true},
+				{Type: "FactSet", Facts: facts},
+			},
+		})
 	}
-	return true
-} 
+	card.Body = append(card.Body, teamsSkipped(report.SkippedRepositories)...)
+	if report.Truncated {
+		card.Body = append(card.Body, cardElement{Type: "TextBlock", Text: fmt.Sprintf("_%s_", truncatedNote), Wrap: true, Separator: true})
+	}
+	
+	return marshalAdaptiveCard(card)
+}
+
+// teamsSkipped returns a section listing the repositories left out of the
+// report and why, or nothing when none were skipped
+func teamsSkipped(skipped []SkippedRepo) []cardElement {
+	if len(skipped) == 0 {
+		return nil
+	}
+	
+	facts := make([]cardFact, 0, len(skipped))
+	for _, repo := range skipped {
+		facts = append(facts, cardFact{
+			Title: fmt.Sprintf("%s (%s)", repo.Name, repo.Kind),
+			Value: repo.Reason,
+		})
+	}
+	
+	return []cardElement{{
+		Type:      "Container",
+		Separator: true,
+		Items: []cardElement{
+			{Type: "TextBlock", Text: "Skipped repositories", Size: "Medium", Weight: "Bolder", Wrap: true},
+			{Type: "FactSet", Facts: facts},
+		},
+	}}
+}
+
+// teamsPullRequestActivity summarizes the user's role and activity on a PR
+func teamsPullRequestActivity(pr PullRequest) string {
+	var roles []string
+	if pr.IsAuthored {
+		roles = append(roles, "authored")
+	}
+	if pr.IsReviewed {
+		roles = append(roles, "reviewed")
+	}
+	if pr.IsClosedByUser {
+		roles = append(roles, "closed")
+	}
+	if pr.IsMetadataEdited {
+		roles = append(roles, "edited metadata")
+	}
+	
+	return fmt.Sprintf("%s: %d commits, %d reviews, %d comments",
+		strings.Join(roles, ", "), len(pr.Commits), len(pr.Reviews), len(pr.Comments))
+}
+
+// marshalAdaptiveCard serializes a card as formatted content
+func marshalAdaptiveCard(card adaptiveCard) (*FormattedContent, error) {
+	output, err := json.MarshalIndent(card, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal adaptive card: %w", err)
+	}
+	
+	return &FormattedContent{
+		ContentType: "application/json",
+		Content:     string(output),
+	}, nil
+}
+
+// stateEmoji returns the emoji for a pull request's state. Drafts are open
+// on GitHub, so they are checked first.
+func stateEmoji(pr PullRequest) string {
+	switch {
+	case pr.Draft && pr.State == "open":
+		return "⚪"
+	case pr.State == "merged":
+		return "🟣"
+	case pr.State == "closed":
+		return "🔴"
+	}
+	return "🟢"
+}
+
+// taskCheckbox returns the mark of a pull request's task list checkbox: "x"
+// once it is merged or closed, and a space while it is open
+func taskCheckbox(pr PullRequest) string {
+	if pr.State == "merged" || pr.State == "closed" {
+		return "x"
+	}
+	return " "
+}
+
+// shortPullRequestRef returns the org/repo#123 display text of a pull request
+func shortPullRequestRef(repo Repository, pr PullRequest) string {
+	return fmt.Sprintf("%s/%s#%d", repo.Organization, repo.Name, pr.Number)
+}
+
+// sizeBadge returns the size category of a pull request as a code span
+// after its title, such as " `L`", or an empty string when it has none
+func sizeBadge(pr PullRequest) string {
+	if pr.SizeCategory == "" {
+		return ""
+	}
+	return fmt.Sprintf(" `%s`", pr.SizeCategory)
+}
+
+// writePullRequestHeader writes the title, URL, and merge commit of a PR,
+// with a state emoji before the title when UseEmoji is set and a size badge
+// after it once its size is known. In task list
+// mode the title is a checkbox item linking to the PR.
+func (f *MarkdownFormatter) writePullRequestHeader(sb *strings.Builder, repo Repository, pr PullRequest) {
+	emoji := ""
+	if f.Options.UseEmoji {
+		emoji = stateEmoji(pr) + " "
+	}
+	if f.Options.TaskList {
+		sb.WriteString(fmt.Sprintf("- [%s] %s[#%d %s](%s) (%s)%s\n\n",
+			taskCheckbox(pr), emoji, pr.Number, pr.Title, pr.URL, pr.State, sizeBadge(pr)))
+	} else {
+		sb.WriteString(fmt.Sprintf("#### %s[#%d] %s (%s)%s\n\n", 
+			emoji, pr.Number, pr.Title, pr.State, sizeBadge(pr)))
+		if f.Options.ShortLinks {
+			sb.WriteString(fmt.Sprintf("[%s](%s)\n\n", shortPullRequestRef(repo, pr), pr.URL))
+		} else {
+			sb.WriteString(fmt.Sprintf("URL: %s\n\n", pr.URL))
+		}
+	}
+	
+	if pr.InclusionReason != "" {
+		sb.WriteString(fmt.Sprintf("<sub>Included: %s</sub>\n\n", pr.InclusionReason))
+	}
+	
+	if pr.HasConflicts() {
+		sb.WriteString("**⚠️ conflicts**\n\n")
+	}
+	
+	if pr.HasPendingReview {
+		sb.WriteString("**📝 pending review in progress**\n\n")
+	}
+	
+	if pr.Milestone != "" {
+		sb.WriteString(fmt.Sprintf("Milestone: %s\n\n", pr.Milestone))
+	}
+	
+	if pr.ReviewDecision != "" {
+		sb.WriteString(fmt.Sprintf("**Review decision:** %s\n\n", pr.ReviewDecision))
+	}
+	
+	if pr.UnresolvedThreads > 0 {
+		sb.WriteString(fmt.Sprintf("**Unresolved threads:** %d\n\n", pr.UnresolvedThreads))
+	}
+	
+	if pr.ThreadsStarted > 0 {
+		sb.WriteString(fmt.Sprintf("**Threads started:** %d\n\n", pr.ThreadsStarted))
+	}
+	
+	if pr.ProjectStatus != "" {
+		sb.WriteString(fmt.Sprintf("**Status:** %s\n\n", pr.ProjectStatus))
+	}
+	
+	if len(pr.ExtractedFields) > 0 {
+		fields := make([]string, 0, len(pr.ExtractedFields))
+		for _, name := range sortedFieldNames(pr.ExtractedFields) {
+			fields = append(fields, fmt.Sprintf("**%s:** %s", name, pr.ExtractedFields[name]))
+		}
+		sb.WriteString(strings.Join(fields, " · ") + "\n\n")
+	}
+	
+	if pr.State == "merged" && pr.MergeCommitSHA != "" {
+		sb.WriteString(fmt.Sprintf("Merge commit: [`%s`](%s)\n\n",
+			shortSHA(pr.MergeCommitSHA),
+			commitURL(repo, pr.MergeCommitSHA)))
+	}
+	
+	// Collapsed so long file lists don't drown out the activity
+	if len(pr.ChangedFilePaths) > 0 {
+		sb.WriteString(fmt.Sprintf("<details>\n<summary>Changed files (%d)</summary>\n\n", len(pr.ChangedFilePaths)))
+		for _, path := range pr.ChangedFilePaths {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", path))
+		}
+		sb.WriteString("\n</details>\n\n")
+	}
+}
+
+// writeCombinedRepository writes a repository's pull requests in a single
+// section, each tagged with the user's roles on it
+func (f *HTMLFormatter) writeCombinedRepository(sb *strings.Builder, repo Repository, times timestampFormatter) {
+	prs := combinePullRequests(repo.PullRequests)
+	if len(prs) == 0 && len(repo.DirectCommits) == 0 && len(repo.AssignedIssues) == 0 {
+		return
+	}
+	
+	sb.WriteString(fmt.Sprintf("<h2>Repository: %s</h2>\n", repositoryTitle(repo)))
+	if len(prs) > 0 {
+		sb.WriteString("<h3>Pull Requests</h3>\n")
+	}
+	for _, pr := range prs {
+		sb.WriteString("<div class=\"pr\">\n")
+		f.writeHTMLPullRequestHeader(sb, repo, pr)
+		sb.WriteString(fmt.Sprintf("<p class=\"roles\">%s</p>\n", strings.Join(pullRequestRoles(pr), ", ")))
+		f.writePullRequestActivity(sb, repo, pr, times, pr.IsAuthored)
+		sb.WriteString("</div>\n")
+	}
+	
+	f.writeDirectCommits(sb, repo, times)
+	
+	f.writeAssignedIssues(sb, repo)
+}
+
+// writeDirectCommits writes the commits pushed to the repository without a
+// pull request, if any
+func (f *HTMLFormatter) writeDirectCommits(sb *strings.Builder, repo Repository, times timestampFormatter) {
+	if len(repo.DirectCommits) == 0 {
+		return
+	}
+	
+	sb.WriteString("<h3>Direct Commits</h3>\n")
+	sb.WriteString("<div class=\"commits\">\n")
+	f.writeCommits(sb, repo, repo.DirectCommits, times)
+	sb.WriteString("</div>\n")
+}
+
+// writeAssignedIssues writes the open issues assigned to the user, if any
+func (f *HTMLFormatter) writeAssignedIssues(sb *strings.Builder, repo Repository) {
+	if len(repo.AssignedIssues) == 0 {
+		return
+	}
+	
+	sb.WriteString("<h3>In Progress</h3>\n")
+	sb.WriteString("<ul class=\"issues\">\n")
+	for _, issue := range repo.AssignedIssues {
+		sb.WriteString(fmt.Sprintf("<li><a href=\"%s\">#%d</a> %s</li>\n", issue.URL, issue.Number, issue.Title))
+	}
+	sb.WriteString("</ul>\n")
+}
+
+// writeCommits writes a block per commit with its message and timestamp
+func (f *HTMLFormatter) writeCommits(sb *strings.Builder, repo Repository, commits []Commit, times timestampFormatter) {
+	for _, commit := range commits {
+		sb.WriteString("<div class=\"commit\">\n")
+		message := commit.Message
+		if f.Options.ShowVerification {
+			class := "unverified"
+			if commit.Verified {
+				class = "verified"
+			}
+			message += fmt.Sprintf(" <span class=\"%s\">%s</span>", class, verificationLabel(commit))
+		}
+		if f.Options.LinkCommits && commit.SHA != "" {
+			sb.WriteString(fmt.Sprintf("<p><a href=\"%s\"><code>%s</code></a> %s</p>\n",
+				commitURL(repo, commit.SHA),
+				shortSHA(commit.SHA),
+				message))
+		} else {
+			sb.WriteString(fmt.Sprintf("<p>%s</p>\n", message))
+		}
+		sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
+			times.format(commit.Timestamp, "2006-01-02 15:04:05", commit.Range) + afterHoursLabel(commit.AfterHours)))
+		sb.WriteString("</div>\n")
+	}
+}
+
+// writePullRequestActivity writes the commits of a pull request, when
+// included, followed by its reviews and comments
+func (f *HTMLFormatter) writePullRequestActivity(sb *strings.Builder, repo Repository, pr PullRequest, times timestampFormatter, includeCommits bool) {
+	if f.Options.ShowAuthorAvatars {
+		writeHTMLAuthor(sb, pr)
+	}
+	
+	if f.Options.ShowDependencies && len(pr.DependsOn) > 0 {
+		links := make([]string, 0, len(pr.DependsOn))
+		for _, ref := range pr.DependsOn {
+			links = append(links, fmt.Sprintf("<a href=\"%s\">%s</a>", ref.URL(repo), ref))
+		}
+		sb.WriteString(fmt.Sprintf("<p class=\"depends-on\">Depends on: %s</p>\n", strings.Join(links, ", ")))
+	}
+	
+	// Add commits
+	if includeCommits && len(pr.Commits) > 0 {
+		sb.WriteString("<div class=\"commits\">\n")
+		sb.WriteString("<h5>Commits</h5>\n")
+		if f.Options.ShowCommitTypes {
+			sb.WriteString(fmt.Sprintf("<p class=\"commit-types\">%s</p>\n", formatCommitTypeTally(tallyCommitTypes(pr.Commits))))
+		}
+		f.writeCommits(sb, repo, pr.Commits, times)
+		sb.WriteString("</div>\n")
+	}
+	
+	// Add reviews and comments, with inline comments
+	// under the review they were submitted with
+	reviews, threads := associateReviewComments(pr.Reviews, pr.Comments)
+	if len(reviews) > 0 {
+		sb.WriteString("<div class=\"reviews\">\n")
+		sb.WriteString("<h5>Reviews</h5>\n")
+		writeHTMLReviews(sb, reviews, times)
+		sb.WriteString("</div>\n")
+	}
+	
+	if len(threads) > 0 {
+		sb.WriteString("<div class=\"comments\">\n")
+		sb.WriteString("<h5>Comments</h5>\n")
+		writeHTMLThreads(sb, threads, times)
+		sb.WriteString("</div>\n")
+	}
+	
+	receivedReviews, receivedThreads := associateReviewComments(pr.ReceivedReviews, pr.ReceivedComments)
+	if len(receivedReviews) > 0 || len(receivedThreads) > 0 {
+		sb.WriteString("<div class=\"feedback\">\n")
+		sb.WriteString("<h5>Feedback received</h5>\n")
+		writeHTMLReviews(sb, receivedReviews, times)
+		writeHTMLThreads(sb, receivedThreads, times)
+		sb.WriteString("</div>\n")
+	}
+	
+	if len(pr.MetadataActions) > 0 {
+		sb.WriteString("<div class=\"metadata-actions\">\n")
+		sb.WriteString("<h5>Metadata actions</h5>\n<ul>\n")
+		for _, action := range pr.MetadataActions {
+			sb.WriteString(fmt.Sprintf("<li><span class=\"timestamp\">%s</span> %s</li>\n", times.format(action.Timestamp, "2006-01-02 15:04:05", action.Range), action.Description()))
+		}
+		sb.WriteString("</ul>\n</div>\n")
+	}
+}
+
+// writeHeader writes the report title and metadata block
+func (f *HTMLFormatter) writeHeader(sb *strings.Builder, report *ActivityReport) {
+	sb.WriteString("<h1>GitHub Activity Report</h1>\n")
+	sb.WriteString("<div class=\"metadata\">\n")
+	if len(report.Ranges) > 1 {
+		sb.WriteString(fmt.Sprintf("<p><strong>Time Ranges:</strong> %s</p>\n", joinTimeRanges(report.Ranges)))
+	} else {
+		sb.WriteString(fmt.Sprintf("<p><strong>Time Range:</strong> %s to %s</p>\n", 
+			formatReportDate(report.TimeRange.Start, f.Options.Locale),
+			formatReportDate(report.TimeRange.End, f.Options.Locale)))
+	}
+	sb.WriteString(fmt.Sprintf("<p><strong>User:</strong> %s</p>\n", report.User.Username))
+	sb.WriteString("</div>\n")
+}
+
+// writeHTMLAuthor writes the pull request author linked to their profile,
+// preceded by their avatar when GitHub returned one
+func writeHTMLAuthor(sb *strings.Builder, pr PullRequest) {
+	if pr.Author == "" {
+		return
+	}
+	
+	profileURL := pr.AuthorURL
+	if profileURL == "" {
+		profileURL = "https://github.com/" + pr.Author
+	}
+	
+	sb.WriteString("<p class=\"author\">")
+	if pr.AuthorAvatarURL != "" {
+		sb.WriteString(fmt.Sprintf("<img class=\"avatar\" src=\"%s\" alt=\"%s\" width=\"20\" height=\"20\"> ", pr.AuthorAvatarURL, pr.AuthorDisplayName()))
+	}
+	sb.WriteString(fmt.Sprintf("<a href=\"%s\">%s</a></p>\n", profileURL, pr.AuthorDisplayName()))
+}
+
+// writeHTMLPullRequestHeader writes the title, URL, and merge commit of a PR
+func (f *HTMLFormatter) writeHTMLPullRequestHeader(sb *strings.Builder, repo Repository, pr PullRequest) {
+	// Add PR state class
+	stateClass := "pr-state-open"
+	if pr.State == "closed" {
+		stateClass = "pr-state-closed"
+	} else if pr.State == "merged" {
+		stateClass = "pr-state-merged"
+	}
+	
+	size := ""
+	if pr.SizeCategory != "" {
+		size = fmt.Sprintf(" <span class=\"size\" title=\"+%d −%d\">%s</span>", pr.Additions, pr.Deletions, pr.SizeCategory)
+	}
+	sb.WriteString(fmt.Sprintf("<h4><span class=\"pr-number\">#%d</span> <span class=\"pr-title\">%s</span> <span class=\"%s\">(%s)</span>%s</h4>\n", 
+		pr.Number, pr.Title, stateClass, pr.State, size))
+	linkText := pr.URL
+	if f.Options.ShortLinks {
+		linkText = shortPullRequestRef(repo, pr)
+	}
+	sb.WriteString(fmt.Sprintf("<p><a href=\"%s\">%s</a></p>\n", pr.URL, linkText))
+	
+	if pr.InclusionReason != "" {
+		sb.WriteString(fmt.Sprintf("<p class=\"explain\">Included: %s</p>\n", pr.InclusionReason))
+	}
+	
+	if pr.HasConflicts() {
+		sb.WriteString("<p class=\"conflicts\">⚠️ conflicts</p>\n")
+	}
+	
+	if pr.HasPendingReview {
+		sb.WriteString("<p class=\"pending-review\">📝 pending review in progress</p>\n")
+	}
+	
+	if len(pr.Labels) > 0 {
+		sb.WriteString("<p class=\"labels\">")
+		for _, label := range pr.Labels {
+			sb.WriteString(fmt.Sprintf("<span class=\"label\" style=\"background-color: #%s; color: %s;\">%s</span>",
+				label.Color, labelTextColor(label.Color), label.Name))
+		}
+		sb.WriteString("</p>\n")
+	}
+	
+	if pr.Milestone != "" {
+		sb.WriteString(fmt.Sprintf("<p class=\"metadata\">Milestone: %s</p>\n", pr.Milestone))
+	}
+	
+	if pr.ReviewDecision != "" {
+		sb.WriteString(fmt.Sprintf("<p><strong>Review decision:</strong> %s</p>\n", pr.ReviewDecision))
+	}
+	
+	if pr.UnresolvedThreads > 0 {
+		sb.WriteString(fmt.Sprintf("<p><strong>Unresolved threads:</strong> %d</p>\n", pr.UnresolvedThreads))
+	}
+	
+	if pr.ThreadsStarted > 0 {
+		sb.WriteString(fmt.Sprintf("<p><strong>Threads started:</strong> %d</p>\n", pr.ThreadsStarted))
+	}
+	
+	if pr.ProjectStatus != "" {
+		sb.WriteString(fmt.Sprintf("<p><strong>Status:</strong> %s</p>\n", pr.ProjectStatus))
+	}
+	
+	if len(pr.ExtractedFields) > 0 {
+		sb.WriteString("<dl class=\"fields\">\n")
+		for _, name := range sortedFieldNames(pr.ExtractedFields) {
+			sb.WriteString(fmt.Sprintf("<dt>%s</dt><dd>%s</dd>\n", name, pr.ExtractedFields[name]))
+		}
+		sb.WriteString("</dl>\n")
+	}
+	
+	if pr.State == "merged" && pr.MergeCommitSHA != "" {
+		sb.WriteString(fmt.Sprintf("<p class=\"metadata\">Merge commit: <a href=\"%s\"><code>%s</code></a></p>\n",
+			commitURL(repo, pr.MergeCommitSHA),
+			shortSHA(pr.MergeCommitSHA)))
+	}
+	
+	if len(pr.ChangedFilePaths) > 0 {
+		sb.WriteString(fmt.Sprintf("<details class=\"files\">\n<summary>Changed files (%d)</summary>\n<ul>\n", len(pr.ChangedFilePaths)))
+		for _, path := range pr.ChangedFilePaths {
+			sb.WriteString(fmt.Sprintf("<li><code>%s</code></li>\n", path))
+		}
+		sb.WriteString("</ul>\n</details>\n")
+	}
+}
+
+// writeMarkdownReviews writes reviews as a list, with the inline comment
+// threads submitted as part of each review indented under it
+func writeMarkdownReviews(sb *strings.Builder, reviews []reviewWithThreads, times timestampFormatter) {
+	for _, review := range reviews {
+		state := review.State
+		if review.CommitID != "" {
+			state += fmt.Sprintf(" at `%s`", shortSHA(review.CommitID))
+		}
+		sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", 
+			times.format(review.Timestamp, "2006-01-02 15:04", review.Range) + afterHoursLabel(review.AfterHours) + postMergeLabel(review.PostMerge),
+			state,
+			review.Body))
+		writeMarkdownThreads(sb, review.Threads, "  ", times)
+	}
+}
+
+// writeHTMLReviews writes reviews as blocks, with the inline comment threads
+// submitted as part of each review nested inside it
+func writeHTMLReviews(sb *strings.Builder, reviews []reviewWithThreads, times timestampFormatter) {
+	for _, review := range reviews {
+		sb.WriteString("<div class=\"review\">\n")
+		if review.CommitID != "" {
+			sb.WriteString(fmt.Sprintf("<p><strong>%s</strong> at <code>%s</code></p>\n", review.State, shortSHA(review.CommitID)))
+		} else {
+			sb.WriteString(fmt.Sprintf("<p><strong>%s</strong></p>\n", review.State))
+		}
+		if review.Body != "" {
+			sb.WriteString(fmt.Sprintf("<p>%s</p>\n", review.Body))
+		}
+		sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
+			times.format(review.Timestamp, "2006-01-02 15:04:05", review.Range) + afterHoursLabel(review.AfterHours) + postMergeLabel(review.PostMerge)))
+		writeHTMLThreads(sb, review.Threads, times)
+		sb.WriteString("</div>\n")
+	}
+}
+
+// writeMarkdownThreads writes comment threads as a list at the given
+// indentation, with replies indented under the comment they reply to
+func writeMarkdownThreads(sb *strings.Builder, threads []CommentThread, indent string, times timestampFormatter) {
+	for _, thread := range threads {
+		sb.WriteString(fmt.Sprintf("%s- %s: %s\n", 
+			indent,
+			times.format(thread.Root.Timestamp, "2006-01-02 15:04", thread.Root.Range) + afterHoursLabel(thread.Root.AfterHours) + postMergeLabel(thread.Root.PostMerge),
+			thread.Root.Body))
+		for _, reply := range thread.Replies {
+			sb.WriteString(fmt.Sprintf("%s  - %s: %s\n", 
+				indent,
+				times.format(reply.Timestamp, "2006-01-02 15:04", reply.Range) + afterHoursLabel(reply.AfterHours) + postMergeLabel(reply.PostMerge),
+				reply.Body))
+		}
+	}
+}
+
+// writeHTMLThreads writes comment threads as blocks, with replies nested
+// inside the comment they reply to
+func writeHTMLThreads(sb *strings.Builder, threads []CommentThread, times timestampFormatter) {
+	for _, thread := range threads {
+		sb.WriteString("<div class=\"comment\">\n")
+		sb.WriteString(fmt.Sprintf("<p>%s</p>\n", thread.Root.Body))
+		sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
+			times.format(thread.Root.Timestamp, "2006-01-02 15:04:05", thread.Root.Range) + afterHoursLabel(thread.Root.AfterHours) + postMergeLabel(thread.Root.PostMerge)))
+		for _, reply := range thread.Replies {
+			sb.WriteString("<div class=\"comment reply\">\n")
+			sb.WriteString(fmt.Sprintf("<p>%s</p>\n", reply.Body))
+			sb.WriteString(fmt.Sprintf("<p class=\"timestamp\">%s</p>\n", 
+				times.format(reply.Timestamp, "2006-01-02 15:04:05", reply.Range) + afterHoursLabel(reply.AfterHours) + postMergeLabel(reply.PostMerge)))
+			sb.WriteString("</div>\n")
+		}
+		sb.WriteString("</div>\n")
+	}
+}
+
+// reviewWithThreads is a review together with the inline comment threads
+// submitted as part of it
+type reviewWithThreads struct {
+	Review
+	Threads []CommentThread
+}
+
+// associateReviewComments attaches each comment thread to the review its root
+// comment was submitted with, so a review's summary and inline comments are
+// rendered together and only once. Comments repeated by ID are rendered
+// once, and reviews and threads are rendered in chronological order. Threads
+// without a matching review are returned separately. GitHub records each
+// reply to an inline comment as a review of its own with an empty body; such
+// reviews are dropped once their comment is shown in its thread.
+func associateReviewComments(reviews []Review, comments []Comment) ([]reviewWithThreads, []CommentThread) {
+	// Reports built elsewhere, such as merged multi-range reports, may hold
+	// reviews out of order
+	reviews = append([]Review(nil), reviews...)
+	sortReviews(reviews)
+	comments = dedupeComments(comments)
+	
+	associated := make([]reviewWithThreads, 0, len(reviews))
+	reviewIndex := make(map[int64]int, len(reviews))
+	for _, review := range reviews {
+		reviewIndex[review.ID] = len(associated)
+		associated = append(associated, reviewWithThreads{Review: review})
+	}
+	
+	var loose []CommentThread
+	for _, thread := range buildCommentThreads(comments) {
+		i, ok := reviewIndex[thread.Root.ReviewID]
+		if thread.Root.ReviewID == 0 || !ok {
+			loose = append(loose, thread)
+			continue
+		}
+		associated[i].Threads = append(associated[i].Threads, thread)
+	}
+	
+	// Reviews that only hold a reply shown in another review's thread
+	hasComments := make(map[int64]bool, len(comments))
+	for _, comment := range comments {
+		hasComments[comment.ReviewID] = true
+	}
+	visible := associated[:0]
+	for _, review := range associated {
+		if review.Body == "" && len(review.Threads) == 0 && hasComments[review.ID] {
+			continue
+		}
+		visible = append(visible, review)
+	}
+	
+	return visible, loose
+}
+
+// utf8BOM is the UTF-8 encoding of the byte order mark
+const utf8BOM = "\ufeff"
+
+// encodeText applies the configured byte order mark and line endings to
+// text output
+func (o FormatterOptions) encodeText(content string) string {
+	if o.CRLF {
+		content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	}
+	if o.ByteOrderMark {
+		content = utf8BOM + content
+	}
+	return content
+}
+
+// timestampFormatter renders activity timestamps, either absolute or relative
+// to the time the report is formatted
+type timestampFormatter struct {
+	relative  bool
+	now       time.Time
+	threshold time.Duration
+}
+
+// timestampFormatter returns the timestamp formatter described by the
+// options, resolving a zero Now and threshold to their defaults
+func (o FormatterOptions) timestampFormatter() timestampFormatter {
+	times := timestampFormatter{
+		relative:  o.RelativeTimes,
+		now:       o.Now,
+		threshold: o.RelativeTimesThreshold,
+	}
+	if times.now.IsZero() {
+		times.now = time.Now()
+	}
+	if times.threshold <= 0 {
+		times.threshold = DefaultRelativeTimesThreshold
+	}
+	return times
+}
+
+// format formats a timestamp, prefixed with the label of the time range the
+// activity belongs to when the report covers several ranges
+func (tf timestampFormatter) format(t time.Time, layout string, rangeLabel string) string {
+	formatted := t.Format(layout)
+	if tf.relative {
+		if relative, ok := relativeTime(t, tf.now, tf.threshold); ok {
+			formatted = relative
+		}
+	}
+	
+	if rangeLabel == "" {
+		return formatted
+	}
+	return fmt.Sprintf("[%s] %s", rangeLabel, formatted)
+}
+
+// afterHoursLabel marks items made outside working hours
+func afterHoursLabel(afterHours bool) string {
+	if afterHours {
+		return " 🌙 after hours"
+	}
+	return ""
+}
+
+// postMergeLabel marks reviews and comments made after the pull request was
+// merged
+func postMergeLabel(postMerge bool) string {
+	if postMerge {
+		return " (post-merge)"
+	}
+	return ""
+}
+
+// relativeTime describes how long before now t was, such as "3h ago" or
+// "2d ago". Times in the future or older than the threshold have no relative
+// form.
+func relativeTime(t time.Time, now time.Time, threshold time.Duration) (string, bool) {
+	age := now.Sub(t)
+	if age < 0 || age > threshold {
+		return "", false
+	}
+	
+	switch {
+	case age < time.Minute:
+		return "just now", true
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute)), true
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour)), true
+	default:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour))), true
+	}
+}
+
+// footer describes when and by what a report was generated, and the time
+// range it covers
+func (o FormatterOptions) footer(report *ActivityReport) string {
+	timeRange := fmt.Sprintf("%s to %s",
+		formatReportDate(report.TimeRange.Start, o.Locale),
+		formatReportDate(report.TimeRange.End, o.Locale))
+	if len(report.Ranges) > 1 {
+		timeRange = joinTimeRanges(report.Ranges)
+	}
+	
+	return fmt.Sprintf("Generated at %s by daiv-github %s for %s",
+		o.timestampFormatter().now.Format(time.RFC3339), Version, timeRange)
+}
+
+// joinTimeRanges lists the labels of several time ranges
+func joinTimeRanges(timeRanges []TimeRange) string {
+	labels := make([]string, 0, len(timeRanges))
+	for _, timeRange := range timeRanges {
+		labels = append(labels, timeRange.Label())
+	}
+	return strings.Join(labels, ", ")
+}
+
+// shortSHA abbreviates a commit SHA to the seven characters GitHub displays
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// repositoryTitle returns the full name of a repository, followed by its tag
+// when it is archived or disabled
+func repositoryTitle(repo Repository) string {
+	title := fmt.Sprintf("%s/%s", repo.Organization, repo.Name)
+	if tag := repo.Tag(); tag != "" {
+		title += fmt.Sprintf(" (%s)", tag)
+	}
+	return title
+}
+
+// commitURL builds the GitHub URL of a commit in a repository
+func commitURL(repo Repository, sha string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/commit/%s", repo.Organization, repo.Name, sha)
+}
+
+// writeMarkdownSkipped writes a footnote listing the repositories left out of
+// the report and why
+func writeMarkdownSkipped(sb *strings.Builder, skipped []SkippedRepo) {
+	if len(skipped) == 0 {
+		return
+	}
+	
+	sb.WriteString("**Skipped repositories:**\n\n")
+	for _, repo := range skipped {
+		sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", repo.Name, repo.Kind, repo.Reason))
+	}
+	sb.WriteString("\n")
+}
+
+// writeHTMLSkipped writes a footnote listing the repositories left out of the
+// report and why
+func writeHTMLSkipped(sb *strings.Builder, skipped []SkippedRepo) {
+	if len(skipped) == 0 {
+		return
+	}
+	
+	sb.WriteString("<div class=\"skipped\">\n<p><strong>Skipped repositories:</strong></p>\n<ul>\n")
+	for _, repo := range skipped {
+		sb.WriteString(fmt.Sprintf("<li>%s (%s): %s</li>\n", repo.Name, repo.Kind, repo.Reason))
+	}
+	sb.WriteString("</ul>\n</div>\n")
+}
+
+// verificationLabel describes the signature verification status of a commit
+func verificationLabel(commit Commit) string {
+	if commit.Verified {
+		return "verified"
+	}
+	if commit.VerificationReason != "" {
+		return "unverified: " + commit.VerificationReason
+	}
+	return "unverified"
+}
+
+// highlight is a pull request state transition featured at the top of a report
+type highlight struct {
+	Transition string
+	Repository Repository
+	PR         PullRequest
+}
+
+// highlights collects the state transitions of every pull request, in report
+// order
+func highlights(repositories []Repository) []highlight {
+	var result []highlight
+	for _, repo := range repositories {
+		for _, pr := range repo.PullRequests {
+			for _, transition := range pr.StateTransitions {
+				result = append(result, highlight{Transition: transition, Repository: repo, PR: pr})
+			}
+		}
+	}
+	return result
+}
+
+// writeMarkdownHighlights writes the state transitions as a list
+func writeMarkdownHighlights(sb *strings.Builder, highlights []highlight) {
+	if len(highlights) == 0 {
+		return
+	}
+	
+	sb.WriteString("## Highlights\n\n")
+	for _, h := range highlights {
+		sb.WriteString(fmt.Sprintf("- **%s%s** [#%d](%s) %s (%s/%s)\n",
+			strings.ToUpper(h.Transition[:1]), h.Transition[1:],
+			h.PR.Number, h.PR.URL, h.PR.Title,
+			h.Repository.Organization, h.Repository.Name))
+	}
+	sb.WriteString("\n")
+}
+
+// writeHTMLHighlights writes the state transitions as a list
+func writeHTMLHighlights(sb *strings.Builder, highlights []highlight) {
+	if len(highlights) == 0 {
+		return
+	}
+	
+	sb.WriteString("<h2>Highlights</h2>\n<ul class=\"highlights\">\n")
+	for _, h := range highlights {
+		sb.WriteString(fmt.Sprintf("<li><strong>%s%s</strong> <a href=\"%s\">#%d</a> %s (%s/%s)</li>\n",
+			strings.ToUpper(h.Transition[:1]), h.Transition[1:],
+			h.PR.URL, h.PR.Number, h.PR.Title,
+			h.Repository.Organization, h.Repository.Name))
+	}
+	sb.WriteString("</ul>\n")
+}
+
+// repositorySummary counts the pull requests of a repository by category
+type repositorySummary struct {
+	Name     string
+	Authored int
+	Reviewed int
+	Total    int
+}
+
+// summarizeRepositories counts the pull requests of each active repository,
+// most active first
+func summarizeRepositories(repositories []Repository) []repositorySummary {
+	summaries := make([]repositorySummary, 0, len(repositories))
+	for _, repo := range repositories {
+		if len(repo.PullRequests) == 0 {
+			continue
+		}
+		
+		summary := repositorySummary{
+			Name:  fmt.Sprintf("%s/%s", repo.Organization, repo.Name),
+			Total: len(repo.PullRequests),
+		}
+		for _, pr := range repo.PullRequests {
+			if pr.IsAuthored {
+				summary.Authored++
+			}
+			if pr.IsReviewed {
+				summary.Reviewed++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Total != summaries[j].Total {
+			return summaries[i].Total > summaries[j].Total
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	
+	return summaries
+}
+
+// truncateSummaries caps the summaries at max, returning how many were left
+// out. A max of zero or less keeps every summary.
+func truncateSummaries(summaries []repositorySummary, max int) ([]repositorySummary, int) {
+	if max <= 0 || len(summaries) <= max {
+		return summaries, 0
+	}
+	return summaries[:max], len(summaries) - max
+}
+
+// writeMarkdownSummary writes the per-repository roll-up as a list
+func writeMarkdownSummary(sb *strings.Builder, summaries []repositorySummary, max int) {
+	shown, remaining := truncateSummaries(summaries, max)
+	
+	sb.WriteString("## Summary\n\n")
+	for _, summary := range shown {
+		sb.WriteString(fmt.Sprintf("- %s: %d authored, %d reviewed\n", summary.Name, summary.Authored, summary.Reviewed))
+	}
+	if remaining > 0 {
+		sb.WriteString(fmt.Sprintf("- and %d more\n", remaining))
+	}
+	sb.WriteString("\n")
+}
+
+// writeHTMLSummary writes the per-repository roll-up as a list
+func writeHTMLSummary(sb *strings.Builder, summaries []repositorySummary, max int) {
+	shown, remaining := truncateSummaries(summaries, max)
+	
+	sb.WriteString("<h2>Summary</h2>\n<ul class=\"summary\">\n")
+	for _, summary := range shown {
+		sb.WriteString(fmt.Sprintf("<li>%s: %d authored, %d reviewed</li>\n", summary.Name, summary.Authored, summary.Reviewed))
+	}
+	if remaining > 0 {
+		sb.WriteString(fmt.Sprintf("<li>and %d more</li>\n", remaining))
+	}
+	sb.WriteString("</ul>\n")
+}
+
+// groupPullRequests splits pull requests into the authored, reviewed,
+// closed, and metadata edited sections they are rendered under. A pull
+// request can appear in more than one section.
+func groupPullRequests(prs []PullRequest) (authored, reviewed, closed, edited []PullRequest) {
+	for _, pr := range prs {
+		if pr.IsAuthored {
+			authored = append(authored, pr)
+		}
+		if pr.IsReviewed {
+			reviewed = append(reviewed, pr)
+		}
+		if pr.IsClosedByUser {
+			closed = append(closed, pr)
+		}
+		if pr.IsMetadataEdited {
+			edited = append(edited, pr)
+		}
+	}
+	return authored, reviewed, closed, edited
+}
+
+// combinePullRequests merges the entries of a pull request that appears
+// more than once, such as in both the authored and reviewed results, into
+// one entry with the roles, commits, reviews, and comments of all of them.
+// Pull requests in no section are dropped.
+func combinePullRequests(prs []PullRequest) []PullRequest {
+	var combined []PullRequest
+	index := make(map[int]int)
+	for _, pr := range prs {
+		if !pr.IsAuthored && !pr.IsReviewed && !pr.IsClosedByUser && !pr.IsMetadataEdited {
+			continue
+		}
+		
+		i, found := index[pr.Number]
+		if !found {
+			index[pr.Number] = len(combined)
+			combined = append(combined, pr)
+			continue
+		}
+		
+		merged := &combined[i]
+		merged.IsAuthored = merged.IsAuthored || pr.IsAuthored
+		merged.IsReviewed = merged.IsReviewed || pr.IsReviewed
+		merged.IsClosedByUser = merged.IsClosedByUser || pr.IsClosedByUser
+		merged.IsMetadataEdited = merged.IsMetadataEdited || pr.IsMetadataEdited
+		for _, commit := range pr.Commits {
+			if !slices.ContainsFunc(merged.Commits, func(c Commit) bool { return c.SHA == commit.SHA }) {
+				merged.Commits = append(merged.Commits, commit)
+			}
+		}
+		for _, review := range pr.Reviews {
+			if !slices.ContainsFunc(merged.Reviews, func(r Review) bool { return r.ID == review.ID }) {
+				merged.Reviews = append(merged.Reviews, review)
+			}
+		}
+		for _, comment := range pr.Comments {
+			if !slices.ContainsFunc(merged.Comments, func(c Comment) bool { return c.ID == comment.ID }) {
+				merged.Comments = append(merged.Comments, comment)
+			}
+		}
+	}
+	return combined
+}
+
+// pullRequestRoles lists the user's roles on a pull request
+func pullRequestRoles(pr PullRequest) []string {
+	var roles []string
+	if pr.IsAuthored {
+		roles = append(roles, "authored")
+	}
+	if pr.IsReviewed {
+		roles = append(roles, "reviewed")
+	}
+	if pr.IsClosedByUser {
+		roles = append(roles, "closed")
+	}
+	if pr.IsMetadataEdited {
+		roles = append(roles, "edited metadata")
+	}
+	return roles
+}
+
+// Helper function to check if all repositories are empty
+func allRepositoriesEmpty(repositories []Repository) bool {
+	for _, repo := range repositories {
+		if len(repo.PullRequests) > 0 || len(repo.DirectCommits) > 0 || len(repo.AssignedIssues) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// labelTextColor picks black or white text, whichever reads better on the
+// given hex background color
+func labelTextColor(color string) string {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(color, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return "#000000"
+	}
+	
+	// Perceived luminance per ITU-R BT.601
+	luminance := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 255
+	if luminance > 0.5 {
+		return "#000000"
+	}
+	return "#ffffff"
+}
@@ -1,10 +1,15 @@
 package github
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/text/language"
 )
 
 // createTestActivityReport creates a sample activity report for testing
@@ -101,6 +106,110 @@ func TestJSONFormatter(t *testing.T) {
 	}
 }
 
+// TestJSONFormatter_FieldNames tests that the JSON output uses snake_case names
+func TestJSONFormatter_FieldNames(t *testing.T) {
+	content, err := NewJSONFormatter().Format(createTestActivityReport())
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	for _, name := range []string{`"time_range"`, `"repositories"`, `"pull_requests"`, `"is_authored": true`, `"is_reviewed": false`, `"created_at"`} {
+		if !strings.Contains(content.Content, name) {
+			t.Errorf("Expected %s in JSON output, got:\n%s", name, content.Content)
+		}
+	}
+
+	// Unset optional fields are omitted
+	for _, name := range []string{`"IsAuthored"`, `"head_branch"`, `"enrichment_errors"`, `"commits"`} {
+		if strings.Contains(content.Content, name) {
+			t.Errorf("Expected no %s in JSON output, got:\n%s", name, content.Content)
+		}
+	}
+}
+
+// TestJSONFormatter_Compact tests that compact output is minified but
+// carries the same report
+func TestJSONFormatter_Compact(t *testing.T) {
+	report := createTestActivityReport()
+
+	pretty, err := NewJSONFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	compact, err := (&JSONFormatter{Compact: true}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	if strings.Contains(compact.Content, "\n") || strings.Contains(compact.Content, "  ") {
+		t.Errorf("Expected no newlines or indentation in compact JSON, got:\n%s", compact.Content)
+	}
+	if len(compact.Content) >= len(pretty.Content) {
+		t.Errorf("Expected compact JSON to be smaller than %d bytes, got %d", len(pretty.Content), len(compact.Content))
+	}
+
+	var prettyParsed, compactParsed any
+	if err := json.Unmarshal([]byte(pretty.Content), &prettyParsed); err != nil {
+		t.Fatalf("Error parsing JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(compact.Content), &compactParsed); err != nil {
+		t.Fatalf("Error parsing compact JSON: %v", err)
+	}
+	if !reflect.DeepEqual(prettyParsed, compactParsed) {
+		t.Errorf("Expected compact JSON to parse to the same structure as pretty JSON")
+	}
+}
+
+// TestJSONFormatter_FormatTo tests that streaming matches the string output
+func TestJSONFormatter_FormatTo(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	for _, report := range []*ActivityReport{createTestActivityReport(), createEmptyActivityReport()} {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := formatter.FormatTo(&buf, report); err != nil {
+			t.Fatalf("Error streaming report: %v", err)
+		}
+
+		if buf.String() != content.Content+"\n" {
+			t.Errorf("Expected streamed output to match Format.\nFormat:\n%s\nFormatTo:\n%s", content.Content, buf.String())
+		}
+	}
+}
+
+// TestJSONFormatter_SkippedOnly tests that a report whose repositories were
+// all skipped still says why
+func TestJSONFormatter_SkippedOnly(t *testing.T) {
+	report := createEmptyActivityReport()
+	report.SkippedRepositories = []SkippedRepo{{Name: "testorg/private", Reason: "not found", Kind: ErrorKindNotFound}}
+
+	formatter := NewJSONFormatter()
+	content, err := formatter.Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	var parsed ActivityReport
+	if err := json.Unmarshal([]byte(content.Content), &parsed); err != nil {
+		t.Fatalf("Error parsing JSON: %v", err)
+	}
+	if len(parsed.SkippedRepositories) != 1 || parsed.SkippedRepositories[0].Name != "testorg/private" {
+		t.Errorf("Expected the skipped repository in the JSON output, got:\n%s", content.Content)
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.FormatTo(&buf, report); err != nil {
+		t.Fatalf("Error streaming report: %v", err)
+	}
+	if buf.String() != content.Content+"\n" {
+		t.Errorf("Expected streamed output to match Format.\nFormat:\n%s\nFormatTo:\n%s", content.Content, buf.String())
+	}
+}
+
 // TestMarkdownFormatter tests the Markdown formatter
 func TestMarkdownFormatter(t *testing.T) {
 	formatter := NewMarkdownFormatter()
@@ -275,3 +384,821 @@ func TestAllRepositoriesEmpty(t *testing.T) {
 		})
 	}
 } 
+
+// TestFormatters_SkipsRepositoriesWithoutSections tests that a repository
+// whose pull requests belong to no section renders no heading
+func TestFormatters_SkipsRepositoriesWithoutSections(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories = append(report.Repositories, Repository{
+		Name:         "quietrepo",
+		Organization: "testorg",
+		PullRequests: []PullRequest{{Number: 7, Title: "Untouched PR"}},
+	})
+
+	formatters := []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()}
+	for _, formatter := range formatters {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if strings.Contains(content.Content, "quietrepo") {
+			t.Errorf("Expected %s output to skip quietrepo, got:\n%s", formatter.Name(), content.Content)
+		}
+		if !strings.Contains(content.Content, "testrepo") {
+			t.Errorf("Expected %s output to include testrepo", formatter.Name())
+		}
+	}
+}
+
+// TestFormatters_MergeCommit tests that the merge commit renders only for merged PRs
+func TestFormatters_MergeCommit(t *testing.T) {
+	report := createTestActivityReport()
+	merged := report.Repositories[0].PullRequests[0]
+	merged.Number = 124
+	merged.State = "merged"
+	merged.MergeCommitSHA = "abcdef1234567890abcdef1234567890abcdef12"
+	report.Repositories[0].PullRequests = append(report.Repositories[0].PullRequests, merged)
+
+	// An open PR with a SHA set must still not render it
+	report.Repositories[0].PullRequests[0].MergeCommitSHA = "0123456789abcdef0123456789abcdef01234567"
+
+	formatters := []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()}
+	for _, formatter := range formatters {
+		t.Run(formatter.Name(), func(t *testing.T) {
+			content, err := formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			if !strings.Contains(content.Content, "abcdef1") {
+				t.Errorf("Expected merged PR's short merge SHA to appear, got:\n%s", content.Content)
+			}
+			if !strings.Contains(content.Content, "https://github.com/testorg/testrepo/commit/abcdef1234567890abcdef1234567890abcdef12") {
+				t.Errorf("Expected merge commit link to appear, got:\n%s", content.Content)
+			}
+			if strings.Contains(content.Content, "0123456") {
+				t.Errorf("Expected no merge commit for the open PR, got:\n%s", content.Content)
+			}
+		})
+	}
+}
+
+// TestFormatters_LinkCommits tests that commits render with a linked short SHA
+func TestFormatters_LinkCommits(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Commits = []Commit{
+		{
+			SHA:       "1234567890abcdef1234567890abcdef12345678",
+			Message:   "Fix the thing",
+			Timestamp: time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC),
+		},
+	}
+	link := "https://github.com/testorg/testrepo/commit/1234567890abcdef1234567890abcdef12345678"
+
+	testCases := []struct {
+		formatter ReportFormatter
+		plain     ReportFormatter
+		expected  string
+	}{
+		{
+			formatter: &MarkdownFormatter{Options: FormatterOptions{LinkCommits: true}},
+			plain:     NewMarkdownFormatter(),
+			expected:  "[`1234567`](" + link + "): Fix the thing",
+		},
+		{
+			formatter: &HTMLFormatter{Options: FormatterOptions{LinkCommits: true}},
+			plain:     NewHTMLFormatter(),
+			expected:  "<a href=\"" + link + "\"><code>1234567</code></a> Fix the thing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.formatter.Name(), func(t *testing.T) {
+			content, err := tc.formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+			if !strings.Contains(content.Content, tc.expected) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tc.expected, content.Content)
+			}
+
+			plain, err := tc.plain.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+			if strings.Contains(plain.Content, "1234567") {
+				t.Errorf("Expected no commit SHA without the option, got:\n%s", plain.Content)
+			}
+		})
+	}
+}
+
+// TestFormatters_CommentThreads tests that replies render indented under their parent
+func TestHTMLFormatter_AuthorAvatars(t *testing.T) {
+	report := createTestActivityReport()
+	pr := &report.Repositories[0].PullRequests[0]
+	pr.Author = "testuser"
+	pr.AuthorAvatarURL = "https://avatars.githubusercontent.com/u/1"
+	pr.AuthorURL = "https://github.com/testuser"
+	formatter := &HTMLFormatter{Options: FormatterOptions{ShowAuthorAvatars: true}}
+
+	content, err := formatter.Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, `<img class="avatar" src="https://avatars.githubusercontent.com/u/1"`) {
+		t.Errorf("Expected the author's avatar, got:\n%s", content.Content)
+	}
+	if !strings.Contains(content.Content, `<a href="https://github.com/testuser">testuser</a>`) {
+		t.Errorf("Expected a link to the author's profile, got:\n%s", content.Content)
+	}
+
+	// Without an avatar only the profile link is shown
+	pr.AuthorAvatarURL = ""
+	pr.AuthorURL = ""
+	content, err = formatter.Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(content.Content, "<img") {
+		t.Errorf("Expected no image without an avatar, got:\n%s", content.Content)
+	}
+	if !strings.Contains(content.Content, `<a href="https://github.com/testuser">testuser</a>`) {
+		t.Errorf("Expected a fallback profile link, got:\n%s", content.Content)
+	}
+
+	content, err = NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(content.Content, `class="author"`) {
+		t.Errorf("Expected no author line by default, got:\n%s", content.Content)
+	}
+}
+
+func TestMarkdownFormatter_StateEmoji(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].State = "merged"
+
+	content, err := (&MarkdownFormatter{Options: FormatterOptions{UseEmoji: true}}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "#### 🟣 [#123] Test PR (merged)") {
+		t.Errorf("Expected the merged emoji before the PR, got:\n%s", content.Content)
+	}
+
+	testCases := []struct {
+		pr       PullRequest
+		expected string
+	}{
+		{PullRequest{State: "open"}, "🟢"},
+		{PullRequest{State: "open", Draft: true}, "⚪"},
+		{PullRequest{State: "closed"}, "🔴"},
+	}
+	for _, tc := range testCases {
+		if emoji := stateEmoji(tc.pr); emoji != tc.expected {
+			t.Errorf("Expected %s for %+v, got %s", tc.expected, tc.pr, emoji)
+		}
+	}
+
+	content, err = NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(content.Content, "🟣") {
+		t.Errorf("Expected no emoji by default, got:\n%s", content.Content)
+	}
+}
+
+func TestMarkdownFormatter_TaskList(t *testing.T) {
+	report := createTestActivityReport()
+	merged := report.Repositories[0].PullRequests[0]
+	merged.Number = 124
+	merged.Title = "Shipped PR"
+	merged.URL = "https://github.com/testorg/testrepo/pull/124"
+	merged.State = "merged"
+	report.Repositories[0].PullRequests[0].State = "open"
+	report.Repositories[0].PullRequests = append(report.Repositories[0].PullRequests, merged)
+
+	content, err := (&MarkdownFormatter{Options: FormatterOptions{TaskList: true}}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "- [ ] [#123 Test PR](https://github.com/testorg/testrepo/pull/123) (open)") {
+		t.Errorf("Expected the open PR as an unchecked item, got:\n%s", content.Content)
+	}
+	if !strings.Contains(content.Content, "- [x] [#124 Shipped PR](https://github.com/testorg/testrepo/pull/124) (merged)") {
+		t.Errorf("Expected the merged PR as a checked item, got:\n%s", content.Content)
+	}
+	if strings.Contains(content.Content, "#### ") {
+		t.Errorf("Expected no PR headings in task list mode, got:\n%s", content.Content)
+	}
+}
+
+func TestFormatters_ShortLinks(t *testing.T) {
+	report := createTestActivityReport()
+	options := FormatterOptions{ShortLinks: true}
+
+	content, err := (&MarkdownFormatter{Options: options}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "[testorg/testrepo#123](https://github.com/testorg/testrepo/pull/123)") {
+		t.Errorf("Expected a shortened link to the PR, got:\n%s", content.Content)
+	}
+	if strings.Contains(content.Content, "URL: ") {
+		t.Errorf("Expected no raw URL line with short links, got:\n%s", content.Content)
+	}
+
+	content, err = (&HTMLFormatter{Options: options}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "<a href=\"https://github.com/testorg/testrepo/pull/123\">testorg/testrepo#123</a>") {
+		t.Errorf("Expected a shortened link to the PR, got:\n%s", content.Content)
+	}
+
+	content, err = NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "URL: https://github.com/testorg/testrepo/pull/123") {
+		t.Errorf("Expected the raw URL by default, got:\n%s", content.Content)
+	}
+}
+
+func TestFormatters_DuplicateComments(t *testing.T) {
+	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Comments = []Comment{
+		{ID: 7, Author: "testuser", Body: "duplicated remark", Timestamp: timestamp, Kind: CommentKindConversation},
+		{ID: 7, Author: "testuser", Body: "duplicated remark", Timestamp: timestamp, Kind: CommentKindReview, Path: "main.go"},
+		{ID: 8, Author: "testuser", Body: "unique remark", Timestamp: timestamp, Kind: CommentKindReview},
+	}
+
+	for _, formatter := range []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()} {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+		if count := strings.Count(content.Content, "duplicated remark"); count != 1 {
+			t.Errorf("Expected the duplicated comment once, got %d times in:\n%s", count, content.Content)
+		}
+		if !strings.Contains(content.Content, "unique remark") {
+			t.Errorf("Expected the unique comment, got:\n%s", content.Content)
+		}
+	}
+}
+
+func TestFormatters_ChronologicalComments(t *testing.T) {
+	base := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := createTestActivityReport()
+	pr := &report.Repositories[0].PullRequests[0]
+	pr.Comments = []Comment{
+		{ID: 3, Author: "testuser", Body: "third comment", Timestamp: base.Add(time.Hour)},
+		{ID: 2, Author: "testuser", Body: "second comment", Timestamp: base},
+		{ID: 1, Author: "testuser", Body: "first comment", Timestamp: base},
+	}
+	pr.Reviews = []Review{
+		{ID: 20, Author: "testuser", State: "APPROVED", Body: "later review", Timestamp: base.Add(2 * time.Hour)},
+		{ID: 10, Author: "testuser", State: "COMMENTED", Body: "earlier review", Timestamp: base},
+	}
+
+	for _, formatter := range []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()} {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+
+		order := []string{"earlier review", "later review", "first comment", "second comment", "third comment"}
+		last := -1
+		for _, body := range order {
+			i := strings.Index(content.Content, body)
+			if i <= last {
+				t.Errorf("Expected %q in chronological order, got:\n%s", body, content.Content)
+				break
+			}
+			last = i
+		}
+	}
+}
+
+func TestFormatters_CommentThreads(t *testing.T) {
+	report := createTestActivityReport()
+	base := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	report.Repositories[0].PullRequests[0].Comments = []Comment{
+		{ID: 3, Body: "Second reply", Timestamp: base.Add(2 * time.Hour), InReplyTo: 1},
+		{ID: 2, Body: "First reply", Timestamp: base.Add(time.Hour), InReplyTo: 1},
+		{ID: 1, Body: "Parent comment", Timestamp: base},
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	expected := "- 2023-01-01 10:00: Parent comment\n" +
+		"  - 2023-01-01 11:00: First reply\n" +
+		"  - 2023-01-01 12:00: Second reply\n"
+	if !strings.Contains(content.Content, expected) {
+		t.Errorf("Expected threaded comments %q, got:\n%s", expected, content.Content)
+	}
+
+	html, err := NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	parent := strings.Index(html.Content, "Parent comment")
+	first := strings.Index(html.Content, "<div class=\"comment reply\">\n<p>First reply")
+	second := strings.Index(html.Content, "<div class=\"comment reply\">\n<p>Second reply")
+	if parent == -1 || first < parent || second < first {
+		t.Errorf("Expected replies nested after their parent in order, got:\n%s", html.Content)
+	}
+}
+
+// TestFormatters_ReviewComments tests that inline comments render under the
+// review they were submitted with, without repeating either
+func TestFormatters_ReviewComments(t *testing.T) {
+	report := createTestActivityReport()
+	base := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	pr := &report.Repositories[0].PullRequests[0]
+	pr.IsAuthored = false
+	pr.IsReviewed = true
+	pr.Reviews = []Review{
+		{ID: 10, State: "CHANGES_REQUESTED", Body: "Needs a few fixes", Timestamp: base},
+		// GitHub records the reply below as a review of its own
+		{ID: 11, State: "COMMENTED", Timestamp: base.Add(time.Hour)},
+	}
+	pr.Comments = []Comment{
+		{ID: 100, Body: "Rename this variable", Timestamp: base, ReviewID: 10},
+		{ID: 101, Body: "Still applies", Timestamp: base.Add(time.Hour), InReplyTo: 100, ReviewID: 11},
+		{ID: 200, Body: "Unrelated note", Timestamp: base.Add(2 * time.Hour)},
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	expected := "**Reviews:**\n\n" +
+		"- 2023-01-01 10:00 (CHANGES_REQUESTED): Needs a few fixes\n" +
+		"  - 2023-01-01 10:00: Rename this variable\n" +
+		"    - 2023-01-01 11:00: Still applies\n\n" +
+		"**Comments:**\n\n" +
+		"- 2023-01-01 12:00: Unrelated note\n"
+	if !strings.Contains(content.Content, expected) {
+		t.Errorf("Expected review with its inline comments %q, got:\n%s", expected, content.Content)
+	}
+	for _, body := range []string{"Needs a few fixes", "Rename this variable", "Still applies"} {
+		if count := strings.Count(content.Content, body); count != 1 {
+			t.Errorf("Expected %q to render once, rendered %d times", body, count)
+		}
+	}
+	if strings.Contains(content.Content, "(COMMENTED)") {
+		t.Errorf("Expected the reply's review not to render separately, got:\n%s", content.Content)
+	}
+
+	html, err := NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	review := strings.Index(html.Content, "Needs a few fixes")
+	inline := strings.Index(html.Content, "Rename this variable")
+	comments := strings.Index(html.Content, "<h5>Comments</h5>")
+	if review == -1 || inline < review || comments < inline || strings.Count(html.Content, "Rename this variable") != 1 {
+		t.Errorf("Expected inline comments nested in their review, got:\n%s", html.Content)
+	}
+}
+
+func TestFormatters_ReviewCommit(t *testing.T) {
+	report := createTestActivityReport()
+	pr := &report.Repositories[0].PullRequests[0]
+	pr.IsAuthored = false
+	pr.IsReviewed = true
+	pr.Reviews = []Review{
+		{ID: 10, State: "APPROVED", Body: "Ship it", Timestamp: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC), CommitID: "0123456789abcdef"},
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "- 2023-01-01 10:00 (APPROVED at `0123456`): Ship it") {
+		t.Errorf("Expected the reviewed commit in Markdown output, got:\n%s", content.Content)
+	}
+
+	html, err := NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(html.Content, "<p><strong>APPROVED</strong> at <code>0123456</code></p>") {
+		t.Errorf("Expected the reviewed commit in HTML output, got:\n%s", html.Content)
+	}
+}
+
+func TestFormatters_CombinedPRs(t *testing.T) {
+	report := createTestActivityReport()
+	authored := report.Repositories[0].PullRequests[0]
+	authored.Commits = []Commit{{SHA: "abc123", Message: "Add feature", Timestamp: time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC)}}
+	reviewed := authored
+	reviewed.IsAuthored = false
+	reviewed.IsReviewed = true
+	reviewed.Commits = nil
+	reviewed.Reviews = []Review{{ID: 10, State: "COMMENTED", Body: "Self review", Timestamp: time.Date(2023, 1, 1, 14, 0, 0, 0, time.UTC)}}
+	report.Repositories[0].PullRequests = []PullRequest{authored, reviewed}
+	options := FormatterOptions{CombinedPRs: true}
+
+	testCases := []struct {
+		formatter ReportFormatter
+		tag       string
+		sections  []string
+	}{
+		{&MarkdownFormatter{Options: options}, "_authored, reviewed_", []string{"### Authored Pull Requests", "### Reviewed Pull Requests"}},
+		{&HTMLFormatter{Options: options}, "<p class=\"roles\">authored, reviewed</p>", []string{"<h3>Authored Pull Requests</h3>", "<h3>Reviewed Pull Requests</h3>"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.formatter.Name(), func(t *testing.T) {
+			content, err := tc.formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			if count := strings.Count(content.Content, "Test PR"); count != 1 {
+				t.Errorf("Expected the PR to appear once, appeared %d times:\n%s", count, content.Content)
+			}
+			if !strings.Contains(content.Content, tc.tag) {
+				t.Errorf("Expected the PR tagged %q, got:\n%s", tc.tag, content.Content)
+			}
+			for _, section := range tc.sections {
+				if strings.Contains(content.Content, section) {
+					t.Errorf("Expected no %q section, got:\n%s", section, content.Content)
+				}
+			}
+			if !strings.Contains(content.Content, "Add feature") || !strings.Contains(content.Content, "Self review") {
+				t.Errorf("Expected the commits and reviews of both entries, got:\n%s", content.Content)
+			}
+		})
+	}
+}
+
+func TestFormatters_ArchivedTag(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].Archived = true
+
+	markdown, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(markdown.Content, "## Repository: testorg/testrepo (archived)") {
+		t.Errorf("Expected the archived tag in Markdown output, got:\n%s", markdown.Content)
+	}
+
+	html, err := NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(html.Content, "<h2>Repository: testorg/testrepo (archived)</h2>") {
+		t.Errorf("Expected the archived tag in HTML output, got:\n%s", html.Content)
+	}
+}
+
+func TestMarkdownFormatter_Encoding(t *testing.T) {
+	formatter := &MarkdownFormatter{Options: FormatterOptions{ByteOrderMark: true, CRLF: true}}
+
+	for _, report := range []*ActivityReport{createTestActivityReport(), createEmptyActivityReport()} {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+
+		if !strings.HasPrefix(content.Content, "\xef\xbb\xbf") {
+			t.Errorf("Expected the output to start with a UTF-8 BOM, got %q", content.Content[:min(len(content.Content), 8)])
+		}
+		if strings.Count(content.Content, "\n") != strings.Count(content.Content, "\r\n") {
+			t.Errorf("Expected every line to end with CRLF, got %q", content.Content)
+		}
+	}
+
+	content, err := formatter.Format(createTestActivityReport())
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "\r\n") {
+		t.Errorf("Expected CRLF line endings, got %q", content.Content)
+	}
+
+	content, err = NewMarkdownFormatter().Format(createTestActivityReport())
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.HasPrefix(content.Content, "\xef\xbb\xbf") || strings.Contains(content.Content, "\r\n") {
+		t.Errorf("Expected no BOM or CRLF by default, got %q", content.Content)
+	}
+}
+
+// TestHTMLFormatter_Labels tests that labels render as colored badges
+func TestHTMLFormatter_Labels(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Labels = []Label{
+		{Name: "bug", Color: "d73a4a"},
+		{Name: "good first issue", Color: "7057ff"},
+		{Name: "documentation", Color: "fef2c0"},
+	}
+
+	content, err := NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	expected := []string{
+		`<span class="label" style="background-color: #d73a4a; color: #ffffff;">bug</span>`,
+		`<span class="label" style="background-color: #7057ff; color: #ffffff;">good first issue</span>`,
+		`<span class="label" style="background-color: #fef2c0; color: #000000;">documentation</span>`,
+	}
+	for _, badge := range expected {
+		if !strings.Contains(content.Content, badge) {
+			t.Errorf("Expected label badge %q, got:\n%s", badge, content.Content)
+		}
+	}
+}
+
+// TestFormatters_Locale tests that the time range renders with localized names
+func TestFormatters_Locale(t *testing.T) {
+	report := createTestActivityReport()
+
+	formatters := []ReportFormatter{
+		&MarkdownFormatter{Options: FormatterOptions{Locale: language.French}},
+		&HTMLFormatter{Options: FormatterOptions{Locale: language.MustParse("fr-CA")}},
+	}
+	for _, formatter := range formatters {
+		t.Run(formatter.Name(), func(t *testing.T) {
+			content, err := formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			for _, expected := range []string{"dimanche, 1 janvier 2023", "lundi, 2 janvier 2023"} {
+				if !strings.Contains(content.Content, expected) {
+					t.Errorf("Expected %q in output, got:\n%s", expected, content.Content)
+				}
+			}
+		})
+	}
+
+	// Without a locale the time range stays an ISO date
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "2023-01-01 to 2023-01-02") {
+		t.Errorf("Expected ISO dates without a locale, got:\n%s", content.Content)
+	}
+}
+
+// TestFormatters_OmitHeader tests that the header can be left out for embedding
+func TestFormatters_OmitHeader(t *testing.T) {
+	report := createTestActivityReport()
+	options := FormatterOptions{OmitHeader: true}
+
+	testCases := []struct {
+		formatter ReportFormatter
+		header    string
+		section   string
+	}{
+		{&MarkdownFormatter{Options: options}, "# GitHub Activity Report", "## Repository: testorg/testrepo"},
+		{&HTMLFormatter{Options: options}, "<h1>GitHub Activity Report</h1>", "<h2>Repository: testorg/testrepo</h2>"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.formatter.Name(), func(t *testing.T) {
+			content, err := tc.formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			if strings.Contains(content.Content, tc.header) || strings.Contains(content.Content, "**User:**") || strings.Contains(content.Content, "<strong>User:</strong>") {
+				t.Errorf("Expected no report header, got:\n%s", content.Content)
+			}
+			if !strings.Contains(content.Content, tc.section) || !strings.Contains(content.Content, "Test PR") {
+				t.Errorf("Expected repository section to remain, got:\n%s", content.Content)
+			}
+		})
+	}
+}
+
+func TestFormatters_RelativeTimes(t *testing.T) {
+	now := time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC)
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Commits = []Commit{
+		{SHA: "abc123", Message: "Recent work", Timestamp: now.Add(-2 * time.Hour)},
+		{SHA: "def456", Message: "Old work", Timestamp: now.Add(-30 * 24 * time.Hour)},
+	}
+	options := FormatterOptions{RelativeTimes: true, Now: now}
+
+	testCases := []struct {
+		formatter ReportFormatter
+		recent    string
+		old       string
+	}{
+		{&MarkdownFormatter{Options: options}, "- 2h ago: Recent work", "- 2022-12-11 12:00: Old work"},
+		{&HTMLFormatter{Options: options}, "<p class=\"timestamp\">2h ago</p>", "<p class=\"timestamp\">2022-12-11 12:00:00</p>"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.formatter.Name(), func(t *testing.T) {
+			content, err := tc.formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			if !strings.Contains(content.Content, tc.recent) {
+				t.Errorf("Expected a relative timestamp %q, got:\n%s", tc.recent, content.Content)
+			}
+			if !strings.Contains(content.Content, tc.old) {
+				t.Errorf("Expected an absolute timestamp %q past the threshold, got:\n%s", tc.old, content.Content)
+			}
+		})
+	}
+}
+
+func TestFormatters_Footer(t *testing.T) {
+	now := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+	report := createTestActivityReport()
+	expected := "Generated at 2023-01-02T09:00:00Z by daiv-github " + Version + " for 2023-01-01 to 2023-01-02"
+
+	for _, formatter := range []ReportFormatter{
+		&MarkdownFormatter{Options: FormatterOptions{Footer: true, Now: now}},
+		&HTMLFormatter{Options: FormatterOptions{Footer: true, Now: now}},
+	} {
+		t.Run(formatter.Name(), func(t *testing.T) {
+			content, err := formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+			if !strings.Contains(content.Content, expected) {
+				t.Errorf("Expected footer %q, got:\n%s", expected, content.Content)
+			}
+		})
+	}
+
+	content, err := (&MarkdownFormatter{}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(content.Content, "Generated at") {
+		t.Errorf("Expected no footer by default, got:\n%s", content.Content)
+	}
+}
+
+// TestFormatters_ClosedByUser tests that closed PRs render in their own section
+func TestFormatters_ClosedByUser(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests = append(report.Repositories[0].PullRequests, PullRequest{
+		Number:         200,
+		Title:          "Abandoned PR",
+		State:          "closed",
+		Author:         "otheruser",
+		IsClosedByUser: true,
+	})
+
+	formatters := map[ReportFormatter]string{
+		NewMarkdownFormatter(): "### Closed Pull Requests",
+		NewHTMLFormatter():     "<h3>Closed Pull Requests</h3>",
+	}
+	for formatter, section := range formatters {
+		t.Run(formatter.Name(), func(t *testing.T) {
+			content, err := formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			index := strings.Index(content.Content, section)
+			if index == -1 || !strings.Contains(content.Content[index:], "Abandoned PR") {
+				t.Errorf("Expected the closed PR under %q, got:\n%s", section, content.Content)
+			}
+		})
+	}
+}
+
+// TestFormatters_SummaryTruncation tests that the summary caps the listed repositories
+func TestFormatters_SummaryTruncation(t *testing.T) {
+	report := createTestActivityReport()
+	pr := report.Repositories[0].PullRequests[0]
+	report.Repositories = nil
+	for i := 0; i < 50; i++ {
+		repo := Repository{Name: fmt.Sprintf("repo%02d", i), Organization: "testorg"}
+		// Give later repositories more activity so ordering is by activity
+		for j := 0; j <= i%5; j++ {
+			repo.PullRequests = append(repo.PullRequests, pr)
+		}
+		report.Repositories = append(report.Repositories, repo)
+	}
+	report.Repositories = append(report.Repositories, Repository{Name: "idle", Organization: "testorg"})
+
+	options := FormatterOptions{IncludeSummary: true, MaxReposInSummary: 10}
+	testCases := []struct {
+		formatter ReportFormatter
+		note      string
+		item      string
+	}{
+		{&MarkdownFormatter{Options: options}, "- and 40 more\n", "- testorg/%s: "},
+		{&HTMLFormatter{Options: options}, "<li>and 40 more</li>", "<li>testorg/%s: "},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.formatter.Name(), func(t *testing.T) {
+			content, err := tc.formatter.Format(report)
+			if err != nil {
+				t.Fatalf("Error formatting report: %v", err)
+			}
+
+			if !strings.Contains(content.Content, tc.note) {
+				t.Errorf("Expected truncation note %q, got:\n%s", tc.note, content.Content)
+			}
+
+			listed := 0
+			for _, repo := range report.Repositories {
+				if strings.Contains(content.Content, fmt.Sprintf(tc.item, repo.Name)) {
+					listed++
+					if len(repo.PullRequests) != 5 {
+						t.Errorf("Expected only the most active repositories listed, got %s", repo.Name)
+					}
+				}
+			}
+			if listed != 10 {
+				t.Errorf("Expected 10 repositories in the summary, got %d", listed)
+			}
+		})
+	}
+}
+
+// TestTeamsFormatter tests that the Teams formatter emits a valid Adaptive Card
+func TestTeamsFormatter(t *testing.T) {
+	formatter := NewTeamsFormatter()
+	if formatter.Name() != "teams" {
+		t.Errorf("Expected formatter name to be 'teams', got '%s'", formatter.Name())
+	}
+
+	content, err := formatter.Format(createTestActivityReport())
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	var card struct {
+		Type    string `json:"type"`
+		Version string `json:"version"`
+		Body    []struct {
+			Type  string `json:"type"`
+			Items []struct {
+				Type  string `json:"type"`
+				Text  string `json:"text"`
+				Facts []struct {
+					Title string `json:"title"`
+					Value string `json:"value"`
+				} `json:"facts"`
+			} `json:"items"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(content.Content), &card); err != nil {
+		t.Fatalf("Error parsing adaptive card: %v", err)
+	}
+
+	if card.Type != "AdaptiveCard" || card.Version != "1.4" {
+		t.Errorf("Expected an AdaptiveCard version 1.4, got %s version %s", card.Type, card.Version)
+	}
+
+	found := false
+	for _, element := range card.Body {
+		if element.Type != "Container" || len(element.Items) != 2 {
+			continue
+		}
+		if element.Items[0].Text == "testorg/testrepo" && len(element.Items[1].Facts) == 1 &&
+			strings.Contains(element.Items[1].Facts[0].Value, "[Test PR](https://github.com/testorg/testrepo/pull/123)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a section for testorg/testrepo listing its PR, got:\n%s", content.Content)
+	}
+
+	// An empty report is still a valid card
+	emptyContent, err := formatter.Format(createEmptyActivityReport())
+	if err != nil {
+		t.Fatalf("Error formatting empty report: %v", err)
+	}
+	if !json.Valid([]byte(emptyContent.Content)) {
+		t.Errorf("Expected valid JSON for an empty report, got:\n%s", emptyContent.Content)
+	}
+
+	// Skipped repositories are listed even when nothing else is
+	skippedReport := createEmptyActivityReport()
+	skippedReport.SkippedRepositories = []SkippedRepo{{Name: "testorg/private", Reason: "not found", Kind: ErrorKindNotFound}}
+	skippedContent, err := formatter.Format(skippedReport)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	for _, want := range []string{`"Skipped repositories"`, `"testorg/private (not_found)"`, `"not found"`} {
+		if !strings.Contains(skippedContent.Content, want) {
+			t.Errorf("Expected %s in the skipped repositories section, got:\n%s", want, skippedContent.Content)
+		}
+	}
+}
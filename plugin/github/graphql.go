@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// reviewThreadsQuery pages through the review threads of a pull request.
+// Thread resolution is only exposed by the GraphQL API.
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        nodes { isResolved }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// projectStatusQuery reads the Status field of the project (v2) items a pull
+// request belongs to. A pull request can be on several boards.
+const projectStatusQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      projectItems(first: 20) {
+        nodes {
+          fieldValueByName(name: "Status") {
+            ... on ProjectV2ItemFieldSingleSelectValue { name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// graphQLRequest is the body of a GraphQL API request
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// graphQLError is a single entry of a GraphQL error response
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// reviewThreadsResponse is the response to reviewThreadsQuery
+type reviewThreadsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						IsResolved bool `json:"isResolved"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// projectStatusResponse is the response to projectStatusQuery. Items without
+// a Status value have a null fieldValueByName.
+type projectStatusResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				ProjectItems struct {
+					Nodes []struct {
+						FieldValueByName *struct {
+							Name string `json:"name"`
+						} `json:"fieldValueByName"`
+					} `json:"nodes"`
+				} `json:"projectItems"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// joinGraphQLErrors joins the messages of a GraphQL error response
+func joinGraphQLErrors(errs []graphQLError) string {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// getUnresolvedThreads counts the unresolved review threads of a pull request
+func (r *GitHubAPIRepository) getUnresolvedThreads(ctx context.Context, org string, repo string, prNumber int) (int, error) {
+	variables := map[string]any{
+		"owner":  org,
+		"name":   repo,
+		"number": prNumber,
+	}
+	
+	unresolved := 0
+	for {
+		req, err := r.client.NewRequest("POST", "graphql", graphQLRequest{Query: reviewThreadsQuery, Variables: variables})
+		if err != nil {
+			return 0, fmt.Errorf("failed to build review threads query for PR #%d: %w", prNumber, err)
+		}
+		
+		var result reviewThreadsResponse
+		if _, err := r.client.Do(ctx, req, &result); err != nil {
+			return 0, fmt.Errorf("failed to query review threads for PR #%d: %w", prNumber, wrapAPIError(err))
+		}
+		
+		// GraphQL reports query errors with a successful status code
+		if len(result.Errors) > 0 {
+			return 0, fmt.Errorf("failed to query review threads for PR #%d: %s", prNumber, joinGraphQLErrors(result.Errors))
+		}
+		
+		threads := result.Data.Repository.PullRequest.ReviewThreads
+		for _, thread := range threads.Nodes {
+			if !thread.IsResolved {
+				unresolved++
+			}
+		}
+		
+		if !threads.PageInfo.HasNextPage {
+			return unresolved, nil
+		}
+		variables["cursor"] = threads.PageInfo.EndCursor
+	}
+}
+
+// getProjectStatus returns the project board status of a pull request, such
+// as "In Review". Statuses from several boards are joined in board order,
+// and a pull request on no board, or without a status, has none.
+func (r *GitHubAPIRepository) getProjectStatus(ctx context.Context, org string, repo string, prNumber int) (string, error) {
+	variables := map[string]any{
+		"owner":  org,
+		"name":   repo,
+		"number": prNumber,
+	}
+	req, err := r.client.NewRequest("POST", "graphql", graphQLRequest{Query: projectStatusQuery, Variables: variables})
+	if err != nil {
+		return "", fmt.Errorf("failed to build project status query for PR #%d: %w", prNumber, err)
+	}
+	
+	var result projectStatusResponse
+	if _, err := r.client.Do(ctx, req, &result); err != nil {
+		return "", fmt.Errorf("failed to query project status for PR #%d: %w", prNumber, wrapAPIError(err))
+	}
+	
+	// GraphQL reports query errors with a successful status code
+	if len(result.Errors) > 0 {
+		return "", fmt.Errorf("failed to query project status for PR #%d: %s", prNumber, joinGraphQLErrors(result.Errors))
+	}
+	
+	var statuses []string
+	for _, item := range result.Data.Repository.PullRequest.ProjectItems.Nodes {
+		if item.FieldValueByName == nil || item.FieldValueByName.Name == "" || slices.Contains(statuses, item.FieldValueByName.Name) {
+			continue
+		}
+		statuses = append(statuses, item.FieldValueByName.Name)
+	}
+	
+	return strings.Join(statuses, ", "), nil
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// heatmapColors shade heatmap cells from no activity to the busiest hour,
+// after GitHub's contribution graph
+var heatmapColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// heatmapDays orders the heatmap rows from Monday to Sunday
+var heatmapDays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// activityHeatmap counts the commits, reviews, and comments of a report by
+// weekday and hour of day, in the time zone of each timestamp. Pull requests
+// listed in more than one section are counted once.
+func activityHeatmap(report *ActivityReport) [7][24]int {
+	var counts [7][24]int
+	add := func(t time.Time) {
+		if !t.IsZero() {
+			counts[t.Weekday()][t.Hour()]++
+		}
+	}
+	
+	for _, repo := range report.Repositories {
+		for _, pr := range combinePullRequests(repo.PullRequests) {
+			for _, commit := range pr.Commits {
+				add(commit.Timestamp)
+			}
+			for _, review := range pr.Reviews {
+				add(review.Timestamp)
+			}
+			for _, comment := range pr.Comments {
+				add(comment.Timestamp)
+			}
+		}
+		for _, commit := range repo.DirectCommits {
+			add(commit.Timestamp)
+		}
+	}
+	
+	return counts
+}
+
+// heatmapColor returns the shade of a cell with count items when the busiest
+// cell has max items: the lightest for none, and darker shades for each
+// quarter of max
+func heatmapColor(count int, max int) string {
+	if count == 0 || max == 0 {
+		return heatmapColors[0]
+	}
+	levels := len(heatmapColors) - 1
+	return heatmapColors[(count*levels+max-1)/max]
+}
+
+// writeHTMLHeatmap writes the report's activity as a weekday by hour grid of
+// table cells shaded by their number of commits, reviews, and comments
+func writeHTMLHeatmap(sb *strings.Builder, report *ActivityReport) {
+	counts := activityHeatmap(report)
+	max := 0
+	for _, day := range counts {
+		for _, count := range day {
+			if count > max {
+				max = count
+			}
+		}
+	}
+	
+	sb.WriteString("<h2>Activity Heatmap</h2>\n")
+	sb.WriteString("<table class=\"heatmap\" style=\"border-spacing: 2px;\">\n<tr><th></th>")
+	for hour := 0; hour < 24; hour++ {
+		sb.WriteString(fmt.Sprintf("<th style=\"font-size: 10px; font-weight: normal;\">%02d</th>", hour))
+	}
+	sb.WriteString("</tr>\n")
+	
+	for _, day := range heatmapDays {
+		sb.WriteString(fmt.Sprintf("<tr><th style=\"font-size: 10px; font-weight: normal; text-align: right;\">%s</th>", day.String()[:3]))
+		for hour := 0; hour < 24; hour++ {
+			count := counts[day][hour]
+			sb.WriteString(fmt.Sprintf("<td style=\"width: 12px; height: 12px; background-color: %s;\" title=\"%s %02d:00: %d\"></td>",
+				heatmapColor(count, max), day.String()[:3], hour, count))
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</table>\n")
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLFormatter_Heatmap(t *testing.T) {
+	// 2023-01-02 is a Monday
+	busy := time.Date(2023, 1, 2, 14, 10, 0, 0, time.UTC)
+	quiet := time.Date(2023, 1, 3, 9, 30, 0, 0, time.UTC)
+
+	report := createTestActivityReport()
+	pr := &report.Repositories[0].PullRequests[0]
+	pr.Commits = []Commit{
+		{SHA: "a1", Message: "first", Timestamp: busy},
+		{SHA: "a2", Message: "second", Timestamp: busy.Add(10 * time.Minute)},
+		{SHA: "a3", Message: "third", Timestamp: busy.Add(20 * time.Minute)},
+	}
+	pr.Reviews = []Review{{ID: 1, State: "APPROVED", Timestamp: busy.Add(30 * time.Minute)}}
+	pr.Comments = []Comment{{ID: 2, Body: "later", Timestamp: quiet}}
+
+	content, err := (&HTMLFormatter{Options: FormatterOptions{Heatmap: true}}).Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	cells := map[string]string{
+		"Mon 14:00: 4": heatmapColors[4],
+		"Tue 09:00: 1": heatmapColors[1],
+		"Wed 09:00: 0": heatmapColors[0],
+	}
+	for title, color := range cells {
+		cell := "background-color: " + color + ";\" title=\"" + title + "\""
+		if !strings.Contains(content.Content, cell) {
+			t.Errorf("Expected the cell %q shaded %s, got:\n%s", title, color, content.Content)
+		}
+	}
+	if count := strings.Count(content.Content, "<td "); count != 7*24 {
+		t.Errorf("Expected a 7 by 24 grid, got %d cells", count)
+	}
+
+	content, err = NewHTMLFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Contains(content.Content, "Activity Heatmap") {
+		t.Error("Expected no heatmap by default")
+	}
+}
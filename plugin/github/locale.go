@@ -0,0 +1,79 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// dateNames holds the weekday and month names of a language
+type dateNames struct {
+	weekdays [7]string  // Indexed by time.Weekday
+	months   [12]string // Indexed by time.Month - 1
+}
+
+// localizedDateNames lists the languages dates can be rendered in. The first
+// entry is the fallback for unsupported locales.
+var localizedDateNames = []struct {
+	tag   language.Tag
+	names dateNames
+}{
+	{language.English, dateNames{
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:   [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	}},
+	{language.French, dateNames{
+		weekdays: [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		months:   [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	}},
+	{language.German, dateNames{
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		months:   [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	}},
+	{language.Spanish, dateNames{
+		weekdays: [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		months:   [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	}},
+	{language.Portuguese, dateNames{
+		weekdays: [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		months:   [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	}},
+	{language.Italian, dateNames{
+		weekdays: [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		months:   [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	}},
+	{language.Dutch, dateNames{
+		weekdays: [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		months:   [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+	}},
+}
+
+// dateNamesMatcher matches a requested locale, such as fr-CA, to the closest
+// supported language
+var dateNamesMatcher = func() language.Matcher {
+	tags := make([]language.Tag, 0, len(localizedDateNames))
+	for _, entry := range localizedDateNames {
+		tags = append(tags, entry.tag)
+	}
+	return language.NewMatcher(tags)
+}()
+
+// formatLocalizedDate renders a date in a "Monday, 2 January 2006" style
+// using the weekday and month names of the locale. Unsupported locales fall
+// back to English.
+func formatLocalizedDate(t time.Time, locale language.Tag) string {
+	_, index, _ := dateNamesMatcher.Match(locale)
+	names := localizedDateNames[index].names
+	
+	return fmt.Sprintf("%s, %d %s %d", names.weekdays[t.Weekday()], t.Day(), names.months[t.Month()-1], t.Year())
+}
+
+// formatReportDate renders a report date, localized when a locale is set and
+// as an ISO date otherwise
+func formatReportDate(t time.Time, locale language.Tag) string {
+	if locale == language.Und {
+		return t.Format("2006-01-02")
+	}
+	return formatLocalizedDate(t, locale)
+}
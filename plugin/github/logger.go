@@ -0,0 +1,12 @@
+package github
+
+import (
+	"log"
+	"os"
+)
+
+// Logger receives diagnostics about repositories and pull requests skipped
+// after an error, and other failures that do not abort a report. It writes to
+// stdout by default; replace it, for example with log.New(io.Discard, "", 0),
+// to redirect or silence them.
+var Logger = log.New(os.Stdout, "", 0)
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+// TestMain silences the package logger so partial-failure tests do not
+// clutter the test output
+func TestMain(m *testing.M) {
+	Logger = log.New(io.Discard, "", 0)
+	os.Exit(m.Run())
+}
+
+// setLogger replaces the package logger for the duration of a test
+func setLogger(t *testing.T, logger *log.Logger) {
+	t.Helper()
+
+	previous := Logger
+	Logger = logger
+	t.Cleanup(func() { Logger = previous })
+}
+
+func TestLogger_RepositoryErrors(t *testing.T) {
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return nil, errors.New("repository unavailable")
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+
+	getReport := func() {
+		service := NewActivityService(mockRepo, config)
+		if _, err := service.GetActivityReport(plug.TimeRange{
+			Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		}); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	t.Run("Injected logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		setLogger(t, log.New(&buf, "", 0))
+
+		getReport()
+
+		if !strings.Contains(buf.String(), "Error processing repository repo1:") {
+			t.Errorf("Expected the repository error to be logged, got %q", buf.String())
+		}
+	})
+
+	t.Run("Discard", func(t *testing.T) {
+		setLogger(t, log.New(io.Discard, "", 0))
+
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Error creating pipe: %v", err)
+		}
+		stdout := os.Stdout
+		os.Stdout = w
+		getReport()
+		os.Stdout = stdout
+		w.Close()
+
+		output, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Error reading stdout: %v", err)
+		}
+		if len(output) != 0 {
+			t.Errorf("Expected nothing written to stdout, got %q", output)
+		}
+	})
+}
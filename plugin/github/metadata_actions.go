@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+// MetadataAction is a change the user made to a pull request's metadata
+// rather than its code or discussion, such as adding a label
+type MetadataAction struct {
+	Event     string    `json:"event"`            // labeled, unlabeled, renamed, assigned, or unassigned
+	Detail    string    `json:"detail,omitempty"` // The label, new title, or assignee
+	Timestamp time.Time `json:"timestamp"`
+	Range     string    `json:"range,omitempty"` // Label of the time range in a multi-range report
+}
+
+// metadataEvents are the issue events that count as metadata actions
+var metadataEvents = []string{"labeled", "unlabeled", "renamed", "assigned", "unassigned"}
+
+// metadataEventActions are the actions of the pull request events in a
+// user's events feed that may be metadata actions. Edits include retitles.
+var metadataEventActions = []string{"labeled", "unlabeled", "edited", "assigned", "unassigned"}
+
+// Description renders the action, such as "labeled bug" or "renamed to Fix
+// login"
+func (a MetadataAction) Description() string {
+	switch {
+	case a.Detail == "":
+		return a.Event
+	case a.Event == "renamed":
+		return fmt.Sprintf("renamed to %s", a.Detail)
+	}
+	return fmt.Sprintf("%s %s", a.Event, a.Detail)
+}
+
+// getMetadataActions lists the labels, retitles, and assignments the user
+// made to a pull request within the time range, according to its issue
+// events
+func (r *GitHubAPIRepository) getMetadataActions(ctx context.Context, org string, repo string, prNumber int, timeRange TimeRange) ([]MetadataAction, error) {
+	var actions []MetadataAction
+	opts := &externalGithub.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := r.issues.ListIssueEvents(ctx, org, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events for PR #%d: %w", prNumber, wrapAPIError(err))
+		}
+
+		for _, event := range events {
+			if event.GetActor().GetLogin() != r.username || !timeRange.IsInRange(event.GetCreatedAt().Time) {
+				continue
+			}
+			if action, ok := metadataActionFromEvent(event); ok {
+				actions = append(actions, action)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	slices.SortStableFunc(actions, func(a, b MetadataAction) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return actions, nil
+}
+
+// metadataActionFromEvent maps an issue event to a metadata action, and
+// reports false for events that do not change metadata
+func metadataActionFromEvent(event *externalGithub.IssueEvent) (MetadataAction, bool) {
+	if !slices.Contains(metadataEvents, event.GetEvent()) {
+		return MetadataAction{}, false
+	}
+
+	action := MetadataAction{
+		Event:     event.GetEvent(),
+		Timestamp: event.GetCreatedAt().Time,
+	}
+	switch action.Event {
+	case "labeled", "unlabeled":
+		action.Detail = event.GetLabel().GetName()
+	case "renamed":
+		action.Detail = event.GetRename().GetTo()
+	case "assigned", "unassigned":
+		action.Detail = event.GetAssignee().GetLogin()
+	}
+	return action, true
+}
+
+// searchMetadataEditedPullRequests finds the pull requests other than found
+// whose labels, title, or assignees the user changed within the time range.
+// The search API has no qualifier for these changes, so candidates come from
+// the user's events feed and are confirmed by their issue events. The feed
+// only covers the last 90 days and 300 events.
+func (r *GitHubAPIRepository) searchMetadataEditedPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions, found []PullRequest) ([]PullRequest, error) {
+	candidates, err := r.metadataEditCandidates(ctx, org, repo, timeRange)
+	if err != nil {
+		return nil, err
+	}
+	
+	var prs []PullRequest
+	for _, number := range candidates {
+		if slices.ContainsFunc(found, func(pr PullRequest) bool { return pr.Number == number }) {
+			continue
+		}
+		
+		actions, err := r.getMetadataActions(ctx, org, repo, number, timeRange)
+		if err != nil {
+			return nil, err
+		}
+		if len(actions) == 0 {
+			continue
+		}
+		
+		// The feed holds the pull request as of the event, so its state,
+		// title, and labels may have changed since
+		details, err := r.getPullRequestDetails(ctx, org, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		
+		pr := pullRequestFromDetails(details)
+		pr.ExtractedFields = extractBodyFields(details.GetBody(), options.BodyFieldPatterns)
+		pr.IsMetadataEdited = true
+		pr.MetadataActions = actions
+		prs = append(prs, pr)
+	}
+	
+	return prs, nil
+}
+
+// metadataEditEvent is a pull request the user's events feed shows them
+// labeling, editing, or assigning
+type metadataEditEvent struct {
+	repo   string // Full name in lower case, e.g. "org/repo"
+	number int
+}
+
+// metadataEditCandidates lists the numbers of the pull requests of the
+// repository that the user's events feed shows them labeling, editing, or
+// assigning within the time range
+func (r *GitHubAPIRepository) metadataEditCandidates(ctx context.Context, org string, repo string, timeRange TimeRange) ([]int, error) {
+	events, err := r.metadataEditEvents(ctx, timeRange)
+	if err != nil {
+		return nil, err
+	}
+	
+	fullName := strings.ToLower(fmt.Sprintf("%s/%s", org, repo))
+	var candidates []int
+	for _, event := range events {
+		if event.repo == fullName {
+			candidates = append(candidates, event.number)
+		}
+	}
+	return candidates, nil
+}
+
+// metadataEditEvents returns the pull requests of every repository that the
+// user's events feed shows them labeling, editing, or assigning within the
+// time range. The feed is not per repository, so it is read once per time
+// range and shared by all of them.
+func (r *GitHubAPIRepository) metadataEditEvents(ctx context.Context, timeRange TimeRange) ([]metadataEditEvent, error) {
+	key := timeRange.Start.String() + "/" + timeRange.End.String()
+	
+	r.feedMu.Lock()
+	events, ok := r.feed[key]
+	r.feedMu.Unlock()
+	if ok {
+		return events, nil
+	}
+	
+	// Repositories processed concurrently wait for the first read rather
+	// than each reading the feed
+	result, err, _ := r.feedGroup.Do(key, func() (any, error) {
+		events, err := r.listMetadataEditEvents(ctx, timeRange)
+		if err != nil {
+			return nil, err
+		}
+		
+		r.feedMu.Lock()
+		if r.feed == nil {
+			r.feed = make(map[string][]metadataEditEvent)
+		}
+		r.feed[key] = events
+		r.feedMu.Unlock()
+		return events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]metadataEditEvent), nil
+}
+
+// listMetadataEditEvents reads the user's events feed for metadataEditEvents.
+// The feed is newest first, so paging stops at the first event before the
+// range.
+func (r *GitHubAPIRepository) listMetadataEditEvents(ctx context.Context, timeRange TimeRange) ([]metadataEditEvent, error) {
+	seen := make(map[metadataEditEvent]bool)
+	var events []metadataEditEvent
+	
+	opts := &externalGithub.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := r.activity.ListEventsPerformedByUser(ctx, r.username, false, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events for %s: %w", r.username, wrapAPIError(err))
+		}
+		
+		for _, event := range page {
+			createdAt := event.GetCreatedAt().Time
+			if createdAt.Before(timeRange.Start) {
+				return events, nil
+			}
+			if event.GetType() != "PullRequestEvent" || !timeRange.IsInRange(createdAt) {
+				continue
+			}
+			
+			payload, err := event.ParsePayload()
+			if err != nil {
+				continue
+			}
+			prEvent, ok := payload.(*externalGithub.PullRequestEvent)
+			if !ok || !slices.Contains(metadataEventActions, prEvent.GetAction()) {
+				continue
+			}
+			
+			candidate := metadataEditEvent{repo: strings.ToLower(event.GetRepo().GetName()), number: prEvent.GetNumber()}
+			if seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+			events = append(events, candidate)
+		}
+		
+		if resp.NextPage == 0 {
+			return events, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
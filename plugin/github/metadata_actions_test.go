@@ -0,0 +1,150 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestGitHubAPIRepository_MetadataActions(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/users/testuser/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/issues/1/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"event": "labeled", "actor": map[string]any{"login": "testuser"}, "label": map[string]any{"name": "bug"}, "created_at": "2023-01-01T12:00:00Z"},
+			{"event": "labeled", "actor": map[string]any{"login": "otheruser"}, "label": map[string]any{"name": "urgent"}, "created_at": "2023-01-01T12:30:00Z"},
+			{"event": "labeled", "actor": map[string]any{"login": "testuser"}, "label": map[string]any{"name": "stale"}, "created_at": "2022-12-01T12:00:00Z"},
+			{"event": "subscribed", "actor": map[string]any{"login": "testuser"}, "created_at": "2023-01-01T13:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeMetadataActions = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+	actions := prs[0].MetadataActions
+	if len(actions) != 1 || actions[0].Event != "labeled" || actions[0].Detail != "bug" {
+		t.Fatalf("Expected only the user's label action in range, got %+v", actions)
+	}
+
+	// The label is the pull request's only activity, yet it is kept
+	service := NewActivityService(&MockGitHubRepository{}, &GitHubConfig{AuthoredActivity: ActivityCriteria{HasCommits: true, HasComments: true}})
+	if kept := service.filterByActivity(prs, testTimeRange()); len(kept) != 1 {
+		t.Errorf("Expected the pull request with only a metadata action to be kept, got %+v", kept)
+	}
+
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].MetadataActions = actions
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "**Metadata actions:**\n\n- 2023-01-01 12:00: labeled bug") {
+		t.Errorf("Expected the label action under metadata actions, got:\n%s", content.Content)
+	}
+}
+
+func TestGitHubAPIRepository_MetadataEditedPullRequests(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	// The user did nothing on the pull request other than label it, so no
+	// search matches it
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult())
+	})
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+	// The feed covers every repository, so it is read once for both
+	var feedReads atomic.Int32
+	mux.HandleFunc("/users/testuser/events", func(w http.ResponseWriter, r *http.Request) {
+		feedReads.Add(1)
+		pullRequest := map[string]any{"number": 7, "title": "Fix typo", "state": "open", "html_url": "https://github.com/testorg/repo1/pull/7", "user": map[string]any{"login": "otheruser"}}
+		writeJSON(t, w, []map[string]any{
+			{"type": "PullRequestEvent", "repo": map[string]any{"name": "testorg/other"}, "created_at": "2023-01-01T13:00:00Z", "payload": map[string]any{"action": "labeled", "number": 3, "pull_request": map[string]any{"number": 3}}},
+			{"type": "PullRequestEvent", "repo": map[string]any{"name": "testorg/repo1"}, "created_at": "2023-01-01T12:00:00Z", "payload": map[string]any{"action": "labeled", "number": 7, "pull_request": pullRequest}},
+			{"type": "PullRequestEvent", "repo": map[string]any{"name": "testorg/repo1"}, "created_at": "2022-12-31T12:00:00Z", "payload": map[string]any{"action": "labeled", "number": 8, "pull_request": map[string]any{"number": 8}}},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/issues/7/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"event": "labeled", "actor": map[string]any{"login": "testuser"}, "label": map[string]any{"name": "bug"}, "created_at": "2023-01-01T12:00:00Z"},
+		})
+	})
+	// The pull request was retitled and merged after the label was added
+	mux.HandleFunc("/repos/testorg/repo1/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"number": 7, "title": "Fix typos in docs", "state": "closed", "merged_at": "2023-01-01T15:00:00Z", "html_url": "https://github.com/testorg/repo1/pull/7", "user": map[string]any{"login": "otheruser"}})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeMetadataActions = true
+
+	config := &GitHubConfig{
+		Username:         "testuser",
+		Organization:     "testorg",
+		Repositories:     []string{"repo1", "repo2"},
+		QueryOptions:     options,
+		AuthoredActivity: ActivityCriteria{HasCommits: true, HasComments: true},
+	}
+	report, err := NewActivityService(NewGitHubAPIRepository(client, "testuser"), config).GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	var prs []PullRequest
+	for _, repository := range report.Repositories {
+		prs = append(prs, repository.PullRequests...)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("Expected only the labeled pull request, got %+v", report.Repositories)
+	}
+	pr := prs[0]
+	if pr.Number != 7 || !pr.IsMetadataEdited || pr.IsAuthored || pr.IsReviewed {
+		t.Errorf("Expected pull request #7 found by its metadata action alone, got %+v", pr)
+	}
+	if pr.Title != "Fix typos in docs" || pr.State != "merged" {
+		t.Errorf("Expected the current title and state rather than those of the event, got %q %q", pr.Title, pr.State)
+	}
+	if reads := feedReads.Load(); reads != 1 {
+		t.Errorf("Expected the events feed read once, got %d reads", reads)
+	}
+	if len(pr.MetadataActions) != 1 || pr.MetadataActions[0].Description() != "labeled bug" {
+		t.Errorf("Expected the label action, got %+v", pr.MetadataActions)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "### Pull Requests with Metadata Changes\n\n") || !strings.Contains(content.Content, "Fix typos in docs") {
+		t.Errorf("Expected the pull request under metadata changes, got:\n%s", content.Content)
+	}
+}
@@ -0,0 +1,42 @@
+package github
+
+import "net/http"
+
+// Middleware decorates the transport the GitHub client sends requests
+// through, such as to log, measure, or authenticate them
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// chainMiddleware wraps base with each middleware in order, so the last
+// middleware is the outermost and sees each request first. A nil base uses
+// http.DefaultTransport.
+func chainMiddleware(base http.RoundTripper, middleware ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for _, m := range middleware {
+		base = m(base)
+	}
+	return base
+}
+
+// dumpMiddleware dumps API responses to dir, as newDumpTransport does
+func dumpMiddleware(dir string) Middleware {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return newDumpTransport(base, dir)
+	}
+}
+
+// rateLimitMiddleware limits the request rate, as newRateLimitTransport does
+func rateLimitMiddleware(requestsPerSecond float64, burst int) Middleware {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return newRateLimitTransport(base, requestsPerSecond, burst)
+	}
+}
+
+// rateLimitStrategyMiddleware handles rate limit exhaustion, as
+// newRateLimitStrategyTransport does
+func rateLimitStrategyMiddleware(strategy string) Middleware {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return newRateLimitStrategyTransport(base, strategy)
+	}
+}
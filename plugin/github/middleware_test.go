@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewGitHubClient_Middleware(t *testing.T) {
+	testClient, mux := newTestClient(t)
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls = append(calls, name+" "+req.URL.Path)
+				mu.Unlock()
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	githubClient, err := NewGitHubClient(&GitHubConfig{
+		Username:   "testuser",
+		Token:      "token",
+		RateLimit:  1000,
+		Middleware: []Middleware{record("inner"), record("outer")},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	githubClient.client.BaseURL = testClient.BaseURL
+
+	for i := 0; i < 2; i++ {
+		if _, err := githubClient.Ping(context.Background()); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	want := []string{"outer /user", "inner /user", "outer /user", "inner /user"}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Expected call %d to be %q, got %q", i, want[i], calls[i])
+		}
+	}
+}
@@ -1,17 +1,55 @@
 package github
 
+import "context"
+
 // MockGitHubRepository is a mock implementation of GitHubRepository for testing
 type MockGitHubRepository struct {
 	MockGetUser        func() (*User, error)
 	MockGetPullRequests func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error)
+	MockSearchPullRequests func(query string, timeRange TimeRange, options QueryOptions) ([]Repository, error)
+	MockGetRepositoryMetadata func(org string, repo string) (RepositoryMetadata, error)
+	MockGetPullRequest func(org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error)
+	MockGetDirectCommits func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]Commit, error)
+	MockGetAssignedIssues func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]Issue, error)
+	MockForUser func(username string) GitHubRepository
 }
 
 // GetUser implements the GitHubRepository interface
-func (m *MockGitHubRepository) GetUser() (*User, error) {
+func (m *MockGitHubRepository) GetUser(ctx context.Context) (*User, error) {
 	return m.MockGetUser()
 }
 
 // GetPullRequests implements the GitHubRepository interface
-func (m *MockGitHubRepository) GetPullRequests(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+func (m *MockGitHubRepository) GetPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
 	return m.MockGetPullRequests(org, repo, timeRange, options)
 } 
+
+// SearchPullRequests implements the GitHubRepository interface
+func (m *MockGitHubRepository) SearchPullRequests(ctx context.Context, query string, timeRange TimeRange, options QueryOptions) ([]Repository, error) {
+	return m.MockSearchPullRequests(query, timeRange, options)
+}
+
+// GetRepositoryMetadata implements the GitHubRepository interface
+func (m *MockGitHubRepository) GetRepositoryMetadata(ctx context.Context, org string, repo string) (RepositoryMetadata, error) {
+	return m.MockGetRepositoryMetadata(org, repo)
+}
+
+// GetPullRequest implements the GitHubRepository interface
+func (m *MockGitHubRepository) GetPullRequest(ctx context.Context, org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error) {
+	return m.MockGetPullRequest(org, repo, number, timeRange, options)
+}
+
+// GetDirectCommits implements the GitHubRepository interface
+func (m *MockGitHubRepository) GetDirectCommits(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Commit, error) {
+	return m.MockGetDirectCommits(org, repo, timeRange, options)
+}
+
+// GetAssignedIssues implements the GitHubRepository interface
+func (m *MockGitHubRepository) GetAssignedIssues(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Issue, error) {
+	return m.MockGetAssignedIssues(org, repo, timeRange, options)
+}
+
+// ForUser implements the UserScopedRepository interface
+func (m *MockGitHubRepository) ForUser(username string) GitHubRepository {
+	return m.MockForUser(username)
+}
@@ -1,18 +1,55 @@
 package github
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
 
 // ActivityReport represents processed GitHub activity data for a specific time range
 type ActivityReport struct {
-	TimeRange    TimeRange
-	User         User
-	Repositories []Repository
+	TimeRange    TimeRange    `json:"time_range"`
+	User         User         `json:"user"`
+	Repositories []Repository `json:"repositories"`
+	
+	// The individual time ranges of a report merged from several ranges
+	Ranges []TimeRange `json:"ranges,omitempty"`
+	
+	// Repositories left out of the report because processing them failed
+	SkippedRepositories []SkippedRepo `json:"skipped_repositories,omitempty"`
+	
+	// Set when content was left out to fit an output size budget
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// SkippedRepo is a repository left out of a report, with the error that
+// caused it to be skipped
+type SkippedRepo struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Kind   string `json:"kind"` // One of the ErrorKind values
+}
+
+// ActivityItemCount returns the total number of commits, reviews, and
+// comments across the report
+func (r *ActivityReport) ActivityItemCount() int {
+	count := 0
+	for _, repo := range r.Repositories {
+		for _, pr := range repo.PullRequests {
+			count += len(pr.Commits) + len(pr.Reviews) + len(pr.Comments)
+		}
+	}
+	return count
 }
 
 // TimeRange represents a time period for the report
 type TimeRange struct {
-	Start time.Time
-	End   time.Time
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
 
 // IsInRange checks if a given time is within the time range
@@ -20,67 +57,473 @@ func (tr TimeRange) IsInRange(t time.Time) bool {
 	return (t.Equal(tr.Start) || t.After(tr.Start)) && t.Before(tr.End)
 }
 
+// FromPluginTimeRange converts a plugin time range to a TimeRange, rejecting
+// ranges whose start is not before their end
+func FromPluginTimeRange(pluginTimeRange plug.TimeRange) (TimeRange, error) {
+	if !pluginTimeRange.Start.Before(pluginTimeRange.End) {
+		return TimeRange{}, fmt.Errorf("invalid time range: start %s is not before end %s",
+			pluginTimeRange.Start.Format(time.RFC3339),
+			pluginTimeRange.End.Format(time.RFC3339))
+	}
+	
+	return TimeRange{
+		Start: pluginTimeRange.Start,
+		End:   pluginTimeRange.End,
+	}, nil
+}
+
+// ToPluginTimeRange converts the time range to a plugin time range
+func (tr TimeRange) ToPluginTimeRange() plug.TimeRange {
+	return plug.TimeRange{
+		Start: tr.Start,
+		End:   tr.End,
+	}
+}
+
+// Label returns a human-readable description of the time range
+func (tr TimeRange) Label() string {
+	return fmt.Sprintf("%s to %s", tr.Start.Format("2006-01-02"), tr.End.Format("2006-01-02"))
+}
+
 // User represents a GitHub user
 type User struct {
-	Username string
-	Email    string
+	Username  string `json:"username"`
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
 }
 
 // Repository represents a GitHub repository with activity
 type Repository struct {
-	Name         string
-	Organization string
-	PullRequests []PullRequest
+	Name         string        `json:"name"`
+	Organization string        `json:"organization"`
+	PullRequests []PullRequest `json:"pull_requests"`
+	
+	// The user's commits pushed to the default branch without a pull
+	// request, when IncludeDirectCommits is set
+	DirectCommits []Commit `json:"direct_commits,omitempty"`
+	
+	// Open issues assigned to the user, when IncludeAssignedIssues is set
+	AssignedIssues []Issue `json:"assigned_issues,omitempty"`
+	
+	// Set when archived or disabled repositories are checked and tagged
+	Archived bool `json:"archived,omitempty"`
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// RepositoryMetadata is the state of a repository that decides whether its
+// activity is worth fetching
+type RepositoryMetadata struct {
+	// The repository name in its canonical casing, such as myrepo for a
+	// configured MyRepo
+	Name     string
+	Archived bool
+	Disabled bool
+}
+
+// Ways of handling archived and disabled repositories
+const (
+	ArchivedReposTag  = "tag"  // Include them, tagged as archived or disabled
+	ArchivedReposSkip = "skip" // Skip them with a note instead of searching
+)
+
+// Tag returns "archived" or "disabled" for a repository flagged as either,
+// and an empty string otherwise
+func (r Repository) Tag() string {
+	switch {
+	case r.Disabled:
+		return "disabled"
+	case r.Archived:
+		return "archived"
+	}
+	return ""
 }
 
 // PullRequest represents a GitHub pull request
 type PullRequest struct {
-	Number      int
-	Title       string
-	URL         string
-	State       string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Author      string
-	Commits     []Commit
-	Reviews     []Review
-	Comments    []Comment
-	IsAuthored  bool
-	IsReviewed  bool
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	State      string    `json:"state"`
+	Draft      bool      `json:"draft,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Author     string    `json:"author"`
+	HeadBranch string    `json:"head_branch,omitempty"`
+	// The branch the pull request merges into, when fetched
+	BaseBranch string `json:"base_branch,omitempty"`
+	// The author's display name, when resolved and set on their profile
+	AuthorName string `json:"author_name,omitempty"`
+	// The author's avatar image and profile page
+	AuthorAvatarURL string `json:"author_avatar_url,omitempty"`
+	AuthorURL       string `json:"author_url,omitempty"`
+	// Pull requests the body says this one depends on
+	DependsOn []PRRef `json:"depends_on,omitempty"`
+	// Reviews and review comments others left on the user's pull request,
+	// when IncludeFeedbackReceived is set
+	ReceivedReviews  []Review  `json:"received_reviews,omitempty"`
+	ReceivedComments []Comment `json:"received_comments,omitempty"`
+	// Labels, retitles, and assignments the user made in the time range,
+	// when IncludeMetadataActions is set
+	MetadataActions []MetadataAction `json:"metadata_actions,omitempty"`
+	// Lines added and deleted, and the size category they fall in, when
+	// IncludeSize is set
+	Additions    int    `json:"additions,omitempty"`
+	Deletions    int    `json:"deletions,omitempty"`
+	SizeCategory string `json:"size_category,omitempty"`
+	// Fields extracted from the body by BodyFieldPatterns, by name
+	ExtractedFields map[string]string `json:"extracted_fields,omitempty"`
+	Milestone  string    `json:"milestone,omitempty"`
+	Labels     []Label   `json:"labels,omitempty"`
+	// Paths of files changed by the pull request, capped at MaxFiles
+	ChangedFilePaths []string `json:"changed_file_paths,omitempty"`
+	// Overall review decision: APPROVED, CHANGES_REQUESTED, or REVIEW_REQUIRED
+	ReviewDecision string `json:"review_decision,omitempty"`
+	// Whether an open pull request can be merged without conflicts. Nil
+	// when not requested or GitHub has not finished computing it.
+	Mergeable *bool `json:"mergeable,omitempty"`
+	// GitHub's mergeable state, such as clean, dirty, or blocked, and
+	// unknown while it is still being computed
+	MergeableState string `json:"mergeable_state,omitempty"`
+	// Number of review threads not yet marked as resolved
+	UnresolvedThreads int       `json:"unresolved_threads,omitempty"`
+	// Number of review threads the user started with a top-level review
+	// comment in the time range, when IncludeThreadsStarted is set
+	ThreadsStarted int `json:"threads_started,omitempty"`
+	// Status of the pull request on its project boards, such as In Review
+	ProjectStatus string `json:"project_status,omitempty"`
+	MergedAt          time.Time `json:"merged_at"`
+	ClosedAt          time.Time `json:"closed_at"`
+	MergeCommitSHA    string    `json:"merge_commit_sha,omitempty"`
+	Commits           []Commit  `json:"commits,omitempty"`
+	Reviews           []Review  `json:"reviews,omitempty"`
+	Comments          []Comment `json:"comments,omitempty"`
+	IsAuthored        bool      `json:"is_authored"`
+	IsReviewed        bool      `json:"is_reviewed"`
+	// The user closed this pull request without merging it
+	IsClosedByUser bool `json:"is_closed_by_user"`
+	// The user changed this pull request's labels, title, or assignees but
+	// is not otherwise involved in it
+	IsMetadataEdited bool `json:"is_metadata_edited,omitempty"`
+	// State transitions within the time range: opened, merged, or closed
+	StateTransitions []string `json:"state_transitions,omitempty"`
+	
+	// The user has started a review that is not submitted yet. GitHub only
+	// shows pending reviews to their author.
+	HasPendingReview bool `json:"has_pending_review,omitempty"`
+	
+	// Why the pull request is in the report, set when Explain is on
+	InclusionReason string `json:"inclusion_reason,omitempty"`
+	
+	// Enrichment steps that failed for this pull request
+	EnrichmentErrors []string `json:"enrichment_errors,omitempty"`
+	
+	// The first enrichment error, kept typed for fast-fail to report
+	enrichmentErr error
+	
+	// Every reviewer's reviews, when already fetched while searching, so
+	// enrichment does not fetch them again
+	fetchedReviews []Review
+}
+
+// Label represents a label applied to a pull request
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"` // Hex color without the leading '#', as returned by the API
+}
+
+// Issue represents a GitHub issue
+type Issue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Labels    []Label   `json:"labels,omitempty"`
+}
+
+// State transitions a pull request can make within a time range
+const (
+	StateTransitionOpened = "opened"
+	StateTransitionMerged = "merged"
+	StateTransitionClosed = "closed"
+)
+
+// AuthorDisplayName returns the author's display name, falling back to their
+// login when it was not resolved or is not set
+func (pr PullRequest) AuthorDisplayName() string {
+	if pr.AuthorName != "" {
+		return pr.AuthorName
+	}
+	return pr.Author
+}
+
+// HasConflicts reports whether the pull request is open and GitHub found it
+// cannot be merged without conflicts
+func (pr PullRequest) HasConflicts() bool {
+	return pr.State == "open" && pr.Mergeable != nil && !*pr.Mergeable
+}
+
+// StateChangedInRange reports whether the pull request was opened, merged, or
+// closed within the report's time range
+func (pr PullRequest) StateChangedInRange() bool {
+	return len(pr.StateTransitions) > 0
+}
+
+// stateTransitions lists the state transitions the pull request made within
+// the time range. A merge also closes a pull request, so it is only reported
+// as merged.
+func stateTransitions(pr PullRequest, timeRange TimeRange) []string {
+	var transitions []string
+	if timeRange.IsInRange(pr.CreatedAt) {
+		transitions = append(transitions, StateTransitionOpened)
+	}
+	if !pr.MergedAt.IsZero() && timeRange.IsInRange(pr.MergedAt) {
+		transitions = append(transitions, StateTransitionMerged)
+	} else if !pr.ClosedAt.IsZero() && timeRange.IsInRange(pr.ClosedAt) {
+		transitions = append(transitions, StateTransitionClosed)
+	}
+	return transitions
 }
 
 // Commit represents a commit in a pull request
 type Commit struct {
-	SHA       string
-	Message   string
-	Author    string
-	Timestamp time.Time
+	SHA       string    `json:"sha"`
+	Message   string    `json:"message"`
+	Author    string    `json:"author"`
+	Timestamp time.Time `json:"timestamp"`
+	Range     string    `json:"range,omitempty"` // Label of the time range in a multi-range report
+	
+	// Whether GitHub verified the commit's GPG or SSH signature, and why
+	// not when it did not (e.g. "unsigned" or "bad_email")
+	Verified           bool   `json:"verified"`
+	VerificationReason string `json:"verification_reason,omitempty"`
+	
+	// Conventional commit type of the message, such as feat or fix, or
+	// ConventionalTypeOther when it does not follow the convention
+	ConventionalType string `json:"conventional_type,omitempty"`
+	
+	// Made outside the configured working hours, when they are tagged
+	AfterHours bool `json:"after_hours,omitempty"`
 }
 
 // Review represents a review on a pull request
 type Review struct {
-	ID        int64
-	Author    string
-	State     string
-	Body      string
-	Timestamp time.Time
+	ID        int64     `json:"id"`
+	Author    string    `json:"author"`
+	State     string    `json:"state"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+	CommitID  string    `json:"commit_id,omitempty"` // The head commit when the review was submitted
+	Range     string    `json:"range,omitempty"` // Label of the time range in a multi-range report
+	
+	// Made outside the configured working hours, when they are tagged
+	AfterHours bool `json:"after_hours,omitempty"`
+	
+	// Made after the pull request was merged, when such activity is tagged
+	PostMerge bool `json:"post_merge,omitempty"`
 }
 
 // Comment represents a comment on a pull request
 type Comment struct {
-	ID        int64
-	Author    string
-	Body      string
-	Timestamp time.Time
-	Path      string
-	Position  int
+	ID        int64     `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+	Path      string    `json:"path,omitempty"`
+	Position  int       `json:"position,omitempty"`
+	InReplyTo int64     `json:"in_reply_to,omitempty"`
+	ReviewID  int64     `json:"review_id,omitempty"` // The review the comment was submitted with
+	Range     string    `json:"range,omitempty"` // Label of the time range in a multi-range report
+	Kind      string    `json:"kind,omitempty"` // CommentKindReview or CommentKindConversation
+	
+	// Made outside the configured working hours, when they are tagged
+	AfterHours bool `json:"after_hours,omitempty"`
+	
+	// Made after the pull request was merged, when such activity is tagged
+	PostMerge bool `json:"post_merge,omitempty"`
+}
+
+// Kinds of comments, by where they were made on the pull request
+const (
+	// CommentKindReview is an inline comment on the diff, made as part of a
+	// review
+	CommentKindReview = "review"
+	
+	// CommentKindConversation is a comment on the conversation tab
+	CommentKindConversation = "conversation"
+)
+
+// ReviewStatePending is the state of a review that was started but not
+// submitted
+const ReviewStatePending = "PENDING"
+
+// Review decisions, matching the values GitHub reports for a pull request
+const (
+	ReviewDecisionApproved         = "APPROVED"
+	ReviewDecisionChangesRequested = "CHANGES_REQUESTED"
+	ReviewDecisionReviewRequired   = "REVIEW_REQUIRED"
+)
+
+// reviewDecision aggregates reviews into an overall decision the way GitHub
+// does: each reviewer's latest approving, change-requesting, or dismissed
+// review counts, and any outstanding change request outweighs approvals.
+// Comment-only reviews do not change a reviewer's standing.
+func reviewDecision(reviews []Review) string {
+	latest := make(map[string]Review)
+	for _, review := range reviews {
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+		default:
+			continue
+		}
+		if current, ok := latest[review.Author]; !ok || !review.Timestamp.Before(current.Timestamp) {
+			latest[review.Author] = review
+		}
+	}
+	
+	decision := ReviewDecisionReviewRequired
+	for _, review := range latest {
+		switch review.State {
+		case "CHANGES_REQUESTED":
+			return ReviewDecisionChangesRequested
+		case "APPROVED":
+			decision = ReviewDecisionApproved
+		}
+	}
+	
+	return decision
+}
+
+// CommentThread is a root comment and the replies to it
+type CommentThread struct {
+	Root    Comment   `json:"root"`
+	Replies []Comment `json:"replies"`
+}
+
+// countThreadsStarted counts the review comments that start a thread rather
+// than reply to one. Conversation comments are not threaded and never count.
+func countThreadsStarted(comments []Comment) int {
+	count := 0
+	for _, comment := range comments {
+		if comment.Kind != CommentKindConversation && comment.InReplyTo == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// buildCommentThreads groups comments into threads using their InReplyTo IDs.
+// Threads are ordered by the timestamp of their root comment and replies are
+// ordered by timestamp within their thread, with IDs breaking ties. A reply
+// whose parent is not among the comments starts its own thread.
+func buildCommentThreads(comments []Comment) []CommentThread {
+	byID := make(map[int64]Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+	
+	// rootOf follows the reply chain up to the first comment whose parent is
+	// unknown, guarding against cycles
+	rootOf := func(comment Comment) int64 {
+		seen := map[int64]bool{}
+		for comment.InReplyTo != 0 && !seen[comment.ID] {
+			seen[comment.ID] = true
+			parent, ok := byID[comment.InReplyTo]
+			if !ok {
+				break
+			}
+			comment = parent
+		}
+		return comment.ID
+	}
+	
+	threads := make([]CommentThread, 0, len(comments))
+	threadIndex := make(map[int64]int)
+	for _, comment := range comments {
+		if rootOf(comment) == comment.ID {
+			threadIndex[comment.ID] = len(threads)
+			threads = append(threads, CommentThread{Root: comment})
+		}
+	}
+	for _, comment := range comments {
+		rootID := rootOf(comment)
+		if rootID != comment.ID {
+			i := threadIndex[rootID]
+			threads[i].Replies = append(threads[i].Replies, comment)
+		}
+	}
+	
+	sort.SliceStable(threads, func(i, j int) bool {
+		return commentBefore(threads[i].Root, threads[j].Root)
+	})
+	for i := range threads {
+		sortComments(threads[i].Replies)
+	}
+	
+	return threads
+}
+
+// commentBefore orders comments by timestamp, then by ID so comments made in
+// the same second keep a stable order
+func commentBefore(a Comment, b Comment) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.ID < b.ID
+}
+
+// dedupeComments drops repeated comments by ID, keeping the first occurrence
+// in place. When a comment was collected both as a review comment and as a
+// conversation comment, the review comment is kept, as it carries the file
+// and review the comment belongs to.
+func dedupeComments(comments []Comment) []Comment {
+	deduped := make([]Comment, 0, len(comments))
+	index := make(map[int64]int, len(comments))
+	for _, comment := range comments {
+		i, seen := index[comment.ID]
+		if !seen {
+			index[comment.ID] = len(deduped)
+			deduped = append(deduped, comment)
+			continue
+		}
+		if comment.Kind == CommentKindReview && deduped[i].Kind != CommentKindReview {
+			deduped[i] = comment
+		}
+	}
+	return deduped
+}
+
+// sortComments sorts comments chronologically in place, independent of the
+// order the API returned them in
+func sortComments(comments []Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return commentBefore(comments[i], comments[j])
+	})
+}
+
+// sortReviews sorts reviews chronologically in place, then by ID
+func sortReviews(reviews []Review) {
+	sort.SliceStable(reviews, func(i, j int) bool {
+		if !reviews[i].Timestamp.Equal(reviews[j].Timestamp) {
+			return reviews[i].Timestamp.Before(reviews[j].Timestamp)
+		}
+		return reviews[i].ID < reviews[j].ID
+	})
 }
 
 // QueryOptions represents configurable options for GitHub queries
 type QueryOptions struct {
-	// Base branch to filter pull requests by
+	// Base branch to filter pull requests by. Empty or AnyBaseBranch
+	// matches every base branch.
 	BaseBranch string
 	
+	// Only keep pull requests whose base branch matches this glob, such as
+	// release/*. Search cannot match base branches by pattern, so when set
+	// BaseBranch is ignored and each pull request's base branch is fetched
+	// and matched instead, costing an extra API call per pull request.
+	BaseBranchPattern string
+	
 	// Maximum number of results to return
 	MaxResults int
 	
@@ -95,8 +538,149 @@ type QueryOptions struct {
 	
 	// Whether to include commits
 	IncludeCommits bool
+	
+	// Only keep pull requests whose head branch starts with this prefix
+	HeadBranchPrefix string
+	
+	// Only keep pull requests whose title starts with this prefix, such as
+	// a ticket ID like [PROJ-123]
+	TitlePrefix string
+	
+	// Only keep pull requests whose title matches this pattern
+	TitlePattern *regexp.Regexp
+	
+	// Only include pull requests in this milestone
+	Milestone string
+	
+	// Whether to compute the overall review decision of each pull request
+	IncludeReviewDecision bool
+	
+	// Whether to include the user's reviews of their own pull requests in
+	// the authored section
+	IncludeSelfReviews bool
+	
+	// Only include commits and review comments touching files under this
+	// path. Each commit costs an extra API call to list its files.
+	PathPrefix string
+	
+	// Drop review comments on files matching any of these glob patterns,
+	// such as go.sum or *.pb.go. Patterns without a slash also match the
+	// file name in any directory.
+	ExcludePaths []string
+	
+	// Whether to include other authors' pull requests the user closed
+	// without merging. Costs an extra events API call per closed candidate.
+	IncludeClosedByUser bool
+	
+	// Whether to list the files changed by each pull request
+	IncludeFiles bool
+	
+	// Maximum number of changed files listed per pull request. Zero uses
+	// DefaultMaxFiles.
+	MaxFiles int
+	
+	// Maximum number of concurrent API calls made to enrich pull requests,
+	// across all the repositories of a report. Zero uses the default of
+	// four; one enriches sequentially.
+	MaxConcurrency int
+	
+	// Whether to check if each open pull request can be merged without
+	// conflicts. Costs an extra API call per open pull request.
+	IncludeMergeable bool
+	
+	// Whether to categorize each pull request as XS, S, M, L, or XL by its
+	// changed lines, using SizeThresholds. Costs an extra API call per pull
+	// request that is not merged.
+	IncludeSize    bool
+	SizeThresholds SizeThresholds
+	
+	// Only report pull requests of this size category or larger, such as
+	// SizeL. Implies IncludeSize; empty reports every size.
+	MinSizeCategory string
+	
+	// Whether to count unresolved review threads of each pull request.
+	// Costs an extra GraphQL query per pull request.
+	IncludeUnresolvedThreads bool
+	
+	// Whether to add the user's comments on the conversation tab to their
+	// review comments. Needs IncludeComments and costs an extra API call per
+	// pull request.
+	IncludeConversationComments bool
+	
+	// Whether to count the review threads the user started on each pull
+	// request, from their top-level review comments in the time range.
+	// Needs IncludeComments and costs no extra API calls.
+	IncludeThreadsStarted bool
+	
+	// Only include reviewed pull requests the user was explicitly requested
+	// to review, dropping drive-by reviews. Costs an extra events API call
+	// per reviewed pull request.
+	OnlyRequestedReviews bool
+	
+	// Drop reviewed pull requests without a review by the user in the time
+	// range, such as those matched by reviewed-by: for an older review,
+	// before any of their activity is fetched. Enrichment reuses the reviews
+	// fetched to check them, so only dropped pull requests cost an extra
+	// reviews API call.
+	OnlyReviewedInRange bool
+	
+	// Keep only each reviewer's most recent submitted review of a pull
+	// request, such as the approval that followed earlier comments
+	LatestReviewPerAuthor bool
+	
+	// Place commits in the time range by their author date instead of their
+	// committer date. Rebasing and amending reset the committer date, while
+	// the author date records when the work was done.
+	UseAuthorDate bool
+	
+	// Only count the user's reviews in these states, such as APPROVED and
+	// CHANGES_REQUESTED to leave out bare COMMENTED reviews. Empty counts
+	// reviews in every state.
+	ReviewStates []string
+	
+	// Resolve each pull request author's login to their display name.
+	// Costs an extra API call per distinct author, made once per report.
+	ResolveDisplayNames bool
+	
+	// Whether to list the user's commits on each repository's default
+	// branch, capturing pushes that never went through a pull request.
+	// Costs an extra API call per repository.
+	IncludeDirectCommits bool
+	
+	// Whether to read the Status of each pull request on its GitHub
+	// Projects boards. Costs an extra GraphQL query per pull request.
+	IncludeProjectStatus bool
+	
+	// Whether to list the reviews and review comments others left on the
+	// user's pull requests in the time range. Costs two extra API calls per
+	// authored pull request.
+	IncludeFeedbackReceived bool
+	
+	// Whether to list the labels, retitles, and assignments the user made
+	// to each pull request in the time range, counting them as activity so
+	// pull requests with only metadata changes are kept. Costs an extra
+	// events API call per pull request.
+	IncludeMetadataActions bool
+	
+	// Patterns extracting named fields, such as "Risk: high", from each
+	// pull request body. The first capture group is the field's value, or
+	// the whole match when the pattern has none.
+	BodyFieldPatterns map[string]*regexp.Regexp
+	
+	// Whether to list the open issues assigned to the user in each
+	// repository, as the work in progress. Costs an extra search per
+	// repository.
+	IncludeAssignedIssues bool
+	
+	// Only list assigned issues updated within the time range, instead of
+	// every open assigned issue
+	AssignedIssuesInRange bool
 }
 
+// DefaultMaxFiles is the number of changed files listed per pull request when
+// no explicit limit is configured
+const DefaultMaxFiles = 20
+
 // DefaultQueryOptions returns the default query options
 func DefaultQueryOptions() QueryOptions {
 	return QueryOptions{
@@ -107,4 +691,72 @@ func DefaultQueryOptions() QueryOptions {
 		IncludeComments: true,
 		IncludeCommits:  true,
 	}
-} 
+}
+
+// ActivityCriteria defines what counts as reportable activity on a pull
+// request. A pull request matches when it has any of the enabled kinds of
+// activity; criteria with nothing enabled match every pull request.
+type ActivityCriteria struct {
+	// The pull request has commits in the time range
+	HasCommits bool
+	
+	// The pull request has reviews in the time range
+	HasReviews bool
+	
+	// The pull request has comments in the time range
+	HasComments bool
+	
+	// The pull request was opened, merged, or closed in the time range
+	StateChanged bool
+}
+
+// IsZero reports whether no kind of activity is enabled
+func (c ActivityCriteria) IsZero() bool {
+	return c == ActivityCriteria{}
+}
+
+// Matches reports whether the pull request has activity satisfying the
+// criteria. Commits, reviews, and comments are expected to already be
+// filtered to the time range.
+func (c ActivityCriteria) Matches(pr PullRequest, timeRange TimeRange) bool {
+	if c.IsZero() {
+		return true
+	}
+	
+	if c.HasCommits && len(pr.Commits) > 0 {
+		return true
+	}
+	if c.HasReviews && len(pr.Reviews) > 0 {
+		return true
+	}
+	if c.HasComments && len(pr.Comments) > 0 {
+		return true
+	}
+	if c.StateChanged && len(stateTransitions(pr, timeRange)) > 0 {
+		return true
+	}
+	return false
+}
+
+// ParseActivityCriteria parses a comma-separated list of activity kinds
+// (commits, reviews, comments, state) into ActivityCriteria
+func ParseActivityCriteria(value string) (ActivityCriteria, error) {
+	var criteria ActivityCriteria
+	for _, kind := range strings.Split(value, ",") {
+		switch strings.TrimSpace(kind) {
+		case "":
+			continue
+		case "commits":
+			criteria.HasCommits = true
+		case "reviews":
+			criteria.HasReviews = true
+		case "comments":
+			criteria.HasComments = true
+		case "state":
+			criteria.StateChanged = true
+		default:
+			return ActivityCriteria{}, fmt.Errorf("unknown activity kind %q", strings.TrimSpace(kind))
+		}
+	}
+	return criteria, nil
+}
@@ -1,8 +1,11 @@
 package github
 
 import (
+	"slices"
 	"testing"
 	"time"
+
+	plug "github.com/iures/daivplug"
 )
 
 func TestTimeRange_IsInRange(t *testing.T) {
@@ -100,3 +103,169 @@ func TestDefaultQueryOptions(t *testing.T) {
 		t.Errorf("Expected default MaxResults to be 100, got %d", options.MaxResults)
 	}
 } 
+
+func TestBuildCommentThreads(t *testing.T) {
+	base := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	comments := []Comment{
+		{ID: 3, Body: "second reply", Timestamp: base.Add(3 * time.Hour), InReplyTo: 1},
+		{ID: 4, Body: "other root", Timestamp: base.Add(2 * time.Hour)},
+		{ID: 2, Body: "first reply", Timestamp: base.Add(1 * time.Hour), InReplyTo: 1},
+		{ID: 1, Body: "root", Timestamp: base},
+		{ID: 5, Body: "orphan reply", Timestamp: base.Add(4 * time.Hour), InReplyTo: 99},
+	}
+
+	threads := buildCommentThreads(comments)
+
+	if len(threads) != 3 {
+		t.Fatalf("Expected 3 threads, got %d", len(threads))
+	}
+
+	expectedRoots := []string{"root", "other root", "orphan reply"}
+	for i, expected := range expectedRoots {
+		if threads[i].Root.Body != expected {
+			t.Errorf("Expected thread %d root %q, got %q", i, expected, threads[i].Root.Body)
+		}
+	}
+
+	replies := threads[0].Replies
+	if len(replies) != 2 || replies[0].Body != "first reply" || replies[1].Body != "second reply" {
+		t.Errorf("Expected replies in chronological order, got %+v", replies)
+	}
+}
+
+func TestDedupeComments(t *testing.T) {
+	comments := []Comment{
+		{ID: 1, Body: "conversation copy", Kind: CommentKindConversation},
+		{ID: 2, Body: "only once", Kind: CommentKindConversation},
+		{ID: 1, Body: "review copy", Kind: CommentKindReview, Path: "main.go"},
+		{ID: 2, Body: "only once", Kind: CommentKindConversation},
+	}
+
+	deduped := dedupeComments(comments)
+	if len(deduped) != 2 {
+		t.Fatalf("Expected 2 comments, got %+v", deduped)
+	}
+	if deduped[0].ID != 1 || deduped[0].Kind != CommentKindReview || deduped[0].Path != "main.go" {
+		t.Errorf("Expected the review copy of comment 1 in its first position, got %+v", deduped[0])
+	}
+	if deduped[1].ID != 2 {
+		t.Errorf("Expected comment 2 second, got %+v", deduped[1])
+	}
+}
+
+func TestReviewDecision(t *testing.T) {
+	base := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		reviews  []Review
+		expected string
+	}{
+		{
+			name:     "No reviews",
+			expected: ReviewDecisionReviewRequired,
+		},
+		{
+			name: "Approval and change request",
+			reviews: []Review{
+				{Author: "alice", State: "APPROVED", Timestamp: base},
+				{Author: "bob", State: "CHANGES_REQUESTED", Timestamp: base.Add(time.Hour)},
+			},
+			expected: ReviewDecisionChangesRequested,
+		},
+		{
+			name: "Change request superseded by approval",
+			reviews: []Review{
+				{Author: "bob", State: "CHANGES_REQUESTED", Timestamp: base},
+				{Author: "bob", State: "APPROVED", Timestamp: base.Add(time.Hour)},
+			},
+			expected: ReviewDecisionApproved,
+		},
+		{
+			name: "Comments do not count",
+			reviews: []Review{
+				{Author: "alice", State: "APPROVED", Timestamp: base},
+				{Author: "alice", State: "COMMENTED", Timestamp: base.Add(time.Hour)},
+			},
+			expected: ReviewDecisionApproved,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if decision := reviewDecision(tc.reviews); decision != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, decision)
+			}
+		})
+	}
+}
+
+func TestParseActivityCriteria(t *testing.T) {
+	criteria, err := ParseActivityCriteria("commits, state")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if criteria != (ActivityCriteria{HasCommits: true, StateChanged: true}) {
+		t.Errorf("Expected commits and state criteria, got %+v", criteria)
+	}
+
+	if _, err := ParseActivityCriteria("commits,pushes"); err == nil {
+		t.Error("Expected an error for an unknown activity kind")
+	}
+}
+
+func TestFromPluginTimeRange(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	timeRange, err := FromPluginTimeRange(plug.TimeRange{Start: start, End: end})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !timeRange.Start.Equal(start) || !timeRange.End.Equal(end) {
+		t.Errorf("Expected %v to %v, got %v to %v", start, end, timeRange.Start, timeRange.End)
+	}
+
+	pluginTimeRange := timeRange.ToPluginTimeRange()
+	if !pluginTimeRange.Start.Equal(start) || !pluginTimeRange.End.Equal(end) {
+		t.Errorf("Expected round trip to preserve the range, got %v to %v", pluginTimeRange.Start, pluginTimeRange.End)
+	}
+
+	for name, inverted := range map[string]plug.TimeRange{
+		"Inverted": {Start: end, End: start},
+		"Empty":    {Start: start, End: start},
+	} {
+		if _, err := FromPluginTimeRange(inverted); err == nil {
+			t.Errorf("%s: expected an error but got nil", name)
+		}
+	}
+}
+
+func TestStateTransitions(t *testing.T) {
+	timeRange := TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	inRange := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		pr       PullRequest
+		expected []string
+	}{
+		{"Opened", PullRequest{CreatedAt: inRange}, []string{StateTransitionOpened}},
+		{"Opened and merged", PullRequest{CreatedAt: inRange, MergedAt: inRange, ClosedAt: inRange}, []string{StateTransitionOpened, StateTransitionMerged}},
+		{"Closed", PullRequest{CreatedAt: before, ClosedAt: inRange}, []string{StateTransitionClosed}},
+		{"Unchanged", PullRequest{CreatedAt: before}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transitions := stateTransitions(tc.pr, timeRange)
+			if !slices.Equal(transitions, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, transitions)
+			}
+		})
+	}
+}
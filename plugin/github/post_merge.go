@@ -0,0 +1,43 @@
+package github
+
+// Ways to treat reviews and comments made after a pull request was merged
+const (
+	// PostMergeFilter drops them
+	PostMergeFilter = "filter"
+	
+	// PostMergeTag keeps them and marks them as post-merge
+	PostMergeTag = "tag"
+)
+
+// applyPostMerge drops the reviews and comments of a merged pull request made
+// after it was merged in PostMergeFilter mode, or marks them as post-merge
+// otherwise. Pull requests that are not merged are left unchanged.
+func applyPostMerge(pr *PullRequest, mode string) {
+	if pr.MergedAt.IsZero() {
+		return
+	}
+	
+	reviews := make([]Review, 0, len(pr.Reviews))
+	for _, review := range pr.Reviews {
+		if review.Timestamp.After(pr.MergedAt) {
+			if mode == PostMergeFilter {
+				continue
+			}
+			review.PostMerge = true
+		}
+		reviews = append(reviews, review)
+	}
+	pr.Reviews = reviews
+	
+	comments := make([]Comment, 0, len(pr.Comments))
+	for _, comment := range pr.Comments {
+		if comment.Timestamp.After(pr.MergedAt) {
+			if mode == PostMergeFilter {
+				continue
+			}
+			comment.PostMerge = true
+		}
+		comments = append(comments, comment)
+	}
+	pr.Comments = comments
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestActivityService_PostMerge(t *testing.T) {
+	mergedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequest: func(org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error) {
+			return PullRequest{
+				Number:     number,
+				Title:      "Test PR",
+				State:      "merged",
+				MergedAt:   mergedAt,
+				IsReviewed: true,
+				Reviews:    []Review{{ID: 1, Author: "testuser", State: "APPROVED", Timestamp: mergedAt.Add(-time.Hour)}},
+				Comments: []Comment{
+					{ID: 1, Author: "testuser", Body: "Looks good", Timestamp: mergedAt.Add(-time.Hour)},
+					{ID: 2, Author: "testuser", Body: "Follow-up: add docs", Timestamp: mergedAt.Add(time.Hour)},
+				},
+			}, nil
+		},
+	}
+
+	timeRange := plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	config := &GitHubConfig{
+		Username:       "testuser",
+		QueryOptions:   DefaultQueryOptions(),
+		PullRequestURL: "https://github.com/testorg/testrepo/pull/42",
+		PostMerge:      PostMergeTag,
+	}
+	service := NewActivityService(mockRepo, config)
+
+	report, err := service.GetActivityReport(timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	pr := report.Repositories[0].PullRequests[0]
+	if len(pr.Comments) != 2 || pr.Comments[0].PostMerge || !pr.Comments[1].PostMerge || pr.Reviews[0].PostMerge {
+		t.Errorf("Expected only the follow-up comment to be flagged, got %+v and %+v", pr.Comments, pr.Reviews)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Count(content.Content, "(post-merge)") != 1 {
+		t.Errorf("Expected the follow-up comment to render as post-merge, got:\n%s", content.Content)
+	}
+
+	config.PostMerge = PostMergeFilter
+	report, err = service.GetActivityReport(timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	pr = report.Repositories[0].PullRequests[0]
+	if len(pr.Comments) != 1 || pr.Comments[0].ID != 1 || len(pr.Reviews) != 1 {
+		t.Errorf("Expected the follow-up comment to be excluded, got %+v and %+v", pr.Comments, pr.Reviews)
+	}
+}
@@ -0,0 +1,31 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParsePullRequestURL extracts the organization, repository, and number from
+// a pull request URL such as https://github.com/org/repo/pull/123. Trailing
+// tabs like /files, queries, and fragments are ignored, and any host is
+// accepted so GitHub Enterprise URLs work too.
+func ParsePullRequestURL(rawURL string) (string, string, int, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Host == "" {
+		return "", "", 0, fmt.Errorf("invalid pull request URL %q: expected https://github.com/<org>/<repo>/pull/<number>", rawURL)
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != "pull" {
+		return "", "", 0, fmt.Errorf("invalid pull request URL %q: expected https://github.com/<org>/<repo>/pull/<number>", rawURL)
+	}
+
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("invalid pull request URL %q: %q is not a pull request number", rawURL, parts[3])
+	}
+
+	return parts[0], parts[1], number, nil
+}
@@ -0,0 +1,40 @@
+package github
+
+import "testing"
+
+func TestParsePullRequestURL(t *testing.T) {
+	testCases := []string{
+		"https://github.com/testorg/testrepo/pull/123",
+		"https://github.com/testorg/testrepo/pull/123/files",
+		"https://github.com/testorg/testrepo/pull/123#discussion_r1",
+		" https://github.example.com/testorg/testrepo/pull/123/ ",
+	}
+
+	for _, rawURL := range testCases {
+		org, repo, number, err := ParsePullRequestURL(rawURL)
+		if err != nil {
+			t.Errorf("Expected no error for %q but got: %v", rawURL, err)
+			continue
+		}
+		if org != "testorg" || repo != "testrepo" || number != 123 {
+			t.Errorf("Expected testorg/testrepo#123 for %q, got %s/%s#%d", rawURL, org, repo, number)
+		}
+	}
+}
+
+func TestParsePullRequestURL_Malformed(t *testing.T) {
+	testCases := []string{
+		"",
+		"testorg/testrepo/pull/123",
+		"https://github.com/testorg/testrepo",
+		"https://github.com/testorg/testrepo/issues/123",
+		"https://github.com/testorg/testrepo/pull/abc",
+		"https://github.com/testorg/testrepo/pull/0",
+	}
+
+	for _, rawURL := range testCases {
+		if _, _, _, err := ParsePullRequestURL(rawURL); err == nil {
+			t.Errorf("Expected an error for %q", rawURL)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	externalGithub "github.com/google/go-github/v68/github"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitTransport waits for a token from a token bucket before sending each
+// request, so the client stays under a configured request rate instead of
+// relying on GitHub to reject requests once its limits are hit
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// newRateLimitTransport wraps base so at most requestsPerSecond requests are
+// sent on average, with bursts of up to burst requests. A nil base uses
+// http.DefaultTransport.
+func newRateLimitTransport(base http.RoundTripper, requestsPerSecond float64, burst int) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimitTransport{base: base, limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst)}
+}
+
+// RoundTrip implements http.RoundTripper. Waiting for a token is abandoned
+// when the request's context is canceled.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Ways to handle the primary rate limit running out mid-run
+const (
+	// RateLimitStrategyWait sleeps until the limit resets and retries
+	RateLimitStrategyWait = "wait"
+	
+	// RateLimitStrategyFail returns a RateLimitExhaustedError right away
+	RateLimitStrategyFail = "fail"
+)
+
+// rateLimitStrategyFor returns the rate limit strategy of config. A
+// fast-fail report fails rather than sleeping until the limit resets.
+func rateLimitStrategyFor(config *GitHubConfig) string {
+	if config.FastFail && config.RateLimitStrategy == RateLimitStrategyWait {
+		return RateLimitStrategyFail
+	}
+	return config.RateLimitStrategy
+}
+
+// RateLimitExhaustedError reports a request rejected because the primary
+// rate limit ran out. It is a kind of ErrRateLimited.
+type RateLimitExhaustedError struct {
+	// When the rate limit resets
+	Reset time.Time
+}
+
+// Error implements the error interface
+func (e *RateLimitExhaustedError) Error() string {
+	return fmt.Sprintf("%v: resets at %s", ErrRateLimited, e.Reset.Format(time.RFC3339))
+}
+
+// Unwrap lets errors.Is match ErrRateLimited
+func (e *RateLimitExhaustedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// rateLimitStrategyTransport handles responses rejected because the primary
+// rate limit ran out, by waiting for it to reset and retrying the request or
+// by failing with a RateLimitExhaustedError, instead of leaving go-github to
+// report it
+type rateLimitStrategyTransport struct {
+	base     http.RoundTripper
+	strategy string
+	
+	// wait blocks for d or until ctx is done, replaced in tests
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// newRateLimitStrategyTransport wraps base to handle rate limit exhaustion
+// with strategy, RateLimitStrategyWait or RateLimitStrategyFail. A nil base
+// uses http.DefaultTransport.
+func newRateLimitStrategyTransport(base http.RoundTripper, strategy string) *rateLimitStrategyTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitStrategyTransport{base: base, strategy: strategy, wait: sleepContext}
+}
+
+// RoundTrip implements http.RoundTripper. Waiting for the reset is abandoned
+// when the request's context is canceled. Requests whose body cannot be
+// replayed are not retried.
+func (t *rateLimitStrategyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		
+		reset, exhausted := primaryRateLimitReset(resp)
+		if !exhausted {
+			return resp, nil
+		}
+		
+		if t.strategy == RateLimitStrategyFail {
+			resp.Body.Close()
+			return nil, &RateLimitExhaustedError{Reset: reset}
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+		resp.Body.Close()
+		
+		Logger.Printf("GitHub rate limit exhausted, waiting until %s\n", reset.Format(time.RFC3339))
+		if err := t.wait(req.Context(), time.Until(reset)); err != nil {
+			return nil, err
+		}
+		
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// requestContext prepares ctx for the repository's API calls. Once a
+// response shows the primary rate limit ran out, go-github refuses further
+// requests before they reach rateLimitStrategyTransport, so with the wait
+// strategy it is told to sleep until the reset instead.
+func (r *GitHubAPIRepository) requestContext(ctx context.Context) context.Context {
+	if r.rateLimitStrategy != RateLimitStrategyWait {
+		return ctx
+	}
+	return context.WithValue(ctx, externalGithub.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
+}
+
+// primaryRateLimitReset returns when the primary rate limit resets if resp
+// was rejected because it ran out: a 403 or 429 with no requests remaining
+// and a reset in the future
+func primaryRateLimitReset(resp *http.Response) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+	
+	seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	reset := time.Unix(seconds, 0)
+	if !reset.After(time.Now()) {
+		return time.Time{}, false
+	}
+	
+	return reset, true
+}
+
+// sleepContext sleeps for d, returning early with the context's error when
+// ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
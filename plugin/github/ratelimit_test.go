@@ -0,0 +1,232 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	testClient, mux := newTestClient(t)
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	client := externalGithub.NewClient(&http.Client{Transport: newRateLimitTransport(nil, 1, 1)})
+	client.BaseURL = testClient.BaseURL
+
+	const calls = 3
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		if _, _, err := client.Users.Get(context.Background(), "testuser"); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < (calls-1)*time.Second {
+		t.Errorf("Expected %d calls at 1 req/s to take at least %v, took %v", calls, (calls-1)*time.Second, elapsed)
+	}
+}
+
+func TestRateLimitTransport_ContextCanceled(t *testing.T) {
+	testClient, mux := newTestClient(t)
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	client := externalGithub.NewClient(&http.Client{Transport: newRateLimitTransport(nil, 0.01, 1)})
+	client.BaseURL = testClient.BaseURL
+
+	// The first call uses the only token; the second would wait 100 seconds
+	if _, _, err := client.Users.Get(context.Background(), "testuser"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err := client.Users.Get(ctx, "testuser")
+	if err == nil {
+		t.Fatal("Expected an error when the context is canceled while waiting")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the wait to be abandoned promptly, took %v", elapsed)
+	}
+}
+
+// exhaustedTransport answers the first exhausted requests with a primary rate
+// limit rejection resetting at reset, and the rest with an empty JSON object
+func exhaustedTransport(exhausted int, reset time.Time, calls *int) roundTripperFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*calls++
+		header := http.Header{"Content-Type": []string{"application/json"}}
+		if *calls <= exhausted {
+			header.Set("X-RateLimit-Remaining", "0")
+			header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader(`{"message": "API rate limit exceeded"}`)),
+				Request:    req,
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func TestRateLimitStrategyTransport_Wait(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	var calls int
+	transport := newRateLimitStrategyTransport(exhaustedTransport(1, reset, &calls), RateLimitStrategyWait)
+	var waited time.Duration
+	transport.wait = func(ctx context.Context, d time.Duration) error {
+		waited = d
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/users/testuser", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("Expected the request to be retried once and succeed, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	if waited < 59*time.Minute || waited > time.Hour {
+		t.Errorf("Expected to wait about an hour until the reset, waited %v", waited)
+	}
+}
+
+func TestRateLimitStrategyTransport_WaitContextCanceled(t *testing.T) {
+	var calls int
+	transport := newRateLimitStrategyTransport(exhaustedTransport(1, time.Now().Add(time.Hour), &calls), RateLimitStrategyWait)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/users/testuser", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retry after the context ended, got %d calls", calls)
+	}
+}
+
+func TestRateLimitStrategyTransport_Fail(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	var calls int
+	client := externalGithub.NewClient(&http.Client{
+		Transport: newRateLimitStrategyTransport(exhaustedTransport(1, reset, &calls), RateLimitStrategyFail),
+	})
+
+	_, _, err := client.Users.Get(context.Background(), "testuser")
+	var exhausted *RateLimitExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("Expected a RateLimitExhaustedError, got %v", err)
+	}
+	if !exhausted.Reset.Equal(reset) {
+		t.Errorf("Expected the reset at %v, got %v", reset, exhausted.Reset)
+	}
+	if !errors.Is(err, ErrRateLimited) || ErrorKind(err) != ErrorKindRateLimited {
+		t.Errorf("Expected the error to be classified as rate limited, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retry, got %d calls", calls)
+	}
+}
+
+func TestNewGitHubClient_RateLimitStrategy(t *testing.T) {
+	for _, strategy := range []string{RateLimitStrategyFail, RateLimitStrategyWait} {
+		t.Run(strategy, func(t *testing.T) {
+			testClient, mux := newTestClient(t)
+
+			// The first response uses up the limit, which resets a second or two later
+			reset := time.Now().Add(time.Second).Truncate(time.Second).Add(time.Second)
+			var calls int
+			mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				remaining := "4999"
+				if calls == 1 {
+					remaining = "0"
+				}
+				w.Header().Set("X-RateLimit-Limit", "5000")
+				w.Header().Set("X-RateLimit-Remaining", remaining)
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+				writeJSON(t, w, map[string]any{"login": "testuser"})
+			})
+
+			githubClient, err := NewGitHubClient(&GitHubConfig{
+				Username:          "testuser",
+				Token:             "token",
+				RateLimitStrategy: strategy,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			githubClient.client.BaseURL = testClient.BaseURL
+			repository := githubClient.GetRepository()
+
+			if _, err := repository.GetUser(context.Background()); err != nil {
+				t.Fatalf("Expected the first request to succeed, got: %v", err)
+			}
+
+			// go-github refuses the second request itself, before it reaches
+			// the transport
+			_, err = repository.GetUser(context.Background())
+			switch strategy {
+			case RateLimitStrategyFail:
+				var exhausted *RateLimitExhaustedError
+				if !errors.As(err, &exhausted) {
+					t.Fatalf("Expected a RateLimitExhaustedError, got %v", err)
+				}
+				if !exhausted.Reset.Equal(reset) {
+					t.Errorf("Expected the reset at %v, got %v", reset, exhausted.Reset)
+				}
+				if calls != 1 {
+					t.Errorf("Expected no request while the limit is exhausted, got %d calls", calls)
+				}
+			case RateLimitStrategyWait:
+				if err != nil {
+					t.Fatalf("Expected the request to wait for the reset and succeed, got: %v", err)
+				}
+				if time.Now().Before(reset) || calls != 2 {
+					t.Errorf("Expected the request to be sent after the reset, got %d calls", calls)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitStrategyFor(t *testing.T) {
+	testCases := []struct {
+		strategy string
+		fastFail bool
+		expected string
+	}{
+		{strategy: RateLimitStrategyWait, expected: RateLimitStrategyWait},
+		{strategy: RateLimitStrategyWait, fastFail: true, expected: RateLimitStrategyFail},
+		{strategy: RateLimitStrategyFail, fastFail: true, expected: RateLimitStrategyFail},
+		{strategy: "", fastFail: true, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		config := &GitHubConfig{RateLimitStrategy: tc.strategy, FastFail: tc.fastFail}
+		if actual := rateLimitStrategyFor(config); actual != tc.expected {
+			t.Errorf("Expected strategy %q for %q with fast-fail %v, got %q", tc.expected, tc.strategy, tc.fastFail, actual)
+		}
+	}
+}
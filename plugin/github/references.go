@@ -0,0 +1,79 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// referencePattern matches issue and pull request references such as #123 or
+// GH-123. The leading group keeps references inside words, paths, and HTML
+// entities like &#39; from matching.
+var referencePattern = regexp.MustCompile(`(^|[^\w/&#-])(#|GH-)(\d+)\b`)
+
+// codeFence delimits fenced code blocks, which are never rewritten
+const codeFence = "```"
+
+// referenceLinker renders a reference to the given issue or pull request
+type referenceLinker func(repo Repository, reference string, number string) string
+
+// markdownReferenceLink renders a reference as a Markdown link
+func markdownReferenceLink(repo Repository, reference string, number string) string {
+	return fmt.Sprintf("[%s](%s)", reference, issueURL(repo, number))
+}
+
+// htmlReferenceLink renders a reference as an HTML anchor
+func htmlReferenceLink(repo Repository, reference string, number string) string {
+	return fmt.Sprintf("<a href=\"%s\">%s</a>", issueURL(repo, number), reference)
+}
+
+// issueURL returns the URL of an issue or pull request. GitHub redirects
+// issue URLs to the pull request when the number belongs to one.
+func issueURL(repo Repository, number string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/issues/%s", repo.Organization, repo.Name, number)
+}
+
+// linkReferences rewrites the references in text into links, leaving fenced
+// code blocks untouched
+func linkReferences(text string, repo Repository, link referenceLinker) string {
+	segments := strings.Split(text, codeFence)
+	for i := range segments {
+		// Odd segments are inside a fence
+		if i%2 == 1 {
+			continue
+		}
+		segments[i] = referencePattern.ReplaceAllStringFunc(segments[i], func(match string) string {
+			groups := referencePattern.FindStringSubmatch(match)
+			return groups[1] + link(repo, groups[2]+groups[3], groups[3])
+		})
+	}
+	return strings.Join(segments, codeFence)
+}
+
+// linkReportReferences returns a copy of the report with the references in
+// commit messages, review bodies, and comments rewritten into links to the
+// repository each one belongs to. The original report is not modified.
+func linkReportReferences(report *ActivityReport, link referenceLinker) *ActivityReport {
+	linked := *report
+	linked.Repositories = make([]Repository, len(report.Repositories))
+	for i, repo := range report.Repositories {
+		linked.Repositories[i] = repo
+		linked.Repositories[i].PullRequests = make([]PullRequest, len(repo.PullRequests))
+		for j, pr := range repo.PullRequests {
+			pr.Commits = append([]Commit(nil), pr.Commits...)
+			for k := range pr.Commits {
+				pr.Commits[k].Message = linkReferences(pr.Commits[k].Message, repo, link)
+			}
+			pr.Reviews = append([]Review(nil), pr.Reviews...)
+			for k := range pr.Reviews {
+				pr.Reviews[k].Body = linkReferences(pr.Reviews[k].Body, repo, link)
+			}
+			pr.Comments = append([]Comment(nil), pr.Comments...)
+			for k := range pr.Comments {
+				pr.Comments[k].Body = linkReferences(pr.Comments[k].Body, repo, link)
+			}
+			linked.Repositories[i].PullRequests[j] = pr
+		}
+	}
+	return &linked
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinkReferences(t *testing.T) {
+	repo := Repository{Name: "testrepo", Organization: "testorg"}
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Hash reference",
+			text:     "Fix crash from #123",
+			expected: "Fix crash from [#123](https://github.com/testorg/testrepo/issues/123)",
+		},
+		{
+			name:     "GH reference",
+			text:     "Follow-up to GH-45.",
+			expected: "Follow-up to [GH-45](https://github.com/testorg/testrepo/issues/45).",
+		},
+		{
+			name:     "Fenced reference",
+			text:     "See:\n```\n#123\n```\nand #7",
+			expected: "See:\n```\n#123\n```\nand [#7](https://github.com/testorg/testrepo/issues/7)",
+		},
+		{
+			name:     "Not a reference",
+			text:     "Use color #fff and path a/#1, or issue#2 and &#39;",
+			expected: "Use color #fff and path a/#1, or issue#2 and &#39;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := linkReferences(tc.text, repo, markdownReferenceLink)
+			if result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatters_LinkReferences(t *testing.T) {
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Commits = []Commit{
+		{SHA: "abc123", Message: "Fix #123\n```\n#123\n```", Timestamp: time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC)},
+	}
+	original := report.Repositories[0].PullRequests[0].Commits[0].Message
+
+	markdown := &MarkdownFormatter{Options: FormatterOptions{LinkReferences: true}}
+	content, err := markdown.Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "Fix [#123](https://github.com/testorg/testrepo/issues/123)") {
+		t.Errorf("Expected a linked reference in Markdown output, got:\n%s", content.Content)
+	}
+	if !strings.Contains(content.Content, "```\n#123\n```") {
+		t.Errorf("Expected the fenced reference to be left alone, got:\n%s", content.Content)
+	}
+
+	html := &HTMLFormatter{Options: FormatterOptions{LinkReferences: true}}
+	content, err = html.Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "Fix <a href=\"https://github.com/testorg/testrepo/issues/123\">#123</a>") {
+		t.Errorf("Expected a linked reference in HTML output, got:\n%s", content.Content)
+	}
+
+	if report.Repositories[0].PullRequests[0].Commits[0].Message != original {
+		t.Errorf("Expected the report to be left unmodified")
+	}
+}
@@ -3,52 +3,178 @@ package github
 import (
 	"context"
 	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	externalGithub "github.com/google/go-github/v68/github"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
-// GitHubRepository defines the interface for accessing GitHub data
+// GitHubRepository defines the interface for accessing GitHub data. Calls
+// stop making API requests once their context is canceled.
 type GitHubRepository interface {
-	GetUser() (*User, error)
-	GetPullRequests(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error)
+	GetUser(ctx context.Context) (*User, error)
+	GetPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error)
+	SearchPullRequests(ctx context.Context, query string, timeRange TimeRange, options QueryOptions) ([]Repository, error)
+	GetPullRequest(ctx context.Context, org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error)
+	GetDirectCommits(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Commit, error)
+	GetAssignedIssues(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Issue, error)
+	GetRepositoryMetadata(ctx context.Context, org string, repo string) (RepositoryMetadata, error)
 }
 
 // GitHubAPIRepository implements GitHubRepository using the GitHub API
 type GitHubAPIRepository struct {
-	client   *externalGithub.Client
-	username string
+	// The REST services are used through narrow interfaces so tests can
+	// fake them. The client itself is kept for GraphQL queries.
+	client       *externalGithub.Client
+	search       searchService
+	pullRequests pullRequestsService
+	users        usersService
+	repositories repositoriesService
+	issues       issuesService
+	activity     activityService
+	username     string
+	
+	// How to handle the primary rate limit running out, RateLimitStrategyWait
+	// or RateLimitStrategyFail. Empty leaves it to go-github.
+	rateLimitStrategy string
+	
+	// Repository metadata by "org/repo", fetched once per repository
+	metadataMu    sync.Mutex
+	metadata      map[string]RepositoryMetadata
+	metadataGroup singleflight.Group
+	
+	// Display names by login, resolved once per login
+	namesMu sync.Mutex
+	names   map[string]string
+	
+	// Pull requests the user changed the metadata of according to their
+	// events feed, read once per time range
+	feedMu    sync.Mutex
+	feed      map[string][]metadataEditEvent
+	feedGroup singleflight.Group
 }
 
 // NewGitHubAPIRepository creates a new GitHubAPIRepository
 func NewGitHubAPIRepository(client *externalGithub.Client, username string) *GitHubAPIRepository {
 	return &GitHubAPIRepository{
-		client:   client,
-		username: username,
+		client:       client,
+		search:       client.Search,
+		pullRequests: client.PullRequests,
+		users:        client.Users,
+		repositories: client.Repositories,
+		issues:       client.Issues,
+		activity:     client.Activity,
+		username:     username,
 	}
 }
 
 // GetUser retrieves the current user from GitHub
-func (r *GitHubAPIRepository) GetUser() (*User, error) {
-	ctx := context.Background()
-	
-	user, _, err := r.client.Users.Get(ctx, r.username)
+func (r *GitHubAPIRepository) GetUser(ctx context.Context) (*User, error) {
+	ctx = r.requestContext(ctx)
+	user, _, err := r.users.Get(ctx, r.username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user from GitHub: %w", err)
+		return nil, fmt.Errorf("failed to get user from GitHub: %w", wrapAPIError(err))
 	}
 	
 	return &User{
-		Username: user.GetLogin(),
-		Email:    user.GetEmail(),
+		Username:  user.GetLogin(),
+		Name:      user.GetName(),
+		Email:     user.GetEmail(),
+		AvatarURL: user.GetAvatarURL(),
 	}, nil
 }
 
+// UserScopedRepository is implemented by repositories that can run the same
+// queries on behalf of another user
+type UserScopedRepository interface {
+	ForUser(username string) GitHubRepository
+}
+
+// ForUser returns a repository querying the activity of another user through
+// the same client. It starts with the repository metadata and display names
+// cached so far, as neither depends on the user.
+func (r *GitHubAPIRepository) ForUser(username string) GitHubRepository {
+	scoped := &GitHubAPIRepository{
+		client:       r.client,
+		search:       r.search,
+		pullRequests: r.pullRequests,
+		users:        r.users,
+		repositories: r.repositories,
+		issues:       r.issues,
+		activity:     r.activity,
+		username:     username,
+		
+		rateLimitStrategy: r.rateLimitStrategy,
+	}
+	
+	r.metadataMu.Lock()
+	scoped.metadata = maps.Clone(r.metadata)
+	r.metadataMu.Unlock()
+	
+	r.namesMu.Lock()
+	scoped.names = maps.Clone(r.names)
+	r.namesMu.Unlock()
+	
+	return scoped
+}
+
+// GetRepositoryMetadata returns the canonical name of a repository and
+// whether it is archived or disabled. The result is cached, as it rarely
+// changes and is checked on every report. Names are matched ignoring case,
+// like the API does.
+func (r *GitHubAPIRepository) GetRepositoryMetadata(ctx context.Context, org string, repo string) (RepositoryMetadata, error) {
+	ctx = r.requestContext(ctx)
+	key := strings.ToLower(org + "/" + repo)
+	
+	r.metadataMu.Lock()
+	metadata, ok := r.metadata[key]
+	r.metadataMu.Unlock()
+	if ok {
+		return metadata, nil
+	}
+	
+	// The lock is not held across the request, so repositories processed
+	// concurrently do not wait on each other's lookups
+	result, err, _ := r.metadataGroup.Do(key, func() (any, error) {
+		repository, _, err := r.repositories.Get(ctx, org, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository %s/%s: %w", org, repo, wrapAPIError(err))
+		}
+		
+		metadata := RepositoryMetadata{
+			Name:     repository.GetName(),
+			Archived: repository.GetArchived(),
+			Disabled: repository.GetDisabled(),
+		}
+		r.metadataMu.Lock()
+		if r.metadata == nil {
+			r.metadata = make(map[string]RepositoryMetadata)
+		}
+		r.metadata[key] = metadata
+		r.metadataMu.Unlock()
+		return metadata, nil
+	})
+	if err != nil {
+		return RepositoryMetadata{}, err
+	}
+	return result.(RepositoryMetadata), nil
+}
+
 // GetPullRequests retrieves pull requests from GitHub based on the given parameters
-func (r *GitHubAPIRepository) GetPullRequests(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+func (r *GitHubAPIRepository) GetPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+	ctx = r.requestContext(ctx)
 	var allPRs []PullRequest
 
 	// Get authored PRs if enabled
 	if options.IncludeAuthored {
-		authoredPRs, err := r.searchAuthoredPullRequests(org, repo, timeRange, options)
+		authoredPRs, err := r.searchAuthoredPullRequests(ctx, org, repo, timeRange, options)
 		if err != nil {
 			return nil, err
 		}
@@ -57,97 +183,473 @@ func (r *GitHubAPIRepository) GetPullRequests(org string, repo string, timeRange
 	
 	// Get reviewed PRs if enabled
 	if options.IncludeReviewed {
-		reviewedPRs, err := r.searchReviewedPullRequests(org, repo, timeRange, options)
+		reviewedPRs, err := r.searchReviewedPullRequests(ctx, org, repo, timeRange, options)
+		if err != nil {
+			return nil, err
+		}
+		allPRs = append(allPRs, attributeSelfReviews(allPRs, reviewedPRs, r.username)...)
+	}
+	
+	// Get PRs the user closed without merging if enabled
+	if options.IncludeClosedByUser {
+		closedPRs, err := r.searchClosedByUserPullRequests(ctx, org, repo, timeRange, options)
+		if err != nil {
+			return nil, err
+		}
+		allPRs = mergeClosedByUser(allPRs, closedPRs)
+	}
+	
+	// Get PRs whose only activity is the user changing their metadata if
+	// enabled, which none of the searches above match
+	if options.IncludeMetadataActions {
+		editedPRs, err := r.searchMetadataEditedPullRequests(ctx, org, repo, timeRange, options, allPRs)
+		if err != nil {
+			return nil, err
+		}
+		allPRs = append(allPRs, editedPRs...)
+	}
+	
+	// Filter by title if requested, before spending calls on enrichment
+	if options.TitlePrefix != "" || options.TitlePattern != nil {
+		allPRs = filterByTitle(allPRs, options.TitlePrefix, options.TitlePattern)
+	}
+	
+	// Filter by head or base branch if requested, before spending calls on
+	// enrichment
+	if options.HeadBranchPrefix != "" || options.BaseBranchPattern != "" {
+		filtered, err := r.filterByBranch(ctx, org, repo, allPRs, options.HeadBranchPrefix, options.BaseBranchPattern)
 		if err != nil {
 			return nil, err
 		}
-		allPRs = append(allPRs, reviewedPRs...)
+		allPRs = filtered
+	}
+	
+	if options.ResolveDisplayNames {
+		r.resolveDisplayNames(ctx, allPRs, options.MaxConcurrency)
 	}
 	
-	// Enrich pull requests with commits, reviews, and comments
+	// Enrich pull requests with commits, reviews, and comments, stopping
+	// once the context is canceled as every remaining call would fail
 	for i := range allPRs {
-		if options.IncludeCommits {
-			commits, err := r.getCommits(org, repo, allPRs[i].Number, timeRange)
-			if err != nil {
-				return nil, err
-			}
-			allPRs[i].Commits = commits
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r.enrichPullRequest(ctx, org, repo, &allPRs[i], timeRange, options)
+	}
+	
+	return allPRs, nil
+}
+
+// GetPullRequest fetches a single pull request by number and enriches it
+// like a search result. The pull request is reported as authored when it is
+// the user's and as reviewed otherwise, so the user's reviews are included.
+func (r *GitHubAPIRepository) GetPullRequest(ctx context.Context, org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error) {
+	ctx = r.requestContext(ctx)
+	details, err := r.getPullRequestDetails(ctx, org, repo, number)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	
+	pr := pullRequestFromDetails(details)
+	pr.ExtractedFields = extractBodyFields(details.GetBody(), options.BodyFieldPatterns)
+	if options.includeSize() {
+		pr.SizeCategory = options.SizeThresholds.Category(pr.Additions + pr.Deletions)
+	}
+	if pr.Author == r.username {
+		pr.IsAuthored = true
+	} else {
+		pr.IsReviewed = true
+	}
+	
+	if options.ResolveDisplayNames {
+		prs := []PullRequest{pr}
+		r.resolveDisplayNames(ctx, prs, options.MaxConcurrency)
+		pr = prs[0]
+	}
+	
+	r.enrichPullRequest(ctx, org, repo, &pr, timeRange, options)
+	
+	return pr, nil
+}
+
+// SearchPullRequests runs a raw search query, such as a saved search, instead
+// of the built-in per-repository queries, and groups the matching pull
+// requests by the repository each one belongs to. The time range is appended
+// unless the query already has an updated: qualifier. Pull requests by the
+// user are reported as authored and the rest as reviewed.
+func (r *GitHubAPIRepository) SearchPullRequests(ctx context.Context, query string, timeRange TimeRange, options QueryOptions) ([]Repository, error) {
+	ctx = r.requestContext(ctx)
+	searchOptions := &externalGithub.SearchOptions{
+		Sort:  "updated",
+		Order: "desc",
+		ListOptions: externalGithub.ListOptions{PerPage: options.MaxResults},
+	}
+	
+	result, _, err := r.search.Issues(ctx, customQueryWithTimeRange(query, timeRange), searchOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search pull requests: %w", wrapSearchError(err))
+	}
+	
+	var repositories []Repository
+	index := make(map[string]int)
+	for _, issue := range result.Issues {
+		// The query may match issues too, which have no pull request links
+		if issue.PullRequestLinks == nil {
+			continue
+		}
+		org, repo, ok := repositoryFromIssue(issue)
+		if !ok {
+			continue
 		}
 		
-		if options.IncludeComments {
-			comments, err := r.getComments(org, repo, allPRs[i].Number, timeRange)
-			if err != nil {
+		pr := pullRequestFromIssue(issue)
+		pr.ExtractedFields = extractBodyFields(issue.GetBody(), options.BodyFieldPatterns)
+		pr.IsAuthored = pr.Author == r.username
+		pr.IsReviewed = !pr.IsAuthored
+		
+		key := org + "/" + repo
+		i, found := index[key]
+		if !found {
+			i = len(repositories)
+			index[key] = i
+			repositories = append(repositories, Repository{Name: repo, Organization: org})
+		}
+		repositories[i].PullRequests = append(repositories[i].PullRequests, pr)
+	}
+	
+	for i := range repositories {
+		repo := &repositories[i]
+		if options.TitlePrefix != "" || options.TitlePattern != nil {
+			repo.PullRequests = filterByTitle(repo.PullRequests, options.TitlePrefix, options.TitlePattern)
+		}
+		if options.ResolveDisplayNames {
+			r.resolveDisplayNames(ctx, repo.PullRequests, options.MaxConcurrency)
+		}
+		for j := range repo.PullRequests {
+			if err := ctx.Err(); err != nil {
 				return nil, err
 			}
-			allPRs[i].Comments = comments
+			r.enrichPullRequest(ctx, repo.Organization, repo.Name, &repo.PullRequests[j], timeRange, options)
 		}
-		
-		if allPRs[i].IsReviewed {
-			reviews, err := r.getReviews(org, repo, allPRs[i].Number, timeRange)
+	}
+	
+	return repositories, nil
+}
+
+// customQueryWithTimeRange appends the time range to a raw search query
+// unless it already restricts the update time itself
+func customQueryWithTimeRange(query string, timeRange TimeRange) string {
+	query = strings.TrimSpace(query)
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "updated:") {
+			return query
+		}
+	}
+	
+	return fmt.Sprintf("%s updated:%s..%s", query,
+		timeRange.Start.Format("2006-01-02"),
+		timeRange.End.Format("2006-01-02"))
+}
+
+// repositoryFromIssue returns the organization and name of the repository a
+// search result belongs to, taken from its repository API URL
+func repositoryFromIssue(issue *externalGithub.Issue) (string, string, bool) {
+	parts := strings.Split(strings.TrimSuffix(issue.GetRepositoryURL(), "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", false
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], true
+}
+
+// attributeSelfReviews drops reviewed pull requests that the user authored,
+// so a user reviewing their own pull request is only reported under the
+// authored section
+func attributeSelfReviews(authoredPRs []PullRequest, reviewedPRs []PullRequest, username string) []PullRequest {
+	authored := make(map[int]bool, len(authoredPRs))
+	for _, pr := range authoredPRs {
+		authored[pr.Number] = true
+	}
+	
+	filtered := make([]PullRequest, 0, len(reviewedPRs))
+	for _, pr := range reviewedPRs {
+		if authored[pr.Number] || pr.Author == username {
+			continue
+		}
+		filtered = append(filtered, pr)
+	}
+	
+	return filtered
+}
+
+// enrichPullRequest fetches the merge commit, commits, reviews, and comments
+// of a pull request. The independent fetches run concurrently, bounded by
+// options.MaxConcurrency across the whole report. A failing step is recorded
+// on the pull request and logged rather than failing the whole repository,
+// so the remaining steps and pull requests are still enriched.
+func (r *GitHubAPIRepository) enrichPullRequest(ctx context.Context, org string, repo string, pr *PullRequest, timeRange TimeRange, options QueryOptions) {
+	// Each step writes only its own fields and error slot, so steps can run
+	// concurrently while errors are still recorded in a stable order
+	const (
+		stepDetails = iota
+		stepCommits
+		stepReviews
+		stepFiles
+		stepThreads
+		stepProject
+		stepFeedback
+		stepMetadata
+		stepComments
+		stepCount
+	)
+	var errs [stepCount]error
+	
+	// Steps take a slot of the report's limiter, so pull requests enriched
+	// concurrently share options.MaxConcurrency rather than each having it
+	limiter := concurrencyLimiterFrom(ctx, options.MaxConcurrency)
+	g := new(errgroup.Group)
+	goLimited := func(step func() error) {
+		g.Go(func() error {
+			var err error
+			limiter.do(func() { err = step() })
+			return err
+		})
+	}
+	
+	// Review comments are submitted as part of a review, so a reviewed PR
+	// without reviews in range has no comments worth fetching
+	fetchComments := func() {
+		if !options.IncludeComments || (!pr.IsAuthored && len(pr.Reviews) == 0) {
+			return
+		}
+		comments, err := r.getComments(ctx, org, repo, pr.Number, timeRange, options)
+		if err != nil {
+			errs[stepComments] = err
+			return
+		}
+		pr.Comments = comments
+		if options.IncludeThreadsStarted {
+			pr.ThreadsStarted = countThreadsStarted(comments)
+		}
+	}
+	
+	// Only merged pull requests have a merge commit worth the extra call,
+	// and only open ones have a mergeability worth reporting
+	includeMergeable := options.IncludeMergeable && pr.State == "open"
+	includeSize := options.includeSize() && pr.SizeCategory == ""
+	if pr.State == "merged" || includeMergeable || includeSize {
+		goLimited(func() error {
+			details, err := r.getPullRequestDetails(ctx, org, repo, pr.Number)
+			if err == nil && includeMergeable {
+				details, err = r.waitForMergeability(ctx, org, repo, details)
+			}
 			if err != nil {
-				return nil, err
+				errs[stepDetails] = err
+				return nil
 			}
-			allPRs[i].Reviews = reviews
-		}
+			if pr.State == "merged" {
+				pr.MergeCommitSHA = details.GetMergeCommitSHA()
+			}
+			if includeSize {
+				pr.Additions = details.GetAdditions()
+				pr.Deletions = details.GetDeletions()
+				pr.SizeCategory = options.SizeThresholds.Category(pr.Additions + pr.Deletions)
+			}
+			if includeMergeable {
+				pr.Mergeable = details.Mergeable
+				pr.MergeableState = details.GetMergeableState()
+			}
+			return nil
+		})
 	}
 	
-	return allPRs, nil
+	if options.IncludeCommits {
+		goLimited(func() error {
+			commits, err := r.getCommits(ctx, org, repo, pr.Number, timeRange, options)
+			if err != nil {
+				errs[stepCommits] = err
+				return nil
+			}
+			pr.Commits = commits
+			return nil
+		})
+	}
+	
+	// The review decision needs every reviewer's reviews, so both share a
+	// fetch, which is skipped when searching already fetched them
+	fetchedReviews := pr.fetchedReviews
+	pr.fetchedReviews = nil
+	includeUserReviews := pr.IsReviewed || (pr.IsAuthored && options.IncludeSelfReviews)
+	if includeUserReviews || options.IncludeReviewDecision {
+		goLimited(func() error {
+			reviews := fetchedReviews
+			var err error
+			if reviews == nil {
+				reviews, err = r.getReviews(ctx, org, repo, pr.Number)
+			}
+			if err != nil {
+				errs[stepReviews] = err
+			} else {
+				if includeUserReviews {
+					pr.Reviews = r.userReviewsInRange(reviews, timeRange, options.ReviewStates)
+					if options.LatestReviewPerAuthor {
+						pr.Reviews = latestReviewPerAuthor(pr.Reviews)
+					}
+					pr.HasPendingReview = r.hasPendingReview(reviews)
+				}
+				if options.IncludeReviewDecision {
+					pr.ReviewDecision = reviewDecision(reviews)
+				}
+			}
+			
+			// Comments on other authors' PRs depend on the user's reviews
+			if !pr.IsAuthored {
+				fetchComments()
+			}
+			return nil
+		})
+	}
+	
+	if options.IncludeFiles {
+		goLimited(func() error {
+			files, err := r.getChangedFilePaths(ctx, org, repo, pr.Number, options.MaxFiles)
+			if err != nil {
+				errs[stepFiles] = err
+				return nil
+			}
+			pr.ChangedFilePaths = files
+			return nil
+		})
+	}
+	
+	if options.IncludeUnresolvedThreads {
+		goLimited(func() error {
+			unresolved, err := r.getUnresolvedThreads(ctx, org, repo, pr.Number)
+			if err != nil {
+				errs[stepThreads] = err
+				return nil
+			}
+			pr.UnresolvedThreads = unresolved
+			return nil
+		})
+	}
+	
+	if options.IncludeFeedbackReceived && pr.IsAuthored {
+		goLimited(func() error {
+			reviews, comments, err := r.getFeedbackReceived(ctx, org, repo, pr.Number, timeRange, options)
+			if err != nil {
+				errs[stepFeedback] = err
+				return nil
+			}
+			pr.ReceivedReviews = reviews
+			pr.ReceivedComments = comments
+			return nil
+		})
+	}
+	
+	// Pull requests found by their metadata actions already have them
+	if options.IncludeMetadataActions && !pr.IsMetadataEdited {
+		goLimited(func() error {
+			actions, err := r.getMetadataActions(ctx, org, repo, pr.Number, timeRange)
+			if err != nil {
+				errs[stepMetadata] = err
+				return nil
+			}
+			pr.MetadataActions = actions
+			return nil
+		})
+	}
+	
+	if options.IncludeProjectStatus {
+		goLimited(func() error {
+			status, err := r.getProjectStatus(ctx, org, repo, pr.Number)
+			if err != nil {
+				errs[stepProject] = err
+				return nil
+			}
+			pr.ProjectStatus = status
+			return nil
+		})
+	}
+	
+	if pr.IsAuthored {
+		goLimited(func() error {
+			fetchComments()
+			return nil
+		})
+	}
+	
+	g.Wait()
+	
+	for _, err := range errs {
+		if err != nil {
+			Logger.Printf("Error enriching PR #%d in %s/%s: %v\n", pr.Number, org, repo, err)
+			pr.EnrichmentErrors = append(pr.EnrichmentErrors, err.Error())
+			if pr.enrichmentErr == nil {
+				pr.enrichmentErr = err
+			}
+		}
+	}
 }
 
-// searchAuthoredPullRequests searches for pull requests authored by the user
-func (r *GitHubAPIRepository) searchAuthoredPullRequests(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
-	ctx := context.Background()
-	
-	query := fmt.Sprintf(
-		"is:pr author:%s repo:%s/%s base:%s updated:%s..%s",
-		r.username,
-		org,
-		repo,
-		options.BaseBranch,
+// AnyBaseBranch is the BaseBranch wildcard that disables base branch filtering
+const AnyBaseBranch = "any"
+
+// buildPullRequestQuery builds a pull request search query from the given
+// user qualifiers, scoped to the repository, base branch, milestone, and
+// time range
+func buildPullRequestQuery(userQualifiers string, org string, repo string, timeRange TimeRange, options QueryOptions) string {
+	qualifiers := []string{
+		"is:pr",
+		userQualifiers,
+		fmt.Sprintf("repo:%s/%s", org, repo),
+	}
+	
+	// An empty base branch or the "any" wildcard matches every base branch,
+	// and a base branch pattern is matched after the search instead
+	if options.BaseBranch != "" && options.BaseBranch != AnyBaseBranch && options.BaseBranchPattern == "" {
+		qualifiers = append(qualifiers, fmt.Sprintf("base:%s", options.BaseBranch))
+	}
+	
+	// Quote the milestone so names containing spaces stay a single qualifier
+	if options.Milestone != "" {
+		qualifiers = append(qualifiers, fmt.Sprintf("milestone:\"%s\"", strings.ReplaceAll(options.Milestone, "\"", "")))
+	}
+	
+	qualifiers = append(qualifiers, fmt.Sprintf("updated:%s..%s",
 		timeRange.Start.Format("2006-01-02"),
 		timeRange.End.Format("2006-01-02"),
-	)
+	))
+	
+	return strings.Join(qualifiers, " ")
+}
+
+// searchAuthoredPullRequests searches for pull requests authored by the user
+func (r *GitHubAPIRepository) searchAuthoredPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+	query := buildPullRequestQuery(fmt.Sprintf("author:%s", r.username), org, repo, timeRange, options)
 	
 	searchOptions := &externalGithub.SearchOptions{
 		ListOptions: externalGithub.ListOptions{PerPage: options.MaxResults},
 	}
 	
-	result, _, err := r.client.Search.Issues(ctx, query, searchOptions)
+	result, _, err := r.search.Issues(ctx, query, searchOptions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search authored pull requests: %w", err)
+		return nil, fmt.Errorf("failed to search authored pull requests: %w", wrapSearchError(err))
 	}
 	
 	prs := make([]PullRequest, 0, len(result.Issues))
 	for _, issue := range result.Issues {
-		prs = append(prs, PullRequest{
-			Number:     issue.GetNumber(),
-			Title:      issue.GetTitle(),
-			URL:        issue.GetHTMLURL(),
-			State:      issue.GetState(),
-			CreatedAt:  issue.GetCreatedAt().Time,
-			UpdatedAt:  issue.GetUpdatedAt().Time,
-			Author:     issue.GetUser().GetLogin(),
-			IsAuthored: true,
-		})
+		pr := pullRequestFromIssue(issue)
+		pr.ExtractedFields = extractBodyFields(issue.GetBody(), options.BodyFieldPatterns)
+		pr.IsAuthored = true
+		prs = append(prs, pr)
 	}
 	
 	return prs, nil
 }
 
 // searchReviewedPullRequests searches for pull requests reviewed by the user
-func (r *GitHubAPIRepository) searchReviewedPullRequests(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
-	ctx := context.Background()
-	
-	query := fmt.Sprintf(
-		"is:pr -author:%s reviewed-by:%s repo:%s/%s base:%s updated:%s..%s",
-		r.username,
-		r.username,
-		org,
-		repo,
-		options.BaseBranch,
-		timeRange.Start.Format("2006-01-02"),
-		timeRange.End.Format("2006-01-02"),
-	)
+func (r *GitHubAPIRepository) searchReviewedPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+	query := buildPullRequestQuery(fmt.Sprintf("-author:%s reviewed-by:%s", r.username, r.username), org, repo, timeRange, options)
 	
 	searchOptions := &externalGithub.SearchOptions{
 		Sort:  "updated",
@@ -155,108 +657,724 @@ func (r *GitHubAPIRepository) searchReviewedPullRequests(org string, repo string
 		ListOptions: externalGithub.ListOptions{PerPage: options.MaxResults},
 	}
 	
-	result, _, err := r.client.Search.Issues(ctx, query, searchOptions)
+	result, _, err := r.search.Issues(ctx, query, searchOptions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search reviewed pull requests: %w", err)
+		return nil, fmt.Errorf("failed to search reviewed pull requests: %w", wrapSearchError(err))
 	}
 	
 	prs := make([]PullRequest, 0, len(result.Issues))
 	for _, issue := range result.Issues {
-		prs = append(prs, PullRequest{
-			Number:     issue.GetNumber(),
-			Title:      issue.GetTitle(),
-			URL:        issue.GetHTMLURL(),
-			State:      issue.GetState(),
-			CreatedAt:  issue.GetCreatedAt().Time,
-			UpdatedAt:  issue.GetUpdatedAt().Time,
-			Author:     issue.GetUser().GetLogin(),
-			IsReviewed: true,
-		})
+		if options.OnlyRequestedReviews {
+			requested, err := r.wasReviewRequested(ctx, org, repo, issue.GetNumber())
+			if err != nil {
+				return nil, err
+			}
+			if !requested {
+				continue
+			}
+		}
+		
+		var fetchedReviews []Review
+		if options.OnlyReviewedInRange {
+			reviews, err := r.getReviews(ctx, org, repo, issue.GetNumber())
+			if err != nil {
+				return nil, err
+			}
+			if len(r.userReviewsInRange(reviews, timeRange, options.ReviewStates)) == 0 {
+				continue
+			}
+			fetchedReviews = reviews
+		}
+		
+		pr := pullRequestFromIssue(issue)
+		pr.ExtractedFields = extractBodyFields(issue.GetBody(), options.BodyFieldPatterns)
+		pr.IsReviewed = true
+		pr.fetchedReviews = fetchedReviews
+		prs = append(prs, pr)
 	}
 	
 	return prs, nil
 }
 
-// getCommits retrieves commits for a pull request
-func (r *GitHubAPIRepository) getCommits(org string, repo string, prNumber int, timeRange TimeRange) ([]Commit, error) {
-	ctx := context.Background()
+// wasReviewRequested reports whether the user was ever requested as a
+// reviewer on the pull request, according to its issue events. The search
+// API's review-requested qualifier only matches pending requests, which
+// GitHub clears once the user submits a review, so it cannot be used here.
+func (r *GitHubAPIRepository) wasReviewRequested(ctx context.Context, org string, repo string, prNumber int) (bool, error) {
+	opts := &externalGithub.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := r.issues.ListIssueEvents(ctx, org, repo, prNumber, opts)
+		if err != nil {
+			return false, fmt.Errorf("failed to list events for PR #%d: %w", prNumber, wrapAPIError(err))
+		}
+		
+		for _, event := range events {
+			if event.GetEvent() == "review_requested" &&
+				event.GetRequestedReviewer().GetLogin() == r.username {
+				return true, nil
+			}
+		}
+		
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// searchClosedByUserPullRequests finds other authors' pull requests the user
+// closed without merging. The search API has no "closed-by" qualifier, so
+// unmerged pull requests closed in the time range are searched and each
+// candidate's issue events are checked for a close by the user.
+func (r *GitHubAPIRepository) searchClosedByUserPullRequests(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+	qualifiers := fmt.Sprintf("-author:%s is:closed is:unmerged closed:%s..%s", r.username,
+		timeRange.Start.Format("2006-01-02"),
+		timeRange.End.Format("2006-01-02"))
+	query := buildPullRequestQuery(qualifiers, org, repo, timeRange, options)
+	
+	searchOptions := &externalGithub.SearchOptions{
+		ListOptions: externalGithub.ListOptions{PerPage: options.MaxResults},
+	}
+	
+	result, _, err := r.search.Issues(ctx, query, searchOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search closed pull requests: %w", wrapSearchError(err))
+	}
+	
+	prs := make([]PullRequest, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		closedByUser, err := r.isClosedByUser(ctx, org, repo, issue.GetNumber(), timeRange)
+		if err != nil {
+			return nil, err
+		}
+		if !closedByUser {
+			continue
+		}
+		
+		pr := pullRequestFromIssue(issue)
+		pr.ExtractedFields = extractBodyFields(issue.GetBody(), options.BodyFieldPatterns)
+		pr.IsClosedByUser = true
+		prs = append(prs, pr)
+	}
+	
+	return prs, nil
+}
+
+// isClosedByUser reports whether the user closed the pull request within the
+// time range, according to its issue events
+func (r *GitHubAPIRepository) isClosedByUser(ctx context.Context, org string, repo string, prNumber int, timeRange TimeRange) (bool, error) {
+	opts := &externalGithub.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := r.issues.ListIssueEvents(ctx, org, repo, prNumber, opts)
+		if err != nil {
+			return false, fmt.Errorf("failed to list events for PR #%d: %w", prNumber, wrapAPIError(err))
+		}
+		
+		for _, event := range events {
+			if event.GetEvent() == "closed" &&
+				event.GetActor().GetLogin() == r.username &&
+				timeRange.IsInRange(event.GetCreatedAt().Time) {
+				return true, nil
+			}
+		}
+		
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// mergeClosedByUser flags pull requests already found by another search as
+// closed by the user, and appends the rest
+func mergeClosedByUser(prs []PullRequest, closedPRs []PullRequest) []PullRequest {
+	for _, closed := range closedPRs {
+		found := false
+		for i := range prs {
+			if prs[i].Number == closed.Number {
+				prs[i].IsClosedByUser = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			prs = append(prs, closed)
+		}
+	}
+	
+	return prs
+}
+
+// pullRequestFromIssue maps a search result to a PullRequest. The search API
+// reports merged pull requests as closed, so the merge timestamp is used to
+// tell them apart.
+func pullRequestFromIssue(issue *externalGithub.Issue) PullRequest {
+	pr := PullRequest{
+		Number:    issue.GetNumber(),
+		Title:     issue.GetTitle(),
+		URL:       issue.GetHTMLURL(),
+		State:     issue.GetState(),
+		Draft:     issue.GetDraft(),
+		CreatedAt: issue.GetCreatedAt().Time,
+		UpdatedAt: issue.GetUpdatedAt().Time,
+		Author:    issue.GetUser().GetLogin(),
+		Milestone: issue.GetMilestone().GetTitle(),
+		ClosedAt:  issue.GetClosedAt().Time,
+		
+		AuthorAvatarURL: issue.GetUser().GetAvatarURL(),
+		AuthorURL:       issue.GetUser().GetHTMLURL(),
+		DependsOn:       parseDependencies(issue.GetBody()),
+	}
 	
-	prCommits, _, err := r.client.PullRequests.ListCommits(ctx, org, repo, prNumber, nil)
+	for _, label := range issue.Labels {
+		pr.Labels = append(pr.Labels, Label{
+			Name:  label.GetName(),
+			Color: label.GetColor(),
+		})
+	}
+	
+	if mergedAt := issue.GetPullRequestLinks().GetMergedAt(); !mergedAt.IsZero() {
+		pr.State = "merged"
+		pr.MergedAt = mergedAt.Time
+	}
+	
+	return pr
+}
+
+// pullRequestFromDetails maps a fetched pull request to a PullRequest
+func pullRequestFromDetails(details *externalGithub.PullRequest) PullRequest {
+	pr := PullRequest{
+		Number:     details.GetNumber(),
+		Title:      details.GetTitle(),
+		URL:        details.GetHTMLURL(),
+		State:      details.GetState(),
+		Draft:      details.GetDraft(),
+		CreatedAt:  details.GetCreatedAt().Time,
+		UpdatedAt:  details.GetUpdatedAt().Time,
+		Author:     details.GetUser().GetLogin(),
+		HeadBranch: details.GetHead().GetRef(),
+		BaseBranch: details.GetBase().GetRef(),
+		Milestone:  details.GetMilestone().GetTitle(),
+		ClosedAt:   details.GetClosedAt().Time,
+		Additions:  details.GetAdditions(),
+		Deletions:  details.GetDeletions(),
+		
+		AuthorAvatarURL: details.GetUser().GetAvatarURL(),
+		AuthorURL:       details.GetUser().GetHTMLURL(),
+		DependsOn:       parseDependencies(details.GetBody()),
+	}
+	
+	for _, label := range details.Labels {
+		pr.Labels = append(pr.Labels, Label{
+			Name:  label.GetName(),
+			Color: label.GetColor(),
+		})
+	}
+	
+	if mergedAt := details.GetMergedAt(); !mergedAt.IsZero() {
+		pr.State = "merged"
+		pr.MergedAt = mergedAt.Time
+	}
+	
+	return pr
+}
+
+// getPullRequestDetails retrieves the full pull request, which carries fields
+// the search API does not return (head branch, merge state, etc.)
+func (r *GitHubAPIRepository) getPullRequestDetails(ctx context.Context, org string, repo string, prNumber int) (*externalGithub.PullRequest, error) {
+	pr, _, err := r.pullRequests.Get(ctx, org, repo, prNumber)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list commits for PR #%d: %w", prNumber, err)
+		return nil, fmt.Errorf("failed to get PR #%d: %w", prNumber, wrapAPIError(err))
+	}
+	
+	return pr, nil
+}
+
+// mergeabilityAttempts is the number of times a pull request is fetched while
+// GitHub computes its mergeability in the background
+const mergeabilityAttempts = 3
+
+// mergeabilityRetryDelay is the wait between mergeability attempts
+var mergeabilityRetryDelay = time.Second
+
+// waitForMergeability refetches the pull request until GitHub has computed
+// whether it can be merged. GitHub starts the computation on the first
+// request and reports a nil mergeable until it completes; if it is still
+// pending after the last attempt, the state is reported as unknown.
+func (r *GitHubAPIRepository) waitForMergeability(ctx context.Context, org string, repo string, details *externalGithub.PullRequest) (*externalGithub.PullRequest, error) {
+	for attempt := 1; details.Mergeable == nil && attempt < mergeabilityAttempts; attempt++ {
+		if err := sleepContext(ctx, mergeabilityRetryDelay); err != nil {
+			return nil, err
+		}
+		
+		var err error
+		details, err = r.getPullRequestDetails(ctx, org, repo, details.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	if details.Mergeable == nil {
+		details.MergeableState = externalGithub.Ptr("unknown")
+	}
+	return details, nil
+}
+
+// filterByTitle keeps only pull requests whose title starts with the prefix
+// and matches the pattern. An empty prefix or nil pattern matches every title.
+func filterByTitle(prs []PullRequest, prefix string, pattern *regexp.Regexp) []PullRequest {
+	filtered := make([]PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if !strings.HasPrefix(pr.Title, prefix) {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(pr.Title) {
+			continue
+		}
+		filtered = append(filtered, pr)
+	}
+	return filtered
+}
+
+// filterByBranch populates the head and base branches of each pull request
+// and keeps only those whose head branch starts with headPrefix and whose
+// base branch matches the basePattern glob. An empty prefix or pattern
+// matches every branch.
+func (r *GitHubAPIRepository) filterByBranch(ctx context.Context, org string, repo string, prs []PullRequest, headPrefix string, basePattern string) ([]PullRequest, error) {
+	filtered := make([]PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		details, err := r.getPullRequestDetails(ctx, org, repo, pr.Number)
+		if err != nil {
+			return nil, err
+		}
+		
+		pr.HeadBranch = details.GetHead().GetRef()
+		pr.BaseBranch = details.GetBase().GetRef()
+		if !strings.HasPrefix(pr.HeadBranch, headPrefix) {
+			continue
+		}
+		if basePattern != "" {
+			// The pattern is validated when configured, so a malformed
+			// one simply matches nothing
+			if matched, _ := path.Match(basePattern, pr.BaseBranch); !matched {
+				continue
+			}
+		}
+		filtered = append(filtered, pr)
+	}
+	
+	return filtered, nil
+}
+
+// getCommits retrieves commits for a pull request
+func (r *GitHubAPIRepository) getCommits(ctx context.Context, org string, repo string, prNumber int, timeRange TimeRange, options QueryOptions) ([]Commit, error) {
+	prCommits, _, err := r.pullRequests.ListCommits(ctx, org, repo, prNumber, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits for PR #%d: %w", prNumber, wrapAPIError(err))
 	}
 	
 	commits := make([]Commit, 0)
 	for _, prCommit := range prCommits {
-		commitTime := prCommit.GetCommit().GetCommitter().GetDate().Time
+		commitTime := commitDate(prCommit, options.UseAuthorDate)
 		
 		// Only include commits within the time range
-		if timeRange.IsInRange(commitTime) {
-			commits = append(commits, Commit{
-				SHA:       prCommit.GetSHA(),
-				Message:   prCommit.GetCommit().GetMessage(),
-				Author:    prCommit.GetCommit().GetAuthor().GetName(),
-				Timestamp: commitTime,
-			})
+		if !timeRange.IsInRange(commitTime) {
+			continue
 		}
+		
+		if options.PathPrefix != "" {
+			touches, err := r.commitTouchesPath(ctx, org, repo, prCommit.GetSHA(), options.PathPrefix)
+			if err != nil {
+				return nil, err
+			}
+			if !touches {
+				continue
+			}
+		}
+		
+		commits = append(commits, commitFromRepositoryCommit(prCommit, commitTime))
 	}
 	
 	return commits, nil
 }
 
-// getComments retrieves comments for a pull request
-func (r *GitHubAPIRepository) getComments(org string, repo string, prNumber int, timeRange TimeRange) ([]Comment, error) {
-	ctx := context.Background()
+// commitFromRepositoryCommit maps a commit returned by the API to a Commit
+// made at the given time
+func commitFromRepositoryCommit(commit *externalGithub.RepositoryCommit, timestamp time.Time) Commit {
+	verification := commit.GetCommit().GetVerification()
+	return Commit{
+		SHA:                commit.GetSHA(),
+		Message:            commit.GetCommit().GetMessage(),
+		Author:             commit.GetCommit().GetAuthor().GetName(),
+		Timestamp:          timestamp,
+		Verified:           verification.GetVerified(),
+		VerificationReason: verification.GetReason(),
+		ConventionalType:   conventionalCommitType(commit.GetCommit().GetMessage()),
+	}
+}
+
+// GetDirectCommits lists the user's commits on the repository's default
+// branch within the time range that did not come through a pull request,
+// such as hotfixes pushed straight to the branch
+func (r *GitHubAPIRepository) GetDirectCommits(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Commit, error) {
+	ctx = r.requestContext(ctx)
+	// Without a SHA the API lists the default branch
+	listOptions := &externalGithub.CommitsListOptions{
+		Author:      r.username,
+		Since:       timeRange.Start,
+		Until:       timeRange.End,
+		ListOptions: externalGithub.ListOptions{PerPage: options.MaxResults},
+	}
+	
+	commits := make([]Commit, 0)
+	for {
+		repoCommits, resp, err := r.repositories.ListCommits(ctx, org, repo, listOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits for %s/%s: %w", org, repo, wrapAPIError(err))
+		}
+		
+		for _, repoCommit := range repoCommits {
+			direct, err := r.isDirectCommit(ctx, org, repo, repoCommit.GetSHA())
+			if err != nil {
+				return nil, err
+			}
+			if direct {
+				commits = append(commits, commitFromRepositoryCommit(repoCommit, commitDate(repoCommit, options.UseAuthorDate)))
+			}
+		}
+		
+		if resp.NextPage == 0 {
+			break
+		}
+		listOptions.Page = resp.NextPage
+	}
+	
+	return commits, nil
+}
+
+// isDirectCommit reports whether a commit on the default branch was pushed
+// directly rather than merged through a pull request
+func (r *GitHubAPIRepository) isDirectCommit(ctx context.Context, org string, repo string, sha string) (bool, error) {
+	prs, _, err := r.pullRequests.ListPullRequestsWithCommit(ctx, org, repo, sha, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to list pull requests for commit %s: %w", sha, wrapAPIError(err))
+	}
+	return len(prs) == 0, nil
+}
+
+// GetAssignedIssues searches for the open issues in the repository assigned to
+// the user. Issues are listed however long ago they were updated, unless
+// AssignedIssuesInRange limits them to the time range.
+func (r *GitHubAPIRepository) GetAssignedIssues(ctx context.Context, org string, repo string, timeRange TimeRange, options QueryOptions) ([]Issue, error) {
+	ctx = r.requestContext(ctx)
+	query := fmt.Sprintf("is:issue is:open assignee:%s repo:%s/%s", r.username, org, repo)
+	if options.AssignedIssuesInRange {
+		query += fmt.Sprintf(" updated:%s..%s",
+			timeRange.Start.Format("2006-01-02"),
+			timeRange.End.Format("2006-01-02"),
+		)
+	}
+	
+	searchOptions := &externalGithub.SearchOptions{
+		Sort:        "updated",
+		Order:       "desc",
+		ListOptions: externalGithub.ListOptions{PerPage: options.MaxResults},
+	}
+	
+	result, _, err := r.search.Issues(ctx, query, searchOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search assigned issues: %w", wrapSearchError(err))
+	}
+	
+	issues := make([]Issue, 0, len(result.Issues))
+	for _, result := range result.Issues {
+		issue := Issue{
+			Number:    result.GetNumber(),
+			Title:     result.GetTitle(),
+			URL:       result.GetHTMLURL(),
+			State:     result.GetState(),
+			UpdatedAt: result.GetUpdatedAt().Time,
+		}
+		for _, label := range result.Labels {
+			issue.Labels = append(issue.Labels, Label{
+				Name:  label.GetName(),
+				Color: label.GetColor(),
+			})
+		}
+		issues = append(issues, issue)
+	}
+	
+	return issues, nil
+}
+
+// commitDate returns when a commit was made: its author date when
+// useAuthorDate is set, and its committer date otherwise
+func commitDate(commit *externalGithub.RepositoryCommit, useAuthorDate bool) time.Time {
+	if useAuthorDate {
+		return commit.GetCommit().GetAuthor().GetDate().Time
+	}
+	return commit.GetCommit().GetCommitter().GetDate().Time
+}
+
+// commitTouchesPath reports whether a commit changed any file under the prefix
+func (r *GitHubAPIRepository) commitTouchesPath(ctx context.Context, org string, repo string, sha string, prefix string) (bool, error) {
+	commit, _, err := r.repositories.GetCommit(ctx, org, repo, sha, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get commit %s: %w", sha, wrapAPIError(err))
+	}
+	
+	for _, file := range commit.Files {
+		if strings.HasPrefix(file.GetFilename(), prefix) {
+			return true, nil
+		}
+	}
 	
-	prComments, _, err := r.client.PullRequests.ListComments(ctx, org, repo, prNumber, nil)
+	return false, nil
+}
+
+// getComments retrieves comments for a pull request
+func (r *GitHubAPIRepository) getComments(ctx context.Context, org string, repo string, prNumber int, timeRange TimeRange, options QueryOptions) ([]Comment, error) {
+	prComments, err := listPullRequestComments(ctx, r.pullRequests, org, repo, prNumber, timeRange.Start)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list comments for PR #%d: %w", prNumber, err)
+		return nil, fmt.Errorf("failed to list comments for PR #%d: %w", prNumber, wrapAPIError(err))
 	}
 	
+	// The API only filters the start of the range; the end is filtered here
 	comments := make([]Comment, 0)
 	for _, prComment := range prComments {
 		commentTime := prComment.GetCreatedAt().Time
 		
 		// Only include comments within the time range and by the current user
-		if timeRange.IsInRange(commentTime) && prComment.GetUser().GetLogin() == r.username {
+		if !timeRange.IsInRange(commentTime) || prComment.GetUser().GetLogin() != r.username {
+			continue
+		}
+		
+		if options.PathPrefix != "" && !strings.HasPrefix(prComment.GetPath(), options.PathPrefix) {
+			continue
+		}
+		
+		if matchesAnyPath(prComment.GetPath(), options.ExcludePaths) {
+			continue
+		}
+		
+		comments = append(comments, commentFromPullRequestComment(prComment))
+	}
+	
+	// Conversation comments have no file, so a path filter leaves them out
+	if options.IncludeConversationComments && options.PathPrefix == "" {
+		conversation, err := r.getConversationComments(ctx, org, repo, prNumber, timeRange)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, conversation...)
+	}
+	
+	comments = dedupeComments(comments)
+	sortComments(comments)
+	return comments, nil
+}
+
+// getConversationComments retrieves the user's comments on the conversation
+// tab of a pull request within the time range
+func (r *GitHubAPIRepository) getConversationComments(ctx context.Context, org string, repo string, prNumber int, timeRange TimeRange) ([]Comment, error) {
+	since := timeRange.Start
+	opts := &externalGithub.IssueListCommentsOptions{
+		Since:       &since,
+		ListOptions: externalGithub.ListOptions{PerPage: 100},
+	}
+	
+	var comments []Comment
+	for {
+		issueComments, resp, err := r.issues.ListComments(ctx, org, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list conversation comments for PR #%d: %w", prNumber, wrapAPIError(err))
+		}
+		
+		for _, issueComment := range issueComments {
+			if !timeRange.IsInRange(issueComment.GetCreatedAt().Time) || issueComment.GetUser().GetLogin() != r.username {
+				continue
+			}
 			comments = append(comments, Comment{
-				ID:        prComment.GetID(),
-				Author:    prComment.GetUser().GetLogin(),
-				Body:      prComment.GetBody(),
-				Timestamp: commentTime,
-				Path:      prComment.GetPath(),
-				Position:  prComment.GetPosition(),
+				ID:        issueComment.GetID(),
+				Author:    issueComment.GetUser().GetLogin(),
+				Body:      issueComment.GetBody(),
+				Timestamp: issueComment.GetCreatedAt().Time,
+				Kind:      CommentKindConversation,
 			})
 		}
+		
+		if resp.NextPage == 0 {
+			return comments, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// commentFromPullRequestComment maps a review comment to a Comment
+func commentFromPullRequestComment(prComment *externalGithub.PullRequestComment) Comment {
+	return Comment{
+		ID:        prComment.GetID(),
+		Author:    prComment.GetUser().GetLogin(),
+		Body:      prComment.GetBody(),
+		Timestamp: prComment.GetCreatedAt().Time,
+		Path:      prComment.GetPath(),
+		Position:  prComment.GetPosition(),
+		InReplyTo: prComment.GetInReplyTo(),
+		ReviewID:  prComment.GetPullRequestReviewID(),
+		Kind:      CommentKindReview,
+	}
+}
+
+// matchesAnyPath reports whether the file path matches any of the glob
+// patterns. Patterns without a slash are matched against the file name alone.
+// Malformed patterns never match; they are rejected when configured.
+func matchesAnyPath(filePath string, patterns []string) bool {
+	if filePath == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		name := filePath
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(filePath)
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// listPullRequestComments retrieves every page of review comments updated
+// since the given time. Filtering at the API avoids transferring the full
+// history of long-lived pull requests.
+func listPullRequestComments(ctx context.Context, pullRequests pullRequestsService, org string, repo string, prNumber int, since time.Time) ([]*externalGithub.PullRequestComment, error) {
+	opts := &externalGithub.PullRequestListCommentsOptions{
+		Since:       since,
+		ListOptions: externalGithub.ListOptions{PerPage: 100},
 	}
 	
-	return comments, nil
+	var all []*externalGithub.PullRequestComment
+	for {
+		comments, resp, err := pullRequests.ListComments(ctx, org, repo, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, comments...)
+		
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
 }
 
-// getReviews retrieves reviews for a pull request
-func (r *GitHubAPIRepository) getReviews(org string, repo string, prNumber int, timeRange TimeRange) ([]Review, error) {
-	ctx := context.Background()
+// getChangedFilePaths retrieves the paths of up to max files changed by a
+// pull request
+func (r *GitHubAPIRepository) getChangedFilePaths(ctx context.Context, org string, repo string, prNumber int, max int) ([]string, error) {
+	if max <= 0 {
+		max = DefaultMaxFiles
+	}
 	
-	prReviews, _, err := r.client.PullRequests.ListReviews(ctx, org, repo, prNumber, nil)
+	opts := &externalGithub.ListOptions{PerPage: min(max, 100)}
+	paths := make([]string, 0, max)
+	for {
+		files, resp, err := r.pullRequests.ListFiles(ctx, org, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files for PR #%d: %w", prNumber, wrapAPIError(err))
+		}
+		
+		for _, file := range files {
+			paths = append(paths, file.GetFilename())
+			if len(paths) == max {
+				return paths, nil
+			}
+		}
+		
+		if resp.NextPage == 0 {
+			return paths, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// getReviews retrieves all reviews for a pull request
+func (r *GitHubAPIRepository) getReviews(ctx context.Context, org string, repo string, prNumber int) ([]Review, error) {
+	prReviews, _, err := r.pullRequests.ListReviews(ctx, org, repo, prNumber, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list reviews for PR #%d: %w", prNumber, err)
+		return nil, fmt.Errorf("failed to list reviews for PR #%d: %w", prNumber, wrapAPIError(err))
 	}
 	
-	reviews := make([]Review, 0)
+	reviews := make([]Review, 0, len(prReviews))
 	for _, prReview := range prReviews {
-		reviewTime := prReview.GetSubmittedAt().Time
-		
-		// Only include reviews within the time range and by the current user
-		if timeRange.IsInRange(reviewTime) && prReview.GetUser().GetLogin() == r.username {
-			reviews = append(reviews, Review{
-				ID:        prReview.GetID(),
-				Author:    prReview.GetUser().GetLogin(),
-				State:     prReview.GetState(),
-				Body:      prReview.GetBody(),
-				Timestamp: reviewTime,
-			})
-		}
+		reviews = append(reviews, Review{
+			ID:        prReview.GetID(),
+			Author:    prReview.GetUser().GetLogin(),
+			State:     prReview.GetState(),
+			Body:      prReview.GetBody(),
+			Timestamp: prReview.GetSubmittedAt().Time,
+			CommitID:  prReview.GetCommitID(),
+		})
 	}
 	
+	sortReviews(reviews)
 	return reviews, nil
-} 
+}
+
+// hasPendingReview reports whether the user has a review in progress that
+// was not submitted yet
+func (r *GitHubAPIRepository) hasPendingReview(reviews []Review) bool {
+	for _, review := range reviews {
+		if review.State == ReviewStatePending && review.Author == r.username {
+			return true
+		}
+	}
+	return false
+}
+
+// latestReviewPerAuthor collapses reviews to the most recently submitted
+// review of each author, keeping the order of the reviews that remain.
+// Pending reviews were never submitted and are dropped.
+func latestReviewPerAuthor(reviews []Review) []Review {
+	latest := make(map[string]Review)
+	for _, review := range reviews {
+		if review.State == ReviewStatePending {
+			continue
+		}
+		if current, ok := latest[review.Author]; !ok || !review.Timestamp.Before(current.Timestamp) {
+			latest[review.Author] = review
+		}
+	}
+	
+	collapsed := make([]Review, 0, len(latest))
+	for _, review := range reviews {
+		if kept, ok := latest[review.Author]; ok && kept.ID == review.ID {
+			collapsed = append(collapsed, review)
+		}
+	}
+	
+	return collapsed
+}
+
+// userReviewsInRange keeps the current user's reviews within the time range,
+// limited to the given review states unless states is empty
+func (r *GitHubAPIRepository) userReviewsInRange(reviews []Review, timeRange TimeRange, states []string) []Review {
+	filtered := make([]Review, 0)
+	for _, review := range reviews {
+		if timeRange.IsInRange(review.Timestamp) && review.Author == r.username && reviewStateCounts(review.State, states) {
+			filtered = append(filtered, review)
+		}
+	}
+	
+	return filtered
+}
+
+// reviewStateCounts reports whether a review in the given state counts as
+// review activity. Every state counts when states is empty.
+func reviewStateCounts(state string, states []string) bool {
+	if len(states) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(states, func(s string) bool {
+		return strings.EqualFold(s, state)
+	})
+}
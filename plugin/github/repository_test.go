@@ -0,0 +1,1337 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+// newTestClient creates a go-github client pointed at a local test server
+func newTestClient(t *testing.T) (*externalGithub.Client, *http.ServeMux) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := externalGithub.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("Failed to parse test server URL: %v", err)
+	}
+	client.BaseURL = baseURL
+
+	return client, mux
+}
+
+// writeJSON writes v as the JSON body of a test server response
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// searchResult builds a search API response containing the given PR numbers
+func searchResult(numbers ...int) map[string]any {
+	items := make([]map[string]any, 0, len(numbers))
+	for _, number := range numbers {
+		items = append(items, map[string]any{
+			"number":     number,
+			"title":      fmt.Sprintf("PR %d", number),
+			"state":      "open",
+			"html_url":   fmt.Sprintf("https://github.com/testorg/repo1/pull/%d", number),
+			"user":       map[string]any{"login": "testuser"},
+			"created_at": "2023-01-01T10:00:00Z",
+			"updated_at": "2023-01-01T12:00:00Z",
+		})
+	}
+	return map[string]any{"total_count": len(items), "items": items}
+}
+
+// reviewedSearchResult builds a search API response containing the given PR
+// numbers authored by someone other than the test user
+func reviewedSearchResult(numbers ...int) map[string]any {
+	result := searchResult(numbers...)
+	for _, item := range result["items"].([]map[string]any) {
+		item["user"] = map[string]any{"login": "otheruser"}
+	}
+	return result
+}
+
+// testTimeRange returns the time range used by the repository tests
+func testTimeRange() TimeRange {
+	return TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestGitHubAPIRepository_TypedErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected error
+	}{
+		{
+			name: "Unauthorized maps to ErrAuth",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"message": "Bad credentials"}`))
+			},
+			expected: ErrAuth,
+		},
+		{
+			name: "Exhausted rate limit maps to ErrRateLimited",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Limit", "30")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", "1893456000")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			},
+			expected: ErrRateLimited,
+		},
+		{
+			name: "Not found maps to ErrRepoNotFound",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message": "Not Found"}`))
+			},
+			expected: ErrRepoNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, mux := newTestClient(t)
+			mux.HandleFunc("/search/issues", tc.handler)
+
+			repository := NewGitHubAPIRepository(client, "testuser")
+			_, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), DefaultQueryOptions())
+			if err == nil {
+				t.Fatal("Expected an error but got nil")
+			}
+
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected error to wrap %v, got: %v", tc.expected, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected error to be an *APIError, got %T", err)
+			}
+
+			var errResp *externalGithub.ErrorResponse
+			var rateLimitErr *externalGithub.RateLimitError
+			if !errors.As(err, &errResp) && !errors.As(err, &rateLimitErr) {
+				t.Errorf("Expected original go-github error to be preserved, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestGitHubAPIRepository_HeadBranchPrefix(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1, 2, 3))
+	})
+
+	headBranches := map[int]string{
+		1: "feat/testuser/login",
+		2: "feat/someone/shared",
+		3: "feat/testuser/logout",
+	}
+	for number, ref := range headBranches {
+		mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d", number), func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, map[string]any{"number": number, "head": map[string]any{"ref": ref}})
+		})
+	}
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.HeadBranchPrefix = "feat/testuser/"
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	for _, pr := range prs {
+		if pr.HeadBranch != headBranches[pr.Number] {
+			t.Errorf("Expected head branch %q for PR #%d, got %q", headBranches[pr.Number], pr.Number, pr.HeadBranch)
+		}
+		if pr.Number == 2 {
+			t.Errorf("Expected PR #2 to be filtered out by head branch prefix")
+		}
+	}
+}
+
+func TestGitHubAPIRepository_BaseBranchPattern(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	var query string
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		writeJSON(t, w, searchResult(1, 2, 3))
+	})
+
+	baseBranches := map[int]string{
+		1: "release/1.0",
+		2: "main",
+		3: "release/2.0",
+	}
+	for number, ref := range baseBranches {
+		mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d", number), func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, map[string]any{"number": number, "base": map[string]any{"ref": ref}})
+		})
+	}
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.BaseBranchPattern = "release/*"
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if strings.Contains(query, "base:") {
+		t.Errorf("Expected no base qualifier with a base branch pattern, got query %q", query)
+	}
+
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	for _, pr := range prs {
+		if pr.BaseBranch != baseBranches[pr.Number] {
+			t.Errorf("Expected base branch %q for PR #%d, got %q", baseBranches[pr.Number], pr.Number, pr.BaseBranch)
+		}
+		if pr.Number == 2 {
+			t.Errorf("Expected PR #2 targeting main to be filtered out by base branch pattern")
+		}
+	}
+}
+
+func TestGitHubAPIRepository_MergeCommitSHA(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		result := searchResult(1, 2)
+		items := result["items"].([]map[string]any)
+		items[1]["state"] = "closed"
+		items[1]["pull_request"] = map[string]any{"merged_at": "2023-01-01T15:00:00Z"}
+		writeJSON(t, w, result)
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no details call for an open PR")
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"number": 2, "merge_commit_sha": "abcdef1234567890"})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	if prs[0].State != "open" || prs[0].MergeCommitSHA != "" {
+		t.Errorf("Expected open PR without merge commit, got state %q and SHA %q", prs[0].State, prs[0].MergeCommitSHA)
+	}
+	if prs[1].State != "merged" || prs[1].MergeCommitSHA != "abcdef1234567890" {
+		t.Errorf("Expected merged PR with merge commit, got state %q and SHA %q", prs[1].State, prs[1].MergeCommitSHA)
+	}
+}
+
+func TestGitHubAPIRepository_Mergeable(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	previousDelay := mergeabilityRetryDelay
+	mergeabilityRetryDelay = 0
+	t.Cleanup(func() { mergeabilityRetryDelay = previousDelay })
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1, 2, 3))
+	})
+	// PR #1 conflicts once GitHub finishes computing its mergeability
+	var conflictingCalls int
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		conflictingCalls++
+		if conflictingCalls == 1 {
+			writeJSON(t, w, map[string]any{"number": 1, "mergeable": nil, "mergeable_state": "unknown"})
+			return
+		}
+		writeJSON(t, w, map[string]any{"number": 1, "mergeable": false, "mergeable_state": "dirty"})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"number": 2, "mergeable": true, "mergeable_state": "clean"})
+	})
+	// PR #3 never finishes computing
+	mux.HandleFunc("/repos/testorg/repo1/pulls/3", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"number": 3, "mergeable": nil})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeMergeable = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 3 {
+		t.Fatalf("Expected 3 pull requests, got %d", len(prs))
+	}
+	if !prs[0].HasConflicts() || prs[0].MergeableState != "dirty" {
+		t.Errorf("Expected PR #1 to have conflicts, got mergeable %v and state %q", prs[0].Mergeable, prs[0].MergeableState)
+	}
+	if prs[1].HasConflicts() || prs[1].Mergeable == nil || !*prs[1].Mergeable {
+		t.Errorf("Expected PR #2 to be mergeable, got %v", prs[1].Mergeable)
+	}
+	if prs[2].Mergeable != nil || prs[2].MergeableState != "unknown" {
+		t.Errorf("Expected PR #3 mergeability to be unknown, got %v and state %q", prs[2].Mergeable, prs[2].MergeableState)
+	}
+
+	content, err := NewMarkdownFormatter().Format(&ActivityReport{
+		TimeRange:    testTimeRange(),
+		Repositories: []Repository{{Name: "repo1", Organization: "testorg", PullRequests: prs}},
+	})
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Count(content.Content, "⚠️ conflicts") != 1 {
+		t.Errorf("Expected one conflicts indicator, got:\n%s", content.Content)
+	}
+}
+
+func TestGitHubAPIRepository_CommitVerification(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"sha": "signed", "commit": map[string]any{
+				"message":      "Signed change",
+				"committer":    map[string]any{"date": "2023-01-01T11:00:00Z"},
+				"verification": map[string]any{"verified": true, "reason": "valid"},
+			}},
+			{"sha": "unsigned", "commit": map[string]any{
+				"message":      "Unsigned change",
+				"committer":    map[string]any{"date": "2023-01-01T12:00:00Z"},
+				"verification": map[string]any{"verified": false, "reason": "unsigned"},
+			}},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeComments = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 || len(prs[0].Commits) != 2 {
+		t.Fatalf("Expected 1 pull request with 2 commits, got %+v", prs)
+	}
+	signed, unsigned := prs[0].Commits[0], prs[0].Commits[1]
+	if !signed.Verified || signed.VerificationReason != "valid" {
+		t.Errorf("Expected the signed commit to be verified, got %+v", signed)
+	}
+	if unsigned.Verified || unsigned.VerificationReason != "unsigned" {
+		t.Errorf("Expected the unsigned commit to be unverified, got %+v", unsigned)
+	}
+
+	content, err := (&MarkdownFormatter{Options: FormatterOptions{ShowVerification: true}}).Format(&ActivityReport{
+		TimeRange:    testTimeRange(),
+		Repositories: []Repository{{Name: "repo1", Organization: "testorg", PullRequests: prs}},
+	})
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	for _, expected := range []string{"Signed change _(verified)_", "Unsigned change _(unverified: unsigned)_"} {
+		if !strings.Contains(content.Content, expected) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", expected, content.Content)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_SkipsCommentsWithoutReviews(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, reviewedSearchResult(1, 2))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "state": "APPROVED", "submitted_at": "2023-01-01T12:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2/reviews", func(w http.ResponseWriter, r *http.Request) {
+		// Only a review outside of the time range
+		writeJSON(t, w, []map[string]any{
+			{"id": 2, "user": map[string]any{"login": "testuser"}, "state": "APPROVED", "submitted_at": "2022-12-01T12:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2/comments", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no comment fetch for a reviewed PR without in-range reviews")
+		writeJSON(t, w, []map[string]any{})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeAuthored = false
+	options.IncludeCommits = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+	if len(prs[0].Reviews) != 1 || len(prs[1].Reviews) != 0 {
+		t.Errorf("Expected 1 and 0 in-range reviews, got %d and %d", len(prs[0].Reviews), len(prs[1].Reviews))
+	}
+}
+
+func TestFilterByTitle(t *testing.T) {
+	prs := []PullRequest{
+		{Number: 1, Title: "[PROJ-1] Add login"},
+		{Number: 2, Title: "[OTHER-2] Fix build"},
+		{Number: 3, Title: "[PROJ-x] Typo"},
+		{Number: 4, Title: "Bump dependencies"},
+	}
+
+	testCases := []struct {
+		name     string
+		prefix   string
+		pattern  *regexp.Regexp
+		expected []int
+	}{
+		{name: "No filter", expected: []int{1, 2, 3, 4}},
+		{name: "Prefix", prefix: "[PROJ-", expected: []int{1, 3}},
+		{name: "Pattern", pattern: regexp.MustCompile(`^\[PROJ-\d+\]`), expected: []int{1}},
+		{name: "Prefix and pattern", prefix: "[OTHER-", pattern: regexp.MustCompile(`^\[PROJ-`), expected: []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			numbers := []int{}
+			for _, pr := range filterByTitle(prs, tc.prefix, tc.pattern) {
+				numbers = append(numbers, pr.Number)
+			}
+			if !slices.Equal(numbers, tc.expected) {
+				t.Errorf("Expected PRs %v, got %v", tc.expected, numbers)
+			}
+		})
+	}
+}
+
+func TestBuildPullRequestQuery_Milestone(t *testing.T) {
+	options := DefaultQueryOptions()
+	options.Milestone = "Sprint 42"
+
+	query := buildPullRequestQuery("author:testuser", "testorg", "repo1", testTimeRange(), options)
+
+	expected := "is:pr author:testuser repo:testorg/repo1 base:master milestone:\"Sprint 42\" updated:2023-01-01..2023-01-02"
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+
+	options.Milestone = ""
+	query = buildPullRequestQuery("author:testuser", "testorg", "repo1", testTimeRange(), options)
+	if strings.Contains(query, "milestone:") {
+		t.Errorf("Expected no milestone qualifier, got %q", query)
+	}
+}
+
+func TestBuildPullRequestQuery_AnyBaseBranch(t *testing.T) {
+	for _, baseBranch := range []string{AnyBaseBranch, ""} {
+		options := DefaultQueryOptions()
+		options.BaseBranch = baseBranch
+
+		query := buildPullRequestQuery("author:testuser", "testorg", "repo1", testTimeRange(), options)
+
+		expected := "is:pr author:testuser repo:testorg/repo1 updated:2023-01-01..2023-01-02"
+		if query != expected {
+			t.Errorf("Expected query %q for base branch %q, got %q", expected, baseBranch, query)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_Milestone(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	var queries []string
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		result := searchResult(1)
+		result["items"].([]map[string]any)[0]["milestone"] = map[string]any{"title": "Sprint 42"}
+		writeJSON(t, w, result)
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeReviewed = false
+	options.Milestone = "Sprint 42"
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(queries) != 1 || !strings.Contains(queries[0], `milestone:"Sprint 42"`) {
+		t.Errorf("Expected quoted milestone qualifier in query, got %v", queries)
+	}
+	if len(prs) != 1 || prs[0].Milestone != "Sprint 42" {
+		t.Errorf("Expected PR milestone to be populated, got %+v", prs)
+	}
+}
+
+func TestGitHubAPIRepository_SearchPullRequests(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	var queries []string
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		result := reviewedSearchResult(1, 2, 3)
+		items := result["items"].([]map[string]any)
+		items[0]["repository_url"] = "https://api.github.com/repos/testorg/repo1"
+		items[0]["pull_request"] = map[string]any{}
+		items[1]["repository_url"] = "https://api.github.com/repos/otherorg/repo2"
+		items[1]["pull_request"] = map[string]any{}
+		items[1]["user"] = map[string]any{"login": "testuser"}
+		// An issue matched by the query, which is not a pull request
+		items[2]["repository_url"] = "https://api.github.com/repos/testorg/repo1"
+		writeJSON(t, w, result)
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeCommits = false
+	options.IncludeComments = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	repositories, err := repository.SearchPullRequests(context.Background(), "is:pr team-review-requested:testorg/platform", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := "is:pr team-review-requested:testorg/platform updated:2023-01-01..2023-01-02"
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Expected query %q, got %v", expected, queries)
+	}
+
+	if len(repositories) != 2 {
+		t.Fatalf("Expected 2 repositories, got %+v", repositories)
+	}
+	if repositories[0].Organization != "testorg" || repositories[0].Name != "repo1" ||
+		len(repositories[0].PullRequests) != 1 || !repositories[0].PullRequests[0].IsReviewed {
+		t.Errorf("Expected reviewed PR #1 in testorg/repo1, got %+v", repositories[0])
+	}
+	if repositories[1].Organization != "otherorg" || repositories[1].Name != "repo2" ||
+		len(repositories[1].PullRequests) != 1 || !repositories[1].PullRequests[0].IsAuthored {
+		t.Errorf("Expected authored PR #2 in otherorg/repo2, got %+v", repositories[1])
+	}
+}
+
+func TestCustomQueryWithTimeRange_KeepsUpdatedQualifier(t *testing.T) {
+	query := "is:pr author:testuser updated:>=2022-12-01"
+	if result := customQueryWithTimeRange(query, testTimeRange()); result != query {
+		t.Errorf("Expected query to be left alone, got %q", result)
+	}
+}
+
+func TestGitHubAPIRepository_PartialEnrichment(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1, 2))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message": "Server Error"}`))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2/commits", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"sha": "abc", "commit": map[string]any{"message": "Work", "committer": map[string]any{"date": "2023-01-01T12:00:00Z"}}},
+		})
+	})
+	for _, number := range []int{1, 2} {
+		mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d/comments", number), func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, []map[string]any{
+				{"id": number, "user": map[string]any{"login": "testuser"}, "body": "Note", "created_at": "2023-01-01T13:00:00Z"},
+			})
+		})
+	}
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected partial enrichment to succeed, got: %v", err)
+	}
+
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	failed, healthy := prs[0], prs[1]
+	if len(failed.EnrichmentErrors) != 1 {
+		t.Errorf("Expected 1 enrichment error on PR #1, got %v", failed.EnrichmentErrors)
+	}
+	if len(failed.Comments) != 1 {
+		t.Errorf("Expected PR #1 comments to still be enriched, got %d", len(failed.Comments))
+	}
+
+	if len(healthy.EnrichmentErrors) != 0 {
+		t.Errorf("Expected no enrichment errors on PR #2, got %v", healthy.EnrichmentErrors)
+	}
+	if len(healthy.Commits) != 1 || len(healthy.Comments) != 1 {
+		t.Errorf("Expected PR #2 to be fully enriched, got %d commits and %d comments", len(healthy.Commits), len(healthy.Comments))
+	}
+}
+
+func TestGitHubAPIRepository_ReviewDecision(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "alice"}, "state": "APPROVED", "submitted_at": "2023-01-01T11:00:00Z"},
+			{"id": 2, "user": map[string]any{"login": "bob"}, "state": "CHANGES_REQUESTED", "submitted_at": "2023-01-01T12:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeReviewDecision = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 || prs[0].ReviewDecision != ReviewDecisionChangesRequested {
+		t.Errorf("Expected review decision %s, got %+v", ReviewDecisionChangesRequested, prs)
+	}
+}
+
+func TestGitHubAPIRepository_SelfReviews(t *testing.T) {
+	for _, includeSelfReviews := range []bool{false, true} {
+		t.Run(fmt.Sprintf("IncludeSelfReviews=%v", includeSelfReviews), func(t *testing.T) {
+			client, mux := newTestClient(t)
+
+			// Both searches return the user's own PR
+			mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, searchResult(1))
+			})
+			mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, []map[string]any{
+					{"id": 1, "user": map[string]any{"login": "testuser"}, "state": "COMMENTED", "body": "Note to self", "submitted_at": "2023-01-01T12:00:00Z"},
+				})
+			})
+
+			options := DefaultQueryOptions()
+			options.IncludeCommits = false
+			options.IncludeComments = false
+			options.IncludeSelfReviews = includeSelfReviews
+
+			repository := NewGitHubAPIRepository(client, "testuser")
+			prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			if len(prs) != 1 {
+				t.Fatalf("Expected the PR once, got %d", len(prs))
+			}
+			if !prs[0].IsAuthored || prs[0].IsReviewed {
+				t.Errorf("Expected the PR only under authored, got IsAuthored=%v IsReviewed=%v", prs[0].IsAuthored, prs[0].IsReviewed)
+			}
+
+			expectedReviews := 0
+			if includeSelfReviews {
+				expectedReviews = 1
+			}
+			if len(prs[0].Reviews) != expectedReviews {
+				t.Errorf("Expected %d self-reviews, got %d", expectedReviews, len(prs[0].Reviews))
+			}
+		})
+	}
+}
+
+func TestGitHubAPIRepository_PathPrefix(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"sha": "inside", "commit": map[string]any{"message": "Touch api", "committer": map[string]any{"date": "2023-01-01T11:00:00Z"}}},
+			{"sha": "outside", "commit": map[string]any{"message": "Touch web", "committer": map[string]any{"date": "2023-01-01T12:00:00Z"}}},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/commits/inside", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"sha": "inside", "files": []map[string]any{{"filename": "services/api/main.go"}}})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/commits/outside", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"sha": "outside", "files": []map[string]any{{"filename": "services/web/main.go"}}})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "path": "services/api/handler.go", "body": "Inside", "created_at": "2023-01-01T13:00:00Z"},
+			{"id": 2, "user": map[string]any{"login": "testuser"}, "path": "services/web/app.js", "body": "Outside", "created_at": "2023-01-01T13:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.PathPrefix = "services/api/"
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+	if len(prs[0].Commits) != 1 || prs[0].Commits[0].SHA != "inside" {
+		t.Errorf("Expected only the commit touching the prefix, got %+v", prs[0].Commits)
+	}
+	if len(prs[0].Comments) != 1 || prs[0].Comments[0].Body != "Inside" {
+		t.Errorf("Expected only the comment under the prefix, got %+v", prs[0].Comments)
+	}
+}
+
+func TestGitHubAPIRepository_ExcludePaths(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "path": "go.sum", "body": "Lockfile", "created_at": "2023-01-01T13:00:00Z"},
+			{"id": 2, "user": map[string]any{"login": "testuser"}, "path": "main.go", "body": "Code", "created_at": "2023-01-01T13:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.ExcludePaths = []string{"go.sum"}
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+	if len(prs[0].Comments) != 1 || prs[0].Comments[0].Path != "main.go" {
+		t.Errorf("Expected only the comment on main.go, got %+v", prs[0].Comments)
+	}
+}
+
+func TestGitHubAPIRepository_ThreadsStarted(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "path": "main.go", "body": "First root", "created_at": "2023-01-01T13:00:00Z"},
+			{"id": 2, "user": map[string]any{"login": "reviewer"}, "path": "main.go", "body": "Other reply", "in_reply_to_id": 1, "created_at": "2023-01-01T13:10:00Z"},
+			{"id": 3, "user": map[string]any{"login": "testuser"}, "path": "main.go", "body": "Own reply", "in_reply_to_id": 2, "created_at": "2023-01-01T13:20:00Z"},
+			{"id": 4, "user": map[string]any{"login": "testuser"}, "path": "util.go", "body": "Second root", "created_at": "2023-01-01T14:00:00Z"},
+			{"id": 5, "user": map[string]any{"login": "reviewer"}, "path": "util.go", "body": "Someone else's root", "created_at": "2023-01-01T14:10:00Z"},
+			{"id": 6, "user": map[string]any{"login": "testuser"}, "path": "util.go", "body": "Reply to someone else", "in_reply_to_id": 5, "created_at": "2023-01-01T14:20:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeThreadsStarted = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+	if len(prs[0].Comments) != 4 {
+		t.Errorf("Expected the user's 4 comments, got %d", len(prs[0].Comments))
+	}
+	if prs[0].ThreadsStarted != 2 {
+		t.Errorf("Expected 2 threads started, got %d", prs[0].ThreadsStarted)
+	}
+}
+
+func TestMatchesAnyPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		patterns []string
+		expected bool
+	}{
+		{path: "go.sum", patterns: []string{"go.sum"}, expected: true},
+		{path: "tools/go.sum", patterns: []string{"go.sum"}, expected: true},
+		{path: "api/v1/service.pb.go", patterns: []string{"*.pb.go"}, expected: true},
+		{path: "api/v1/service.go", patterns: []string{"*.pb.go"}, expected: false},
+		{path: "vendor/lib.go", patterns: []string{"vendor/*"}, expected: true},
+		{path: "internal/vendor/lib.go", patterns: []string{"vendor/*"}, expected: false},
+		{path: "", patterns: []string{"*"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		if result := matchesAnyPath(tc.path, tc.patterns); result != tc.expected {
+			t.Errorf("Expected matchesAnyPath(%q, %v) to be %v, got %v", tc.path, tc.patterns, tc.expected, result)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_CommentsSince(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		if since := r.URL.Query().Get("since"); since != "2023-01-01T00:00:00Z" {
+			t.Errorf("Expected since to be the range start, got %q", since)
+		}
+
+		// Serve two pages; the second holds a comment past the range end
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(t, w, []map[string]any{
+				{"id": 2, "user": map[string]any{"login": "testuser"}, "body": "Later", "created_at": "2023-01-03T13:00:00Z"},
+			})
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, client.BaseURL.String()+"repos/testorg/repo1/pulls/1/comments"))
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "body": "Note", "created_at": "2023-01-01T13:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 || len(prs[0].Comments) != 1 || prs[0].Comments[0].ID != 1 {
+		t.Errorf("Expected only the in-range comment, got %+v", prs)
+	}
+}
+
+func TestGitHubAPIRepository_UnresolvedThreads(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode GraphQL request: %v", err)
+		}
+		if body.Variables["owner"] != "testorg" || body.Variables["name"] != "repo1" || body.Variables["number"] != float64(1) {
+			t.Errorf("Unexpected GraphQL variables: %v", body.Variables)
+		}
+
+		writeJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"repository": map[string]any{
+					"pullRequest": map[string]any{
+						"reviewThreads": map[string]any{
+							"nodes":    []map[string]any{{"isResolved": true}, {"isResolved": false}},
+							"pageInfo": map[string]any{"hasNextPage": false},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeUnresolvedThreads = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+	if prs[0].UnresolvedThreads != 1 {
+		t.Errorf("Expected 1 unresolved thread, got %d", prs[0].UnresolvedThreads)
+	}
+	if len(prs[0].EnrichmentErrors) != 0 {
+		t.Errorf("Expected no enrichment errors, got %v", prs[0].EnrichmentErrors)
+	}
+}
+
+func TestGitHubAPIRepository_ProjectStatus(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1, 2))
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode GraphQL request: %v", err)
+		}
+
+		// PR #1 is on a board, PR #2 on none
+		var nodes []map[string]any
+		if body.Variables["number"] == float64(1) {
+			nodes = []map[string]any{
+				{"fieldValueByName": nil},
+				{"fieldValueByName": map[string]any{"name": "In Review"}},
+			}
+		}
+		writeJSON(t, w, map[string]any{
+			"data": map[string]any{
+				"repository": map[string]any{
+					"pullRequest": map[string]any{
+						"projectItems": map[string]any{"nodes": nodes},
+					},
+				},
+			},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeProjectStatus = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	statuses := map[int]string{}
+	for _, pr := range prs {
+		statuses[pr.Number] = pr.ProjectStatus
+		if len(pr.EnrichmentErrors) != 0 {
+			t.Errorf("Expected no enrichment errors, got %v", pr.EnrichmentErrors)
+		}
+	}
+	if statuses[1] != "In Review" || statuses[2] != "" {
+		t.Errorf("Expected PR #1 In Review and PR #2 without status, got %v", statuses)
+	}
+
+	report := &ActivityReport{
+		TimeRange:    testTimeRange(),
+		User:         User{Username: "testuser"},
+		Repositories: []Repository{{Name: "repo1", Organization: "testorg", PullRequests: prs}},
+	}
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if strings.Count(content.Content, "**Status:**") != 1 || !strings.Contains(content.Content, "**Status:** In Review") {
+		t.Errorf("Expected a single status line for PR #1, got:\n%s", content.Content)
+	}
+}
+
+func TestGitHubAPIRepository_ClosedByUser(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		if !strings.Contains(query, "is:unmerged") {
+			writeJSON(t, w, searchResult())
+			return
+		}
+		if !strings.Contains(query, "-author:testuser") || !strings.Contains(query, "closed:2023-01-01..2023-01-02") {
+			t.Errorf("Unexpected closed search query: %s", query)
+		}
+		writeJSON(t, w, reviewedSearchResult(1, 2))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/issues/1/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"event": "labeled", "actor": map[string]any{"login": "testuser"}, "created_at": "2023-01-01T10:00:00Z"},
+			{"event": "closed", "actor": map[string]any{"login": "testuser"}, "created_at": "2023-01-01T11:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/issues/2/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"event": "closed", "actor": map[string]any{"login": "otheruser"}, "created_at": "2023-01-01T11:00:00Z"},
+		})
+	})
+	for _, number := range []int{1, 2} {
+		mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d/commits", number), func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, []map[string]any{})
+		})
+	}
+
+	options := DefaultQueryOptions()
+	options.IncludeClosedByUser = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected only the PR closed by the user, got %+v", prs)
+	}
+	if prs[0].Number != 1 || !prs[0].IsClosedByUser || prs[0].IsAuthored || prs[0].IsReviewed {
+		t.Errorf("Expected PR #1 flagged as closed by the user only, got %+v", prs[0])
+	}
+}
+
+func TestGitHubAPIRepository_OnlyRequestedReviews(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "reviewed-by:") {
+			writeJSON(t, w, reviewedSearchResult(1, 2))
+			return
+		}
+		writeJSON(t, w, searchResult())
+	})
+	// PR #1 requested the user's review; PR #2 was a drive-by review
+	mux.HandleFunc("/repos/testorg/repo1/issues/1/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"event": "review_requested", "actor": map[string]any{"login": "otheruser"}, "requested_reviewer": map[string]any{"login": "testuser"}},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/issues/2/events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"event": "review_requested", "actor": map[string]any{"login": "otheruser"}, "requested_reviewer": map[string]any{"login": "thirduser"}},
+		})
+	})
+	for _, number := range []int{1, 2} {
+		mux.HandleFunc(fmt.Sprintf("/repos/testorg/repo1/pulls/%d/reviews", number), func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, []map[string]any{})
+		})
+	}
+
+	options := DefaultQueryOptions()
+	options.OnlyRequestedReviews = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 || prs[0].Number != 1 {
+		t.Fatalf("Expected only the PR the user was requested on, got %+v", prs)
+	}
+}
+
+func TestGitHubAPIRepository_OnlyReviewedInRange(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "reviewed-by:") {
+			writeJSON(t, w, reviewedSearchResult(1, 2))
+			return
+		}
+		writeJSON(t, w, searchResult())
+	})
+	// PR #1 was reviewed in range; PR #2 only has a review from before it
+	var reviewFetches int
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		reviewFetches++
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "state": "APPROVED", "submitted_at": "2023-01-01T12:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2/reviews", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{
+			{"id": 2, "user": map[string]any{"login": "testuser"}, "state": "APPROVED", "submitted_at": "2022-12-01T12:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, []map[string]any{})
+	})
+	var commentsFetched bool
+	mux.HandleFunc("/repos/testorg/repo1/pulls/2/comments", func(w http.ResponseWriter, r *http.Request) {
+		commentsFetched = true
+		writeJSON(t, w, []map[string]any{})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeCommits = false
+	options.OnlyReviewedInRange = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 || prs[0].Number != 1 {
+		t.Fatalf("Expected only the PR reviewed in range, got %+v", prs)
+	}
+	if len(prs[0].Reviews) != 1 || reviewFetches != 1 {
+		t.Errorf("Expected the review reused from the search, got %d fetches and %+v", reviewFetches, prs[0].Reviews)
+	}
+	if commentsFetched {
+		t.Errorf("Expected no comment fetch for the PR without reviews in range")
+	}
+}
+
+func TestGitHubAPIRepository_ChangedFiles(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/files", func(w http.ResponseWriter, r *http.Request) {
+		files := make([]map[string]any, 0, 5)
+		for _, name := range []string{"auth/login.go", "auth/token.go", "auth/session.go", "README.md", "go.mod"} {
+			files = append(files, map[string]any{"filename": name})
+		}
+		writeJSON(t, w, files)
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.IncludeFiles = true
+	options.MaxFiles = 3
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := []string{"auth/login.go", "auth/token.go", "auth/session.go"}
+	if len(prs) != 1 || !slices.Equal(prs[0].ChangedFilePaths, expected) {
+		t.Fatalf("Expected changed files %v, got %+v", expected, prs)
+	}
+
+	for _, formatter := range []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()} {
+		report := createTestActivityReport()
+		report.Repositories[0].PullRequests = prs
+
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+		if !strings.Contains(content.Content, "Changed files (3)") || !strings.Contains(content.Content, "auth/session.go") {
+			t.Errorf("Expected the capped file list in %s output, got:\n%s", formatter.Name(), content.Content)
+		}
+		if strings.Contains(content.Content, "README.md") {
+			t.Errorf("Expected files past the cap to be left out of %s output", formatter.Name())
+		}
+	}
+}
+
+func TestGitHubAPIRepository_ConcurrentEnrichment(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	// Each enrichment call waits until all three are in flight, so the test
+	// only completes promptly if they run concurrently
+	var mu sync.Mutex
+	arrived := 0
+	allArrived := make(chan struct{})
+	barrier := func(name string) {
+		mu.Lock()
+		arrived++
+		if arrived == 3 {
+			close(allArrived)
+		}
+		mu.Unlock()
+
+		select {
+		case <-allArrived:
+		case <-time.After(2 * time.Second):
+			t.Errorf("Timed out waiting for concurrent calls in %s", name)
+		}
+	}
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		barrier("commits")
+		writeJSON(t, w, []map[string]any{
+			{"sha": "abc", "commit": map[string]any{"message": "Work", "committer": map[string]any{"date": "2023-01-01T12:00:00Z"}}},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		barrier("reviews")
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "state": "COMMENTED", "submitted_at": "2023-01-01T12:00:00Z"},
+		})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		barrier("comments")
+		writeJSON(t, w, []map[string]any{
+			{"id": 1, "user": map[string]any{"login": "testuser"}, "body": "Note", "created_at": "2023-01-01T13:00:00Z"},
+		})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeSelfReviews = true
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 pull request, got %d", len(prs))
+	}
+	pr := prs[0]
+	if len(pr.Commits) != 1 || len(pr.Reviews) != 1 || len(pr.Comments) != 1 {
+		t.Errorf("Expected commits, reviews, and comments to be populated, got %d, %d, and %d", len(pr.Commits), len(pr.Reviews), len(pr.Comments))
+	}
+}
+
+func TestGitHubAPIRepository_SharedConcurrencyLimit(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	// Record the most enrichment calls in flight at once, across both
+	// repositories
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	track := func(w http.ResponseWriter, v any) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+		writeJSON(t, w, v)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	for _, repo := range []string{"repo1", "repo2"} {
+		mux.HandleFunc("/repos/testorg/"+repo+"/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+			track(w, []map[string]any{})
+		})
+		mux.HandleFunc("/repos/testorg/"+repo+"/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+			track(w, []map[string]any{})
+		})
+		mux.HandleFunc("/repos/testorg/"+repo+"/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+			track(w, []map[string]any{})
+		})
+	}
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeSelfReviews = true
+	options.MaxConcurrency = 2
+
+	repository := NewGitHubAPIRepository(client, "testuser")
+	ctx := withConcurrencyLimiter(context.Background(), options.MaxConcurrency)
+	var wg sync.WaitGroup
+	for _, repo := range []string{"repo1", "repo2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repository.GetPullRequests(ctx, "testorg", repo, testTimeRange(), options); err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > options.MaxConcurrency {
+		t.Errorf("Expected at most %d enrichment calls in flight across repositories, got %d", options.MaxConcurrency, maxInFlight)
+	}
+}
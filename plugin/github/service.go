@@ -1,7 +1,11 @@
 package github
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	plug "github.com/iures/daivplug"
@@ -11,6 +15,10 @@ import (
 type ActivityService struct {
 	repository GitHubRepository
 	config     *GitHubConfig
+	
+	// The authenticated user, fetched once and reused by later reports
+	userMu sync.Mutex
+	user   *User
 }
 
 // NewActivityService creates a new activity service
@@ -24,15 +32,25 @@ func NewActivityService(repository GitHubRepository, config *GitHubConfig) *Acti
 // GetActivityReport retrieves and processes GitHub activity data for the given time range
 func (s *ActivityService) GetActivityReport(pluginTimeRange plug.TimeRange) (*ActivityReport, error) {
 	// Convert plugin.TimeRange to our domain TimeRange
-	timeRange := TimeRange{
-		Start: pluginTimeRange.Start,
-		End:   pluginTimeRange.End,
+	timeRange, err := FromPluginTimeRange(pluginTimeRange)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get the current user
-	user, err := s.repository.GetUser()
+	// Get the current user. Enrichment shares one concurrency limit across
+	// the report's repositories.
+	ctx := withConcurrencyLimiter(context.Background(), s.config.QueryOptions.MaxConcurrency)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, err
+	}
+
+	// Stop working once the time budget runs out, whichever way the report
+	// is built
+	if s.config.TotalBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.TotalBudget)
+		defer cancel()
 	}
 
 	// Create the activity report
@@ -42,32 +60,195 @@ func (s *ActivityService) GetActivityReport(pluginTimeRange plug.TimeRange) (*Ac
 		Repositories: make([]Repository, 0, len(s.config.Repositories)),
 	}
 
+	// A single pull request is reported on its own
+	if s.config.PullRequestURL != "" {
+		repository, err := s.processPullRequestURL(ctx, timeRange)
+		if err != nil {
+			return nil, s.budgetExceededOr(ctx, err)
+		}
+		report.Repositories = append(report.Repositories, repository)
+		return report, nil
+	}
+
+	// A custom query spans its own repositories, so it bypasses the
+	// per-repository processing
+	if s.config.CustomQuery != "" {
+		repositories, err := s.processCustomQuery(ctx, timeRange)
+		if err != nil {
+			return nil, s.budgetExceededOr(ctx, err)
+		}
+		report.Repositories = repositories
+		return report, nil
+	}
+
+	// Resume from the checkpoint if one is configured
+	repoNames := dedupeRepositories(s.config.Organization, s.config.Repositories)
+	progress := &progressTracker{fn: s.config.OnProgress, total: len(repoNames)}
+	var cp *checkpoint
+	if s.config.CheckpointFile != "" {
+		cp, err = s.openCheckpoint(timeRange)
+		if err != nil {
+			return nil, err
+		}
+		report.Repositories = append(report.Repositories, cp.completed()...)
+
+		pending := make([]string, 0, len(repoNames))
+		for _, repoName := range repoNames {
+			if !cp.isCompleted(repoName) {
+				pending = append(pending, repoName)
+			}
+		}
+		repoNames = pending
+		progress.done = progress.total - len(pending)
+	}
+
 	// Process repositories concurrently
-	if len(s.config.Repositories) > 1 {
-		report.Repositories = s.processRepositoriesConcurrently(timeRange)
+	var repositories []Repository
+	var skipped []SkippedRepo
+	if len(repoNames) > 1 {
+		repositories, skipped, err = s.processRepositoriesConcurrently(ctx, repoNames, timeRange, cp, progress)
 	} else {
-		report.Repositories = s.processRepositoriesSequentially(timeRange)
+		repositories, skipped, err = s.processRepositoriesSequentially(ctx, repoNames, timeRange, cp, progress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Repositories = append(report.Repositories, repositories...)
+	report.SkippedRepositories = skipped
+
+	// A fully completed report no longer needs its checkpoint. Archived
+	// repositories are skipped on every run, so they count as completed.
+	if cp != nil && len(repositories)+countArchived(skipped) == len(repoNames) {
+		if err := cp.remove(); err != nil {
+			Logger.Printf("Error removing checkpoint: %v\n", err)
+		}
 	}
 
 	return report, nil
 }
 
+// countArchived counts the repositories skipped for being archived or
+// disabled
+func countArchived(skipped []SkippedRepo) int {
+	count := 0
+	for _, repo := range skipped {
+		if repo.Kind == ErrorKindArchived {
+			count++
+		}
+	}
+	return count
+}
+
+// currentUser returns the authenticated user, fetching it on first use so
+// reports run back to back, such as the buckets of a trend, share one lookup
+func (s *ActivityService) currentUser(ctx context.Context) (*User, error) {
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+
+	if s.user == nil {
+		user, err := s.repository.GetUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		s.user = user
+	}
+	return s.user, nil
+}
+
+// openCheckpoint loads the configured checkpoint when resuming, or starts a
+// fresh one otherwise
+func (s *ActivityService) openCheckpoint(timeRange TimeRange) (*checkpoint, error) {
+	if !s.config.Resume {
+		return &checkpoint{path: s.config.CheckpointFile, data: checkpointData{TimeRange: timeRange}}, nil
+	}
+	return loadCheckpoint(s.config.CheckpointFile, timeRange)
+}
+
+// recordCompleted adds a completed repository to the checkpoint, if any
+func recordCompleted(cp *checkpoint, repo Repository) {
+	if cp == nil {
+		return
+	}
+	if err := cp.record(repo); err != nil {
+		Logger.Printf("Error writing checkpoint for repository %s: %v\n", repo.Name, err)
+	}
+}
+
+// progressTracker reports completed repositories to the OnProgress callback.
+// It is only used from the goroutine collecting results, so the callback is
+// never invoked concurrently.
+type progressTracker struct {
+	fn    func(done, total int)
+	done  int
+	total int
+}
+
+// complete records one more completed repository and reports it
+func (p *progressTracker) complete() {
+	p.done++
+	if p.fn != nil {
+		p.fn(p.done, p.total)
+	}
+}
+
+// dedupeRepositories removes repeated repositories, comparing names
+// case-insensitively and treating "org/repo" for the configured organization
+// as the bare repository name. The first occurrence's order is preserved.
+func dedupeRepositories(org string, repoNames []string) []string {
+	seen := make(map[string]bool, len(repoNames))
+	deduped := make([]string, 0, len(repoNames))
+	for _, repoName := range repoNames {
+		if owner, name, found := strings.Cut(repoName, "/"); found && strings.EqualFold(owner, org) {
+			repoName = name
+		}
+
+		key := strings.ToLower(repoName)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, repoName)
+	}
+	return deduped
+}
+
+// skipRepository logs a repository that failed to process and describes it
+// for the report
+func skipRepository(repoName string, err error) SkippedRepo {
+	Logger.Printf("Error processing repository %s: %v\n", repoName, err)
+	return SkippedRepo{
+		Name:   repoName,
+		Reason: err.Error(),
+		Kind:   ErrorKind(err),
+	}
+}
+
+// repositoryResult is the outcome of processing a single repository
+type repositoryResult struct {
+	name string
+	repo Repository
+	err  error
+}
+
 // processRepositoriesConcurrently processes repositories in parallel
-func (s *ActivityService) processRepositoriesConcurrently(timeRange TimeRange) []Repository {
+func (s *ActivityService) processRepositoriesConcurrently(ctx context.Context, repoNames []string, timeRange TimeRange, cp *checkpoint, progress *progressTracker) ([]Repository, []SkippedRepo, error) {
+	// Canceled on return, so after a fast-fail the other repositories stop
+	// making API calls once the report has failed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	var wg sync.WaitGroup
-	resultChan := make(chan Repository, len(s.config.Repositories))
+	resultChan := make(chan repositoryResult, len(repoNames))
 
-	for _, repoName := range s.config.Repositories {
+	for _, repoName := range repoNames {
 		wg.Add(1)
 		go func(repoName string) {
 			defer wg.Done()
-			repo, err := s.processRepository(s.config.Organization, repoName, timeRange)
-			if err != nil {
-				// Log error but continue with other repositories
-				fmt.Printf("Error processing repository %s: %v\n", repoName, err)
-				return
+			repo, err := s.processRepositoryWithin(ctx, repoName, timeRange)
+			if err == nil {
+				recordCompleted(cp, repo)
 			}
-			resultChan <- repo
+			resultChan <- repositoryResult{name: repoName, repo: repo, err: err}
 		}(repoName)
 	}
 
@@ -77,49 +258,447 @@ func (s *ActivityService) processRepositoriesConcurrently(timeRange TimeRange) [
 		close(resultChan)
 	}()
 
-	// Collect results from the channel
-	repositories := make([]Repository, 0, len(s.config.Repositories))
-	for repo := range resultChan {
-		repositories = append(repositories, repo)
+	// Collect results from the channel. The channel is buffered, so workers
+	// still running after a fast-fail return can finish without blocking.
+	repositories := make([]Repository, 0, len(repoNames))
+	var skipped []SkippedRepo
+	for result := range resultChan {
+		progress.complete()
+		if result.err != nil {
+			if s.abortsReport(result.err) {
+				return nil, nil, result.err
+			}
+			// Record the error but continue with other repositories
+			skipped = append(skipped, skipRepository(result.name, result.err))
+			continue
+		}
+		repositories = append(repositories, result.repo)
 	}
 
-	return repositories
+	// Results arrive in completion order; report skips in configured order
+	slices.SortStableFunc(skipped, func(a, b SkippedRepo) int {
+		return slices.Index(repoNames, a.Name) - slices.Index(repoNames, b.Name)
+	})
+
+	return repositories, skipped, nil
 }
 
 // processRepositoriesSequentially processes repositories sequentially
-func (s *ActivityService) processRepositoriesSequentially(timeRange TimeRange) []Repository {
-	repositories := make([]Repository, 0, len(s.config.Repositories))
+func (s *ActivityService) processRepositoriesSequentially(ctx context.Context, repoNames []string, timeRange TimeRange, cp *checkpoint, progress *progressTracker) ([]Repository, []SkippedRepo, error) {
+	repositories := make([]Repository, 0, len(repoNames))
+	var skipped []SkippedRepo
 
-	for _, repoName := range s.config.Repositories {
-		repo, err := s.processRepository(s.config.Organization, repoName, timeRange)
+	for _, repoName := range repoNames {
+		repo, err := s.processRepositoryWithin(ctx, repoName, timeRange)
+		progress.complete()
 		if err != nil {
-			// Log error but continue with other repositories
-			fmt.Printf("Error processing repository %s: %v\n", repoName, err)
+			if s.abortsReport(err) {
+				return nil, nil, err
+			}
+			// Record the error but continue with other repositories
+			skipped = append(skipped, skipRepository(repoName, err))
 			continue
 		}
+		recordCompleted(cp, repo)
 		repositories = append(repositories, repo)
 	}
 
-	return repositories
+	return repositories, skipped, nil
+}
+
+// abortsReport reports whether a repository error fails the whole report.
+// Running out of time budget and skipping archived repositories are
+// expected outcomes, so they never abort. A token that cannot search fails
+// every repository the same way, so it always aborts with a single error.
+func (s *ActivityService) abortsReport(err error) bool {
+	if errors.Is(err, ErrSearchForbidden) {
+		return true
+	}
+	return s.config.FastFail && !errors.Is(err, ErrBudgetExceeded) && !errors.Is(err, ErrRepoArchived)
+}
+
+// processRepositoryWithin processes a repository unless ctx is done first.
+// The repository's API calls share ctx, so a repository abandoned at the
+// deadline stops making requests instead of spending quota after the report
+// is returned.
+func (s *ActivityService) processRepositoryWithin(ctx context.Context, repoName string, timeRange TimeRange) (Repository, error) {
+	if ctx.Err() != nil {
+		return Repository{}, s.budgetExceeded()
+	}
+
+	done := make(chan repositoryResult, 1)
+	go func() {
+		repo, err := s.processRepository(ctx, s.config.Organization, repoName, timeRange)
+		done <- repositoryResult{name: repoName, repo: repo, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.repo, result.err
+	case <-ctx.Done():
+		return Repository{}, s.budgetExceeded()
+	}
+}
+
+// budgetExceeded describes running out of the configured time budget
+func (s *ActivityService) budgetExceeded() error {
+	return fmt.Errorf("%w after %s", ErrBudgetExceeded, s.config.TotalBudget)
+}
+
+// budgetExceededOr returns ErrBudgetExceeded in place of err when the time
+// budget ran out, as err then only reports the canceled request
+func (s *ActivityService) budgetExceededOr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return s.budgetExceeded()
+	}
+	return err
 }
 
 // processRepository processes a single repository
-func (s *ActivityService) processRepository(org string, repoName string, timeRange TimeRange) (Repository, error) {
+func (s *ActivityService) processRepository(ctx context.Context, org string, repoName string, timeRange TimeRange) (Repository, error) {
 	repository := Repository{
 		Name:         repoName,
 		Organization: org,
 	}
 
+	// Archived repositories have no new activity, so skipping them saves
+	// the searches
+	if s.config.ArchivedRepos != "" || s.config.CanonicalRepoNames {
+		metadata, err := s.repository.GetRepositoryMetadata(ctx, org, repoName)
+		if err != nil {
+			return repository, err
+		}
+		if s.config.CanonicalRepoNames && metadata.Name != "" {
+			repoName = metadata.Name
+			repository.Name = repoName
+		}
+		if (metadata.Archived || metadata.Disabled) && s.config.ArchivedRepos == ArchivedReposSkip {
+			return repository, fmt.Errorf("%w: %s/%s", ErrRepoArchived, org, repoName)
+		}
+		if s.config.ArchivedRepos != "" {
+			repository.Archived = metadata.Archived
+			repository.Disabled = metadata.Disabled
+		}
+	}
+
 	// Get pull requests for the repository
-	pullRequests, err := s.repository.GetPullRequests(org, repoName, timeRange, s.config.QueryOptions)
+	pullRequests, err := s.repository.GetPullRequests(ctx, org, repoName, timeRange, s.config.QueryOptions)
 	if err != nil {
 		return repository, fmt.Errorf("failed to get pull requests for %s/%s: %w", org, repoName, err)
 	}
 
+	pullRequests, err = s.processPullRequests(org, repoName, pullRequests, timeRange)
+	if err != nil {
+		return repository, err
+	}
+
 	// Only include repositories with activity
 	if len(pullRequests) > 0 {
 		repository.PullRequests = pullRequests
 	}
 
+	if s.config.QueryOptions.IncludeDirectCommits {
+		directCommits, err := s.repository.GetDirectCommits(ctx, org, repoName, timeRange, s.config.QueryOptions)
+		if err != nil {
+			return repository, fmt.Errorf("failed to get direct commits for %s/%s: %w", org, repoName, err)
+		}
+		if !s.config.WorkingHours.IsZero() {
+			directCommits = applyWorkingHoursToCommits(directCommits, s.config.WorkingHours)
+		}
+		if len(directCommits) > 0 {
+			repository.DirectCommits = directCommits
+		}
+	}
+
+	if s.config.QueryOptions.IncludeAssignedIssues {
+		issues, err := s.repository.GetAssignedIssues(ctx, org, repoName, timeRange, s.config.QueryOptions)
+		if err != nil {
+			return repository, fmt.Errorf("failed to get assigned issues for %s/%s: %w", org, repoName, err)
+		}
+		if len(issues) > 0 {
+			repository.AssignedIssues = issues
+		}
+	}
+
 	return repository, nil
 } 
+
+// processCustomQuery runs the configured custom query and processes the
+// pull requests of each repository it matched
+func (s *ActivityService) processCustomQuery(ctx context.Context, timeRange TimeRange) ([]Repository, error) {
+	matched, err := s.repository.SearchPullRequests(ctx, s.config.CustomQuery, timeRange, s.config.QueryOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run custom query: %w", err)
+	}
+
+	repositories := make([]Repository, 0, len(matched))
+	for _, repo := range matched {
+		pullRequests, err := s.processPullRequests(repo.Organization, repo.Name, repo.PullRequests, timeRange)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only include repositories with activity
+		if len(pullRequests) > 0 {
+			repo.PullRequests = pullRequests
+			repositories = append(repositories, repo)
+		}
+	}
+
+	return repositories, nil
+}
+
+// processPullRequestURL fetches the configured pull request with its full
+// activity. It is not filtered by activity, since it was asked for by name.
+func (s *ActivityService) processPullRequestURL(ctx context.Context, timeRange TimeRange) (Repository, error) {
+	org, repoName, number, err := ParsePullRequestURL(s.config.PullRequestURL)
+	if err != nil {
+		return Repository{}, err
+	}
+
+	pr, err := s.repository.GetPullRequest(ctx, org, repoName, number, timeRange, s.config.QueryOptions)
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to get PR #%d in %s/%s: %w", number, org, repoName, err)
+	}
+	if err := enrichmentError(pr); s.config.FastFail && err != nil {
+		return Repository{}, fmt.Errorf("failed to enrich PR #%d in %s/%s: %w", number, org, repoName, err)
+	}
+
+	if !s.config.WorkingHours.IsZero() {
+		applyWorkingHoursToPullRequest(&pr, s.config.WorkingHours)
+	}
+	if s.config.PostMerge != "" {
+		applyPostMerge(&pr, s.config.PostMerge)
+	}
+	pr.StateTransitions = stateTransitions(pr, timeRange)
+	if s.config.Explain {
+		pr.InclusionReason = inclusionReason(pr)
+	}
+
+	return Repository{
+		Name:         repoName,
+		Organization: org,
+		PullRequests: []PullRequest{pr},
+	}, nil
+}
+
+// processPullRequests checks the enrichment of a repository's pull requests,
+// applies working hours and post-merge treatment to their activity, derives
+// their state transitions, keeps those with reportable activity, optionally
+// only the open ones or those of a minimum size, and explains their
+// inclusion when asked to
+func (s *ActivityService) processPullRequests(org string, repoName string, pullRequests []PullRequest, timeRange TimeRange) ([]PullRequest, error) {
+	// Partial enrichment is tolerated unless fast-fail is on
+	if s.config.FastFail {
+		for _, pr := range pullRequests {
+			if err := enrichmentError(pr); err != nil {
+				return nil, fmt.Errorf("failed to enrich PR #%d in %s/%s: %w", pr.Number, org, repoName, err)
+			}
+		}
+	}
+
+	if !s.config.WorkingHours.IsZero() {
+		for i := range pullRequests {
+			applyWorkingHoursToPullRequest(&pullRequests[i], s.config.WorkingHours)
+		}
+	}
+
+	if s.config.PostMerge != "" {
+		for i := range pullRequests {
+			applyPostMerge(&pullRequests[i], s.config.PostMerge)
+		}
+	}
+
+	for i := range pullRequests {
+		pullRequests[i].StateTransitions = stateTransitions(pullRequests[i], timeRange)
+	}
+
+	pullRequests = s.filterByActivity(pullRequests, timeRange)
+	if s.config.OnlyOpen {
+		pullRequests = openPullRequests(pullRequests)
+	}
+	if s.config.QueryOptions.MinSizeCategory != "" {
+		pullRequests = filterBySize(pullRequests, s.config.QueryOptions.MinSizeCategory)
+	}
+
+	if s.config.Explain {
+		for i := range pullRequests {
+			pullRequests[i].InclusionReason = inclusionReason(pullRequests[i])
+		}
+	}
+
+	return pullRequests, nil
+}
+
+// enrichmentError returns the first error enriching the pull request, or nil.
+// Pull requests not enriched by GitHubAPIRepository only carry the message.
+func enrichmentError(pr PullRequest) error {
+	if pr.enrichmentErr != nil {
+		return pr.enrichmentErr
+	}
+	if len(pr.EnrichmentErrors) > 0 {
+		return errors.New(pr.EnrichmentErrors[0])
+	}
+	return nil
+}
+
+// filterByActivity keeps the pull requests that have reportable activity
+// according to the configured criteria for their category
+func (s *ActivityService) filterByActivity(pullRequests []PullRequest, timeRange TimeRange) []PullRequest {
+	if s.config.AuthoredActivity.IsZero() && s.config.ReviewedActivity.IsZero() {
+		return pullRequests
+	}
+
+	filtered := make([]PullRequest, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		// Closing a pull request and changing its metadata are themselves
+		// reportable activity
+		if pr.IsClosedByUser || len(pr.MetadataActions) > 0 ||
+			(pr.IsAuthored && s.config.AuthoredActivity.Matches(pr, timeRange)) ||
+			(pr.IsReviewed && s.config.ReviewedActivity.Matches(pr, timeRange)) {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
+
+// openPullRequests keeps the pull requests that are still open
+func openPullRequests(pullRequests []PullRequest) []PullRequest {
+	open := make([]PullRequest, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		if pr.State == "open" {
+			open = append(open, pr)
+		}
+	}
+	return open
+}
+
+// GetActivityReportMulti retrieves activity for several time ranges and merges
+// it into a single report. Pull requests active in more than one range are
+// listed once, and each commit, review, and comment is labeled with the range
+// it belongs to.
+func (s *ActivityService) GetActivityReportMulti(pluginTimeRanges []plug.TimeRange) (*ActivityReport, error) {
+	if len(pluginTimeRanges) == 0 {
+		return nil, fmt.Errorf("at least one time range is required")
+	}
+
+	var merged *ActivityReport
+	for _, pluginTimeRange := range pluginTimeRanges {
+		report, err := s.GetActivityReport(pluginTimeRange)
+		if err != nil {
+			return nil, err
+		}
+
+		labelActivity(report, report.TimeRange.Label())
+
+		if merged == nil {
+			merged = report
+			merged.Ranges = []TimeRange{report.TimeRange}
+			continue
+		}
+		mergeActivityReports(merged, report)
+	}
+
+	return merged, nil
+}
+
+// labelActivity tags every commit, review, and comment with the range label
+func labelActivity(report *ActivityReport, label string) {
+	for i := range report.Repositories {
+		for j := range report.Repositories[i].PullRequests {
+			pr := &report.Repositories[i].PullRequests[j]
+			for k := range pr.Commits {
+				pr.Commits[k].Range = label
+			}
+			for k := range pr.Reviews {
+				pr.Reviews[k].Range = label
+			}
+			for k := range pr.Comments {
+				pr.Comments[k].Range = label
+			}
+			for k := range pr.MetadataActions {
+				pr.MetadataActions[k].Range = label
+			}
+		}
+	}
+}
+
+// mergeActivityReports merges src into dst, widening the overall time range
+// and deduplicating repositories, pull requests, and their activity
+func mergeActivityReports(dst *ActivityReport, src *ActivityReport) {
+	dst.Ranges = append(dst.Ranges, src.TimeRange)
+	for _, skipped := range src.SkippedRepositories {
+		if !slices.ContainsFunc(dst.SkippedRepositories, func(s SkippedRepo) bool { return s.Name == skipped.Name }) {
+			dst.SkippedRepositories = append(dst.SkippedRepositories, skipped)
+		}
+	}
+	if src.TimeRange.Start.Before(dst.TimeRange.Start) {
+		dst.TimeRange.Start = src.TimeRange.Start
+	}
+	if src.TimeRange.End.After(dst.TimeRange.End) {
+		dst.TimeRange.End = src.TimeRange.End
+	}
+
+	for _, srcRepo := range src.Repositories {
+		i := slices.IndexFunc(dst.Repositories, func(r Repository) bool {
+			return r.Organization == srcRepo.Organization && r.Name == srcRepo.Name
+		})
+		if i == -1 {
+			dst.Repositories = append(dst.Repositories, srcRepo)
+			continue
+		}
+
+		dstRepo := &dst.Repositories[i]
+		for _, srcPR := range srcRepo.PullRequests {
+			j := slices.IndexFunc(dstRepo.PullRequests, func(pr PullRequest) bool {
+				return pr.Number == srcPR.Number
+			})
+			if j == -1 {
+				dstRepo.PullRequests = append(dstRepo.PullRequests, srcPR)
+				continue
+			}
+			mergePullRequests(&dstRepo.PullRequests[j], srcPR)
+		}
+	}
+}
+
+// mergePullRequests merges the activity of src into dst. Activity already
+// present in dst, from overlapping ranges, is kept under its first label.
+func mergePullRequests(dst *PullRequest, src PullRequest) {
+	dst.IsAuthored = dst.IsAuthored || src.IsAuthored
+	dst.IsReviewed = dst.IsReviewed || src.IsReviewed
+	dst.IsClosedByUser = dst.IsClosedByUser || src.IsClosedByUser
+	dst.IsMetadataEdited = dst.IsMetadataEdited || src.IsMetadataEdited
+	for _, transition := range src.StateTransitions {
+		if !slices.Contains(dst.StateTransitions, transition) {
+			dst.StateTransitions = append(dst.StateTransitions, transition)
+		}
+	}
+	if src.UpdatedAt.After(dst.UpdatedAt) {
+		dst.UpdatedAt = src.UpdatedAt
+		dst.State = src.State
+	}
+
+	for _, commit := range src.Commits {
+		if !slices.ContainsFunc(dst.Commits, func(c Commit) bool { return c.SHA == commit.SHA }) {
+			dst.Commits = append(dst.Commits, commit)
+		}
+	}
+	for _, review := range src.Reviews {
+		if !slices.ContainsFunc(dst.Reviews, func(r Review) bool { return r.ID == review.ID }) {
+			dst.Reviews = append(dst.Reviews, review)
+		}
+	}
+	for _, comment := range src.Comments {
+		if !slices.ContainsFunc(dst.Comments, func(c Comment) bool { return c.ID == comment.ID }) {
+			dst.Comments = append(dst.Comments, comment)
+		}
+	}
+	for _, action := range src.MetadataActions {
+		if !slices.ContainsFunc(dst.MetadataActions, func(a MetadataAction) bool {
+			return a.Event == action.Event && a.Detail == action.Detail && a.Timestamp.Equal(action.Timestamp)
+		}) {
+			dst.MetadataActions = append(dst.MetadataActions, action)
+		}
+	}
+}
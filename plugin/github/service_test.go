@@ -1,7 +1,15 @@
 package github
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -209,7 +217,7 @@ func TestActivityService_GetActivityReport(t *testing.T) {
 
 				// Check user info if repositories were returned
 				if tc.expectedRepos > 0 {
-					expectedUser, _ := tc.mockRepo.GetUser()
+					expectedUser, _ := tc.mockRepo.GetUser(context.Background())
 					if report.User.Username != expectedUser.Username {
 						t.Errorf("Expected username %s, got %s", expectedUser.Username, report.User.Username)
 					}
@@ -260,7 +268,7 @@ func TestActivityService_ProcessRepository(t *testing.T) {
 	}
 	
 	// Call the method being tested
-	repo, err := service.processRepository("testorg", "repo1", timeRange)
+	repo, err := service.processRepository(context.Background(), "testorg", "repo1", timeRange)
 	
 	// Check error
 	if err != nil {
@@ -286,10 +294,951 @@ func TestActivityService_ProcessRepository(t *testing.T) {
 	}
 	
 	// Call the method being tested
-	_, err = service.processRepository("testorg", "repo1", timeRange)
+	_, err = service.processRepository(context.Background(), "testorg", "repo1", timeRange)
 	
 	// Check error
 	if err == nil {
 		t.Errorf("Expected an error but got nil")
 	}
 } 
+
+func TestActivityService_ResumeFromCheckpoint(t *testing.T) {
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	var mu sync.Mutex
+	var calls []string
+	interrupted := true
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, repo)
+
+			// Simulate an interruption after the first two repositories
+			if interrupted && (repo == "repo3" || repo == "repo4") {
+				return nil, errors.New("interrupted")
+			}
+			return []PullRequest{{Number: 1, Title: "PR in " + repo, IsAuthored: true}}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:       "testuser",
+		Organization:   "testorg",
+		Repositories:   []string{"repo1", "repo2", "repo3", "repo4"},
+		QueryOptions:   DefaultQueryOptions(),
+		CheckpointFile: checkpointFile,
+		Resume:         true,
+	}
+	timeRange := plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(report.Repositories) != 2 {
+		t.Fatalf("Expected 2 repositories before the interruption, got %d", len(report.Repositories))
+	}
+	if _, err := os.Stat(checkpointFile); err != nil {
+		t.Fatalf("Expected checkpoint file to be written: %v", err)
+	}
+
+	// Resume the run
+	interrupted = false
+	calls = nil
+	report, err = service.GetActivityReport(timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	slices.Sort(calls)
+	if !slices.Equal(calls, []string{"repo3", "repo4"}) {
+		t.Errorf("Expected only repo3 and repo4 to be processed on resume, got %v", calls)
+	}
+
+	names := make([]string, 0, len(report.Repositories))
+	for _, repo := range report.Repositories {
+		names = append(names, repo.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"repo1", "repo2", "repo3", "repo4"}) {
+		t.Errorf("Expected all four repositories after resume, got %v", names)
+	}
+
+	if _, err := os.Stat(checkpointFile); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint file to be removed after a complete run")
+	}
+}
+
+func TestActivityService_CheckpointRemovedWithArchivedRepos(t *testing.T) {
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetRepositoryMetadata: func(org string, repo string) (RepositoryMetadata, error) {
+			return RepositoryMetadata{Archived: repo == "old"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{{Number: 1, Title: "PR in " + repo, IsAuthored: true}}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:       "testuser",
+		Organization:   "testorg",
+		Repositories:   []string{"current", "old"},
+		QueryOptions:   DefaultQueryOptions(),
+		ArchivedRepos:  ArchivedReposSkip,
+		CheckpointFile: checkpointFile,
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(report.SkippedRepositories) != 1 {
+		t.Fatalf("Expected the archived repository to be skipped, got %+v", report.SkippedRepositories)
+	}
+
+	// The archived repository is skipped on every run, so the run is complete
+	if _, err := os.Stat(checkpointFile); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint file to be removed after a complete run")
+	}
+}
+
+func TestActivityService_GetActivityReportMulti(t *testing.T) {
+	lastWeek := plug.TimeRange{
+		Start: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 9, 0, 0, 0, 0, time.UTC),
+	}
+	today := plug.TimeRange{
+		Start: time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 13, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			// PR #1 spans both ranges with a different commit in each
+			prs := []PullRequest{
+				{
+					Number:     1,
+					Title:      "Long running PR",
+					IsAuthored: true,
+					Commits: []Commit{
+						{SHA: timeRange.Start.Format("0102"), Message: "Work on " + timeRange.Start.Format("2006-01-02"), Timestamp: timeRange.Start.Add(time.Hour)},
+					},
+				},
+			}
+			if timeRange.Start.Equal(today.Start) {
+				prs = append(prs, PullRequest{Number: 2, Title: "New PR", IsAuthored: true})
+			}
+			return prs, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReportMulti([]plug.TimeRange{lastWeek, today})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(report.Ranges) != 2 {
+		t.Fatalf("Expected 2 labeled ranges, got %d", len(report.Ranges))
+	}
+	if !report.TimeRange.Start.Equal(lastWeek.Start) || !report.TimeRange.End.Equal(today.End) {
+		t.Errorf("Expected overall range to span both ranges, got %s", report.TimeRange.Label())
+	}
+
+	if len(report.Repositories) != 1 {
+		t.Fatalf("Expected 1 repository, got %d", len(report.Repositories))
+	}
+	prs := report.Repositories[0].PullRequests
+	if len(prs) != 2 {
+		t.Fatalf("Expected the overlapping PR to be deduplicated into 2 PRs, got %d", len(prs))
+	}
+
+	commits := prs[0].Commits
+	if len(commits) != 2 {
+		t.Fatalf("Expected both ranges' commits on the overlapping PR, got %d", len(commits))
+	}
+	if commits[0].Range != "2023-01-02 to 2023-01-09" || commits[1].Range != "2023-01-12 to 2023-01-13" {
+		t.Errorf("Expected commits labeled by range, got %q and %q", commits[0].Range, commits[1].Range)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	for _, expected := range []string{
+		"**Time Ranges:** 2023-01-02 to 2023-01-09, 2023-01-12 to 2023-01-13",
+		"- [2023-01-02 to 2023-01-09] 2023-01-02 01:00: Work on 2023-01-02",
+		"- [2023-01-12 to 2023-01-13] 2023-01-12 01:00: Work on 2023-01-12",
+	} {
+		if !strings.Contains(content.Content, expected) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", expected, content.Content)
+		}
+	}
+	if strings.Count(content.Content, "Long running PR") != 1 {
+		t.Errorf("Expected the overlapping PR to render once, got:\n%s", content.Content)
+	}
+}
+
+func TestActivityService_FastFail(t *testing.T) {
+	repoErr := errors.New("repository unavailable")
+
+	for _, repositories := range [][]string{{"repo1"}, {"repo1", "repo2", "repo3"}} {
+		t.Run(strings.Join(repositories, ","), func(t *testing.T) {
+			mockRepo := &MockGitHubRepository{
+				MockGetUser: func() (*User, error) {
+					return &User{Username: "testuser"}, nil
+				},
+				MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+					if repo == "repo1" {
+						return nil, repoErr
+					}
+					return []PullRequest{{Number: 1, IsAuthored: true}}, nil
+				},
+			}
+
+			config := &GitHubConfig{
+				Username:     "testuser",
+				Organization: "testorg",
+				Repositories: repositories,
+				QueryOptions: DefaultQueryOptions(),
+				FastFail:     true,
+			}
+
+			service := NewActivityService(mockRepo, config)
+			report, err := service.GetActivityReport(plug.TimeRange{
+				Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			})
+
+			if !errors.Is(err, repoErr) {
+				t.Errorf("Expected the underlying repository error, got: %v", err)
+			}
+			if report != nil {
+				t.Errorf("Expected no report when fast-fail aborts, got %+v", report)
+			}
+		})
+	}
+}
+
+func TestActivityService_FastFailCancelsRepositories(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	// The failing repository's search errors at once, while the other's
+	// blocks until its request is canceled
+	canceled := make(chan struct{})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "repo:testorg/broken") {
+			http.Error(w, "server error", http.StatusInternalServerError)
+			return
+		}
+		<-r.Context().Done()
+		close(canceled)
+	})
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"broken", "slow"},
+		QueryOptions: options,
+		FastFail:     true,
+	}
+
+	service := NewActivityService(NewGitHubAPIRepository(client, "testuser"), config)
+	if _, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}); err == nil {
+		t.Fatal("Expected the failing repository to abort the report")
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the other repository's request to be canceled by the fast-fail")
+	}
+}
+
+func TestActivityService_FastFailOnEnrichmentError(t *testing.T) {
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{{Number: 1, IsAuthored: true, EnrichmentErrors: []string{"failed to list commits"}}}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+		FastFail:     true,
+	}
+
+	service := NewActivityService(mockRepo, config)
+	_, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to list commits") {
+		t.Errorf("Expected enrichment error to abort the report, got: %v", err)
+	}
+}
+
+func TestActivityService_FastFailKeepsEnrichmentErrorType(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, searchResult(1))
+	})
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+	mux.HandleFunc("/repos/testorg/repo1/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	})
+
+	options := DefaultQueryOptions()
+	options.IncludeReviewed = false
+	options.IncludeComments = false
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: options,
+		FastFail:     true,
+	}
+
+	service := NewActivityService(NewGitHubAPIRepository(client, "testuser"), config)
+	_, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, ErrAuth) {
+		t.Errorf("Expected the enrichment error to keep its kind, got: %v", err)
+	}
+}
+
+func TestActivityService_ActivityCriteria(t *testing.T) {
+	inRange := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{
+				{Number: 1, IsAuthored: true, Commits: []Commit{{SHA: "abc", Timestamp: inRange}}},
+				{Number: 2, IsAuthored: true, Comments: []Comment{{ID: 1, Timestamp: inRange}}},
+				{Number: 3, IsReviewed: true, Comments: []Comment{{ID: 2, Timestamp: inRange}}},
+			}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:         "testuser",
+		Organization:     "testorg",
+		Repositories:     []string{"repo1"},
+		QueryOptions:     DefaultQueryOptions(),
+		AuthoredActivity: ActivityCriteria{HasCommits: true},
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	var numbers []int
+	for _, pr := range report.Repositories[0].PullRequests {
+		numbers = append(numbers, pr.Number)
+	}
+
+	// The comments-only authored PR is dropped; reviewed PRs use their own
+	// (empty) criteria and are kept
+	if !slices.Equal(numbers, []int{1, 3}) {
+		t.Errorf("Expected PRs [1 3], got %v", numbers)
+	}
+}
+
+func TestActivityService_DeduplicatesRepositories(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[repo]++
+			return []PullRequest{{Number: 1, IsAuthored: true}}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1", "repo2", "Repo1", "TestOrg/repo1", "repo2"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if calls["repo1"] != 1 || calls["repo2"] != 1 || len(calls) != 2 {
+		t.Errorf("Expected each repository to be processed once, got %v", calls)
+	}
+	if len(report.Repositories) != 2 {
+		t.Errorf("Expected 2 repositories in the report, got %d", len(report.Repositories))
+	}
+}
+
+func TestDedupeRepositories(t *testing.T) {
+	repoNames := dedupeRepositories("testorg", []string{"repo2", "testorg/Repo1", "repo1", "other/repo1", "REPO2"})
+	expected := []string{"repo2", "Repo1", "other/repo1"}
+	if !slices.Equal(repoNames, expected) {
+		t.Errorf("Expected %v, got %v", expected, repoNames)
+	}
+}
+
+func TestActivityService_RejectsInvertedTimeRange(t *testing.T) {
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			t.Error("Expected no API calls for an inverted time range")
+			return &User{Username: "testuser"}, nil
+		},
+	}
+
+	service := NewActivityService(mockRepo, &GitHubConfig{Repositories: []string{"repo1"}})
+	_, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err == nil {
+		t.Error("Expected an error for an inverted time range")
+	}
+}
+
+func TestActivityService_Highlights(t *testing.T) {
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{
+				{
+					Number:     1,
+					Title:      "Ship it",
+					State:      "merged",
+					IsAuthored: true,
+					CreatedAt:  time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC),
+					MergedAt:   time.Date(2023, 1, 1, 15, 0, 0, 0, time.UTC),
+					ClosedAt:   time.Date(2023, 1, 1, 15, 0, 0, 0, time.UTC),
+				},
+				{
+					Number:     2,
+					Title:      "Still going",
+					State:      "open",
+					IsAuthored: true,
+					CreatedAt:  time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC),
+				},
+			}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	prs := report.Repositories[0].PullRequests
+	if !slices.Equal(prs[0].StateTransitions, []string{StateTransitionMerged}) || prs[1].StateChangedInRange() {
+		t.Errorf("Expected only PR #1 to be merged in range, got %v and %v", prs[0].StateTransitions, prs[1].StateTransitions)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+
+	highlights, _, found := strings.Cut(content.Content, "## Repository:")
+	if !found || !strings.Contains(highlights, "## Highlights") {
+		t.Fatalf("Expected a highlights section before the repositories, got:\n%s", content.Content)
+	}
+	if !strings.Contains(highlights, "**Merged** [#1]") || strings.Contains(highlights, "#2") {
+		t.Errorf("Expected only the merged PR under highlights, got:\n%s", highlights)
+	}
+}
+
+func TestActivityService_OnProgress(t *testing.T) {
+	for _, repositories := range [][]string{{"repo1"}, {"repo1", "repo2", "repo3", "repo4"}} {
+		t.Run(strings.Join(repositories, ","), func(t *testing.T) {
+			mockRepo := &MockGitHubRepository{
+				MockGetUser: func() (*User, error) {
+					return &User{Username: "testuser"}, nil
+				},
+				MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+					// Failed repositories still count towards progress
+					if repo == "repo2" {
+						return nil, errors.New("repository unavailable")
+					}
+					return []PullRequest{{Number: 1, IsAuthored: true}}, nil
+				},
+			}
+
+			var calls []int
+			config := &GitHubConfig{
+				Username:     "testuser",
+				Organization: "testorg",
+				Repositories: repositories,
+				QueryOptions: DefaultQueryOptions(),
+				OnProgress: func(done, total int) {
+					if total != len(repositories) {
+						t.Errorf("Expected total %d, got %d", len(repositories), total)
+					}
+					calls = append(calls, done)
+				},
+			}
+
+			service := NewActivityService(mockRepo, config)
+			if _, err := service.GetActivityReport(plug.TimeRange{
+				Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			}); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			if len(calls) != len(repositories) {
+				t.Fatalf("Expected %d progress calls, got %v", len(repositories), calls)
+			}
+			for i, done := range calls {
+				if done != i+1 {
+					t.Errorf("Expected done to increase by one on each call, got %v", calls)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestActivityService_SkippedRepositories(t *testing.T) {
+	client, mux := newTestClient(t)
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "repo:testorg/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		writeJSON(t, w, searchResult())
+	})
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1", "missing"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+
+	service := NewActivityService(NewGitHubAPIRepository(client, "testuser"), config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(report.SkippedRepositories) != 1 {
+		t.Fatalf("Expected 1 skipped repository, got %+v", report.SkippedRepositories)
+	}
+	skipped := report.SkippedRepositories[0]
+	if skipped.Name != "missing" || skipped.Kind != ErrorKindNotFound || !strings.Contains(skipped.Reason, "not found") {
+		t.Errorf("Expected the missing repository skipped as not found, got %+v", skipped)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "**Skipped repositories:**\n\n- missing (not_found): ") {
+		t.Errorf("Expected a skipped footnote, got:\n%s", content.Content)
+	}
+}
+
+func TestActivityService_TotalBudget(t *testing.T) {
+	// Blocked repositories are released once the test ends
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			if repo == "slow" {
+				<-release
+			}
+			return []PullRequest{{Number: 1, Title: "Test PR", IsAuthored: true}}, nil
+		},
+	}
+
+	for _, repositories := range [][]string{{"fast", "slow"}, {"slow"}} {
+		config := &GitHubConfig{
+			Username:     "testuser",
+			Organization: "testorg",
+			Repositories: repositories,
+			QueryOptions: DefaultQueryOptions(),
+			TotalBudget:  100 * time.Millisecond,
+			FastFail:     true,
+		}
+
+		service := NewActivityService(mockRepo, config)
+		start := time.Now()
+		report, err := service.GetActivityReport(plug.TimeRange{
+			Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatalf("Expected a partial report but got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the report to return at the budget, took %s", elapsed)
+		}
+
+		if len(report.Repositories) != len(repositories)-1 {
+			t.Errorf("Expected the completed repositories in the report, got %+v", report.Repositories)
+		}
+		if len(report.SkippedRepositories) != 1 {
+			t.Fatalf("Expected the slow repository to be skipped, got %+v", report.SkippedRepositories)
+		}
+		skipped := report.SkippedRepositories[0]
+		if skipped.Name != "slow" || skipped.Kind != ErrorKindBudget || !strings.Contains(skipped.Reason, "budget exceeded") {
+			t.Errorf("Expected the slow repository skipped for the budget, got %+v", skipped)
+		}
+	}
+}
+
+func TestActivityService_TotalBudgetCancelsRequests(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	// The search blocks until its request is canceled
+	canceled := make(chan struct{})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(canceled)
+	})
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"slow"},
+		QueryOptions: DefaultQueryOptions(),
+		TotalBudget:  100 * time.Millisecond,
+	}
+
+	service := NewActivityService(NewGitHubAPIRepository(client, "testuser"), config)
+	if _, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("Expected a partial report but got: %v", err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Expected the abandoned repository's request to be canceled at the budget")
+	}
+}
+
+func TestActivityService_TotalBudgetCustomQuery(t *testing.T) {
+	client, mux := newTestClient(t)
+
+	// The search blocks until its request is canceled
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/users/testuser", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, map[string]any{"login": "testuser"})
+	})
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		CustomQuery:  "is:pr involves:testuser",
+		QueryOptions: DefaultQueryOptions(),
+		TotalBudget:  100 * time.Millisecond,
+	}
+
+	service := NewActivityService(NewGitHubAPIRepository(client, "testuser"), config)
+	_, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected the custom query to stop at the budget, got: %v", err)
+	}
+}
+
+func TestActivityService_ArchivedRepos(t *testing.T) {
+	// Repositories are processed concurrently
+	var mu sync.Mutex
+	searched := make(map[string]bool)
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetRepositoryMetadata: func(org string, repo string) (RepositoryMetadata, error) {
+			return RepositoryMetadata{Archived: repo == "old"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			mu.Lock()
+			searched[repo] = true
+			mu.Unlock()
+			return []PullRequest{{Number: 1, Title: "Test PR", IsAuthored: true}}, nil
+		},
+	}
+
+	testCases := []struct {
+		mode     string
+		expected []string
+		skipped  bool
+	}{
+		{mode: ArchivedReposSkip, expected: []string{"current"}, skipped: true},
+		{mode: ArchivedReposTag, expected: []string{"current", "old"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.mode, func(t *testing.T) {
+			clear(searched)
+			config := &GitHubConfig{
+				Username:      "testuser",
+				Organization:  "testorg",
+				Repositories:  []string{"current", "old"},
+				QueryOptions:  DefaultQueryOptions(),
+				ArchivedRepos: tc.mode,
+				FastFail:      true,
+			}
+
+			service := NewActivityService(mockRepo, config)
+			report, err := service.GetActivityReport(plug.TimeRange{
+				Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			})
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			var names []string
+			for _, repo := range report.Repositories {
+				names = append(names, repo.Name)
+				if repo.Archived != (repo.Name == "old") {
+					t.Errorf("Expected only the old repository to be tagged archived, got %+v", repo)
+				}
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tc.expected) {
+				t.Errorf("Expected repositories %v, got %v", tc.expected, names)
+			}
+
+			if !tc.skipped {
+				return
+			}
+			if searched["old"] {
+				t.Errorf("Expected the archived repository not to be searched")
+			}
+			if len(report.SkippedRepositories) != 1 || report.SkippedRepositories[0].Name != "old" ||
+				report.SkippedRepositories[0].Kind != ErrorKindArchived {
+				t.Errorf("Expected the archived repository skipped with a note, got %+v", report.SkippedRepositories)
+			}
+		})
+	}
+}
+
+func TestActivityService_CanonicalRepoNames(t *testing.T) {
+	var searched string
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetRepositoryMetadata: func(org string, repo string) (RepositoryMetadata, error) {
+			return RepositoryMetadata{Name: strings.ToLower(repo)}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			searched = repo
+			return []PullRequest{{Number: 1, Title: "Test PR", IsAuthored: true}}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:           "testuser",
+		Organization:       "testorg",
+		Repositories:       []string{"MyRepo"},
+		QueryOptions:       DefaultQueryOptions(),
+		CanonicalRepoNames: true,
+	}
+
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if searched != "myrepo" {
+		t.Errorf("Expected pull requests searched in myrepo, got %q", searched)
+	}
+	if len(report.Repositories) != 1 || report.Repositories[0].Name != "myrepo" {
+		t.Fatalf("Expected the canonical repository name in the report, got %+v", report.Repositories)
+	}
+
+	formatted, err := (&MarkdownFormatter{}).Format(report)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !strings.Contains(formatted.Content, "myrepo") || strings.Contains(formatted.Content, "MyRepo") {
+		t.Errorf("Expected only the canonical repository name in the output, got:\n%s", formatted.Content)
+	}
+}
+
+func TestActivityService_PullRequestURL(t *testing.T) {
+	var fetched string
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequest: func(org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error) {
+			fetched = fmt.Sprintf("%s/%s#%d", org, repo, number)
+			return PullRequest{Number: number, Title: "Old PR", IsReviewed: true}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:       "testuser",
+		QueryOptions:   DefaultQueryOptions(),
+		PullRequestURL: "https://github.com/testorg/testrepo/pull/42",
+	}
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if fetched != "testorg/testrepo#42" {
+		t.Errorf("Expected testorg/testrepo#42 to be fetched, got %q", fetched)
+	}
+	if len(report.Repositories) != 1 || len(report.Repositories[0].PullRequests) != 1 {
+		t.Fatalf("Expected the pull request to be reported without activity, got %+v", report.Repositories)
+	}
+
+	config.PullRequestURL = "https://github.com/testorg/testrepo/issues/42"
+	if _, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	}); err == nil || !strings.Contains(err.Error(), "invalid pull request URL") {
+		t.Errorf("Expected an invalid pull request URL error, got %v", err)
+	}
+}
+
+func TestActivityService_OnlyOpen(t *testing.T) {
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{
+				{Number: 1, Title: "In flight", State: "open", IsAuthored: true},
+				{Number: 2, Title: "Shipped", State: "merged", IsAuthored: true},
+			}, nil
+		},
+	}
+
+	for _, onlyOpen := range []bool{false, true} {
+		config := &GitHubConfig{
+			Username:     "testuser",
+			Organization: "testorg",
+			Repositories: []string{"testrepo"},
+			QueryOptions: DefaultQueryOptions(),
+			OnlyOpen:     onlyOpen,
+		}
+		service := NewActivityService(mockRepo, config)
+		report, err := service.GetActivityReport(plug.TimeRange{
+			Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+
+		prs := report.Repositories[0].PullRequests
+		if onlyOpen {
+			if len(prs) != 1 || prs[0].Number != 1 {
+				t.Errorf("Expected only the open pull request, got %+v", prs)
+			}
+		} else if len(prs) != 2 {
+			t.Errorf("Expected both pull requests without OnlyOpen, got %+v", prs)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"context"
+
+	externalGithub "github.com/google/go-github/v68/github"
+)
+
+// The narrow views of the go-github services used by GitHubAPIRepository.
+// The go-github services satisfy them as they are, while tests can supply
+// in-memory fakes instead of a test server.
+
+// searchService searches issues and pull requests
+type searchService interface {
+	Issues(ctx context.Context, query string, opts *externalGithub.SearchOptions) (*externalGithub.IssuesSearchResult, *externalGithub.Response, error)
+}
+
+// pullRequestsService reads pull requests and their commits, comments,
+// files, and reviews, and finds the pull requests containing a commit
+type pullRequestsService interface {
+	Get(ctx context.Context, owner string, repo string, number int) (*externalGithub.PullRequest, *externalGithub.Response, error)
+	ListCommits(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.RepositoryCommit, *externalGithub.Response, error)
+	ListComments(ctx context.Context, owner string, repo string, number int, opts *externalGithub.PullRequestListCommentsOptions) ([]*externalGithub.PullRequestComment, *externalGithub.Response, error)
+	ListFiles(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.CommitFile, *externalGithub.Response, error)
+	ListReviews(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.PullRequestReview, *externalGithub.Response, error)
+	ListPullRequestsWithCommit(ctx context.Context, owner string, repo string, sha string, opts *externalGithub.ListOptions) ([]*externalGithub.PullRequest, *externalGithub.Response, error)
+}
+
+// usersService looks up users
+type usersService interface {
+	Get(ctx context.Context, user string) (*externalGithub.User, *externalGithub.Response, error)
+}
+
+// repositoriesService reads repositories and their commits
+type repositoriesService interface {
+	Get(ctx context.Context, owner string, repo string) (*externalGithub.Repository, *externalGithub.Response, error)
+	GetCommit(ctx context.Context, owner string, repo string, sha string, opts *externalGithub.ListOptions) (*externalGithub.RepositoryCommit, *externalGithub.Response, error)
+	ListCommits(ctx context.Context, owner string, repo string, opts *externalGithub.CommitsListOptions) ([]*externalGithub.RepositoryCommit, *externalGithub.Response, error)
+}
+
+// issuesService lists the events and conversation comments of issues and
+// pull requests
+type issuesService interface {
+	ListIssueEvents(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.IssueEvent, *externalGithub.Response, error)
+	ListComments(ctx context.Context, owner string, repo string, number int, opts *externalGithub.IssueListCommentsOptions) ([]*externalGithub.IssueComment, *externalGithub.Response, error)
+}
+
+// activityService lists the events a user performed
+type activityService interface {
+	ListEventsPerformedByUser(ctx context.Context, user string, publicOnly bool, opts *externalGithub.ListOptions) ([]*externalGithub.Event, *externalGithub.Response, error)
+}
+
+// Compile-time checks that the go-github services satisfy the interfaces
+var (
+	_ searchService       = (*externalGithub.SearchService)(nil)
+	_ pullRequestsService = (*externalGithub.PullRequestsService)(nil)
+	_ usersService        = (*externalGithub.UsersService)(nil)
+	_ repositoriesService = (*externalGithub.RepositoriesService)(nil)
+	_ issuesService       = (*externalGithub.IssuesService)(nil)
+	_ activityService     = (*externalGithub.ActivityService)(nil)
+)
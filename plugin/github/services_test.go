@@ -0,0 +1,763 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	externalGithub "github.com/google/go-github/v68/github"
+	plug "github.com/iures/daivplug"
+)
+
+// fakeSearchService returns fixed search results, or err when set, and
+// records the queries run
+type fakeSearchService struct {
+	issues  []*externalGithub.Issue
+	err     error
+	mu      sync.Mutex
+	queries []string
+}
+
+func (f *fakeSearchService) Issues(ctx context.Context, query string, opts *externalGithub.SearchOptions) (*externalGithub.IssuesSearchResult, *externalGithub.Response, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, query)
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return &externalGithub.IssuesSearchResult{
+		Total:  externalGithub.Ptr(len(f.issues)),
+		Issues: f.issues,
+	}, &externalGithub.Response{}, nil
+}
+
+// fakePullRequestsService serves the commits, review comments, and reviews
+// of pull requests by number, and the pull requests containing a commit by
+// SHA. The other listings are empty.
+type fakePullRequestsService struct {
+	commits    map[int][]*externalGithub.RepositoryCommit
+	comments   map[int][]*externalGithub.PullRequestComment
+	reviews    map[int][]*externalGithub.PullRequestReview
+	withCommit map[string][]*externalGithub.PullRequest
+}
+
+func (f *fakePullRequestsService) Get(ctx context.Context, owner string, repo string, number int) (*externalGithub.PullRequest, *externalGithub.Response, error) {
+	return &externalGithub.PullRequest{Number: externalGithub.Ptr(number)}, &externalGithub.Response{}, nil
+}
+
+func (f *fakePullRequestsService) ListCommits(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.RepositoryCommit, *externalGithub.Response, error) {
+	return f.commits[number], &externalGithub.Response{}, nil
+}
+
+func (f *fakePullRequestsService) ListComments(ctx context.Context, owner string, repo string, number int, opts *externalGithub.PullRequestListCommentsOptions) ([]*externalGithub.PullRequestComment, *externalGithub.Response, error) {
+	return f.comments[number], &externalGithub.Response{}, nil
+}
+
+func (f *fakePullRequestsService) ListFiles(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.CommitFile, *externalGithub.Response, error) {
+	return nil, &externalGithub.Response{}, nil
+}
+
+func (f *fakePullRequestsService) ListReviews(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.PullRequestReview, *externalGithub.Response, error) {
+	return f.reviews[number], &externalGithub.Response{}, nil
+}
+
+func (f *fakePullRequestsService) ListPullRequestsWithCommit(ctx context.Context, owner string, repo string, sha string, opts *externalGithub.ListOptions) ([]*externalGithub.PullRequest, *externalGithub.Response, error) {
+	return f.withCommit[sha], &externalGithub.Response{}, nil
+}
+
+// fakeUsersService serves users by login
+type fakeUsersService struct {
+	users map[string]*externalGithub.User
+}
+
+func (f *fakeUsersService) Get(ctx context.Context, user string) (*externalGithub.User, *externalGithub.Response, error) {
+	found, ok := f.users[user]
+	if !ok {
+		return nil, nil, fmt.Errorf("user %s not found", user)
+	}
+	return found, &externalGithub.Response{}, nil
+}
+
+// fakeRepositoriesService serves repositories by name, commits by SHA, and a
+// fixed commit listing paged by the requested page size, recording the
+// options it was listed with
+type fakeRepositoriesService struct {
+	repositories map[string]*externalGithub.Repository
+	commits      map[string]*externalGithub.RepositoryCommit
+	listed       []*externalGithub.RepositoryCommit
+	listOptions  *externalGithub.CommitsListOptions
+	
+	// Called at the start of each Get, which may run concurrently
+	onGet func(repo string)
+	
+	mu   sync.Mutex
+	gets int
+}
+
+func (f *fakeRepositoriesService) Get(ctx context.Context, owner string, repo string) (*externalGithub.Repository, *externalGithub.Response, error) {
+	f.mu.Lock()
+	f.gets++
+	f.mu.Unlock()
+	if f.onGet != nil {
+		f.onGet(repo)
+	}
+	repository, ok := f.repositories[owner+"/"+repo]
+	if !ok {
+		return nil, nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+	}
+	return repository, &externalGithub.Response{}, nil
+}
+
+func (f *fakeRepositoriesService) GetCommit(ctx context.Context, owner string, repo string, sha string, opts *externalGithub.ListOptions) (*externalGithub.RepositoryCommit, *externalGithub.Response, error) {
+	commit, ok := f.commits[sha]
+	if !ok {
+		return nil, nil, fmt.Errorf("commit %s not found", sha)
+	}
+	return commit, &externalGithub.Response{}, nil
+}
+
+func (f *fakeRepositoriesService) ListCommits(ctx context.Context, owner string, repo string, opts *externalGithub.CommitsListOptions) ([]*externalGithub.RepositoryCommit, *externalGithub.Response, error) {
+	f.listOptions = opts
+	if opts.PerPage <= 0 || opts.PerPage >= len(f.listed) {
+		return f.listed, &externalGithub.Response{}, nil
+	}
+	
+	page := max(opts.Page, 1)
+	start := min((page-1)*opts.PerPage, len(f.listed))
+	end := min(start+opts.PerPage, len(f.listed))
+	resp := &externalGithub.Response{}
+	if end < len(f.listed) {
+		resp.NextPage = page + 1
+	}
+	return f.listed[start:end], resp, nil
+}
+
+// fakeIssuesService serves the events and conversation comments of issues by
+// number, in a single page
+type fakeIssuesService struct {
+	events   map[int][]*externalGithub.IssueEvent
+	comments map[int][]*externalGithub.IssueComment
+}
+
+func (f *fakeIssuesService) ListIssueEvents(ctx context.Context, owner string, repo string, number int, opts *externalGithub.ListOptions) ([]*externalGithub.IssueEvent, *externalGithub.Response, error) {
+	return f.events[number], &externalGithub.Response{}, nil
+}
+
+func (f *fakeIssuesService) ListComments(ctx context.Context, owner string, repo string, number int, opts *externalGithub.IssueListCommentsOptions) ([]*externalGithub.IssueComment, *externalGithub.Response, error) {
+	return f.comments[number], &externalGithub.Response{}, nil
+}
+
+// fakeCommit builds a pull request commit made at the given time
+func fakeCommit(sha string, message string, date time.Time) *externalGithub.RepositoryCommit {
+	return &externalGithub.RepositoryCommit{
+		SHA: externalGithub.Ptr(sha),
+		Commit: &externalGithub.Commit{
+			Message:   externalGithub.Ptr(message),
+			Committer: &externalGithub.CommitAuthor{Date: &externalGithub.Timestamp{Time: date}},
+		},
+	}
+}
+
+func TestGitHubAPIRepository_FakeSearch(t *testing.T) {
+	search := &fakeSearchService{
+		issues: []*externalGithub.Issue{
+			{
+				Number: externalGithub.Ptr(1),
+				Title:  externalGithub.Ptr("Add feature"),
+				State:  externalGithub.Ptr("open"),
+				User:   &externalGithub.User{Login: externalGithub.Ptr("testuser")},
+			},
+		},
+	}
+	repository := &GitHubAPIRepository{search: search, username: "testuser"}
+
+	prs, err := repository.searchAuthoredPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), DefaultQueryOptions())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(search.queries) != 1 || !strings.Contains(search.queries[0], "author:testuser repo:testorg/repo1") {
+		t.Errorf("Expected an authored query for testorg/repo1, got %v", search.queries)
+	}
+	if len(prs) != 1 || prs[0].Title != "Add feature" || !prs[0].IsAuthored {
+		t.Errorf("Expected authored PR #1, got %+v", prs)
+	}
+}
+
+func TestGitHubAPIRepository_FakeUsers(t *testing.T) {
+	users := &fakeUsersService{
+		users: map[string]*externalGithub.User{
+			"testuser": {Login: externalGithub.Ptr("testuser"), Email: externalGithub.Ptr("test@example.com")},
+		},
+	}
+
+	user, err := (&GitHubAPIRepository{users: users, username: "testuser"}).GetUser(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if user.Username != "testuser" || user.Email != "test@example.com" {
+		t.Errorf("Expected testuser, got %+v", user)
+	}
+
+	if _, err := (&GitHubAPIRepository{users: users, username: "missing"}).GetUser(context.Background()); err == nil {
+		t.Errorf("Expected an error for an unknown user")
+	}
+}
+
+func TestGitHubAPIRepository_FakePullRequestsAndRepositories(t *testing.T) {
+	inRange := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	pullRequests := &fakePullRequestsService{
+		commits: map[int][]*externalGithub.RepositoryCommit{
+			1: {
+				fakeCommit("aaa", "Change docs", inRange),
+				fakeCommit("bbb", "Change service", inRange),
+				fakeCommit("ccc", "Old change", inRange.AddDate(0, 0, -7)),
+			},
+		},
+	}
+	repositories := &fakeRepositoriesService{
+		commits: map[string]*externalGithub.RepositoryCommit{
+			"aaa": {Files: []*externalGithub.CommitFile{{Filename: externalGithub.Ptr("docs/README.md")}}},
+			"bbb": {Files: []*externalGithub.CommitFile{{Filename: externalGithub.Ptr("services/api/main.go")}}},
+		},
+	}
+	repository := &GitHubAPIRepository{pullRequests: pullRequests, repositories: repositories, username: "testuser"}
+
+	options := DefaultQueryOptions()
+	options.PathPrefix = "services/api/"
+	commits, err := repository.getCommits(context.Background(), "testorg", "repo1", 1, testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(commits) != 1 || commits[0].SHA != "bbb" {
+		t.Errorf("Expected only the in-range commit under the path prefix, got %+v", commits)
+	}
+}
+
+func TestGitHubAPIRepository_FakeIssues(t *testing.T) {
+	closedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	issues := &fakeIssuesService{
+		events: map[int][]*externalGithub.IssueEvent{
+			1: {{
+				Event:     externalGithub.Ptr("closed"),
+				Actor:     &externalGithub.User{Login: externalGithub.Ptr("testuser")},
+				CreatedAt: &externalGithub.Timestamp{Time: closedAt},
+			}},
+			2: {{
+				Event:     externalGithub.Ptr("closed"),
+				Actor:     &externalGithub.User{Login: externalGithub.Ptr("otheruser")},
+				CreatedAt: &externalGithub.Timestamp{Time: closedAt},
+			}},
+		},
+	}
+	repository := &GitHubAPIRepository{issues: issues, username: "testuser"}
+
+	for number, expected := range map[int]bool{1: true, 2: false} {
+		closed, err := repository.isClosedByUser(context.Background(), "testorg", "repo1", number, testTimeRange())
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if closed != expected {
+			t.Errorf("Expected PR #%d closed by user to be %v, got %v", number, expected, closed)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_FakeRepositoryMetadata(t *testing.T) {
+	repositories := &fakeRepositoriesService{
+		repositories: map[string]*externalGithub.Repository{
+			"testorg/old": {Archived: externalGithub.Ptr(true)},
+		},
+	}
+	repository := &GitHubAPIRepository{repositories: repositories, username: "testuser"}
+
+	for i := 0; i < 2; i++ {
+		metadata, err := repository.GetRepositoryMetadata(context.Background(), "testorg", "old")
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if !metadata.Archived || metadata.Disabled {
+			t.Errorf("Expected an archived repository, got %+v", metadata)
+		}
+	}
+	if repositories.gets != 1 {
+		t.Errorf("Expected the metadata to be fetched once, got %d fetches", repositories.gets)
+	}
+}
+
+func TestGitHubAPIRepository_RepositoryMetadataConcurrent(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	repositories := &fakeRepositoriesService{
+		repositories: map[string]*externalGithub.Repository{
+			"testorg/slow": {Name: externalGithub.Ptr("slow")},
+			"testorg/fast": {Name: externalGithub.Ptr("fast")},
+		},
+		onGet: func(repo string) {
+			if repo == "slow" {
+				close(started)
+				<-release
+			}
+		},
+	}
+	repository := &GitHubAPIRepository{repositories: repositories, username: "testuser"}
+
+	slow := make(chan error, 1)
+	go func() {
+		_, err := repository.GetRepositoryMetadata(context.Background(), "testorg", "slow")
+		slow <- err
+	}()
+	<-started
+
+	// Another repository's lookup does not wait for the slow one
+	fast := make(chan error, 1)
+	go func() {
+		_, err := repository.GetRepositoryMetadata(context.Background(), "testorg", "fast")
+		fast <- err
+	}()
+	select {
+	case err := <-fast:
+		if err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the lookup not to wait for another repository's")
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+}
+
+func TestGitHubAPIRepository_PendingReview(t *testing.T) {
+	search := &fakeSearchService{
+		issues: []*externalGithub.Issue{
+			{
+				Number: externalGithub.Ptr(1),
+				Title:  externalGithub.Ptr("Add feature"),
+				State:  externalGithub.Ptr("open"),
+				User:   &externalGithub.User{Login: externalGithub.Ptr("otheruser")},
+			},
+		},
+	}
+	pullRequests := &fakePullRequestsService{
+		reviews: map[int][]*externalGithub.PullRequestReview{
+			1: {{
+				ID:    externalGithub.Ptr(int64(10)),
+				State: externalGithub.Ptr(ReviewStatePending),
+				User:  &externalGithub.User{Login: externalGithub.Ptr("testuser")},
+			}},
+		},
+	}
+	repository := &GitHubAPIRepository{search: search, pullRequests: pullRequests, username: "testuser"}
+
+	options := DefaultQueryOptions()
+	options.IncludeAuthored = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(prs) != 1 || !prs[0].HasPendingReview {
+		t.Fatalf("Expected a pending review to be detected, got %+v", prs)
+	}
+
+	report := &ActivityReport{
+		TimeRange:    testTimeRange(),
+		User:         User{Username: "testuser"},
+		Repositories: []Repository{{Name: "repo1", Organization: "testorg", PullRequests: prs}},
+	}
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "pending review in progress") {
+		t.Errorf("Expected a pending review note, got:\n%s", content.Content)
+	}
+}
+
+func TestGitHubAPIRepository_LatestReviewPerAuthor(t *testing.T) {
+	search := &fakeSearchService{
+		issues: []*externalGithub.Issue{
+			{
+				Number: externalGithub.Ptr(1),
+				Title:  externalGithub.Ptr("Add feature"),
+				State:  externalGithub.Ptr("open"),
+				User:   &externalGithub.User{Login: externalGithub.Ptr("otheruser")},
+			},
+		},
+	}
+	commentedAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	pullRequests := &fakePullRequestsService{
+		reviews: map[int][]*externalGithub.PullRequestReview{
+			1: {
+				{
+					ID:          externalGithub.Ptr(int64(10)),
+					State:       externalGithub.Ptr("COMMENTED"),
+					User:        &externalGithub.User{Login: externalGithub.Ptr("testuser")},
+					SubmittedAt: &externalGithub.Timestamp{Time: commentedAt},
+				},
+				{
+					ID:          externalGithub.Ptr(int64(11)),
+					State:       externalGithub.Ptr("APPROVED"),
+					User:        &externalGithub.User{Login: externalGithub.Ptr("testuser")},
+					SubmittedAt: &externalGithub.Timestamp{Time: commentedAt.Add(2 * time.Hour)},
+				},
+			},
+		},
+	}
+	repository := &GitHubAPIRepository{search: search, pullRequests: pullRequests, username: "testuser"}
+
+	for _, latestOnly := range []bool{false, true} {
+		options := DefaultQueryOptions()
+		options.IncludeAuthored = false
+		options.IncludeCommits = false
+		options.IncludeComments = false
+		options.LatestReviewPerAuthor = latestOnly
+		prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if len(prs) != 1 {
+			t.Fatalf("Expected one reviewed PR, got %+v", prs)
+		}
+
+		reviews := prs[0].Reviews
+		if !latestOnly {
+			if len(reviews) != 2 {
+				t.Errorf("Expected both reviews without the option, got %+v", reviews)
+			}
+			continue
+		}
+		if len(reviews) != 1 || reviews[0].State != "APPROVED" {
+			t.Errorf("Expected only the approval to be kept, got %+v", reviews)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_UseAuthorDate(t *testing.T) {
+	inRange := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	outOfRange := inRange.AddDate(0, 0, -7)
+
+	// rebased was written in range but rebased later, so its committer date
+	// falls after the range; picked was written a week earlier and
+	// cherry-picked in range
+	rebased := fakeCommit("aaa", "Rebased change", inRange.AddDate(0, 0, 7))
+	rebased.Commit.Author = &externalGithub.CommitAuthor{Date: &externalGithub.Timestamp{Time: inRange}}
+	picked := fakeCommit("bbb", "Cherry-picked change", inRange)
+	picked.Commit.Author = &externalGithub.CommitAuthor{Date: &externalGithub.Timestamp{Time: outOfRange}}
+
+	pullRequests := &fakePullRequestsService{
+		commits: map[int][]*externalGithub.RepositoryCommit{1: {rebased, picked}},
+	}
+	repository := &GitHubAPIRepository{pullRequests: pullRequests, username: "testuser"}
+
+	for useAuthorDate, expected := range map[bool]string{false: "bbb", true: "aaa"} {
+		options := DefaultQueryOptions()
+		options.UseAuthorDate = useAuthorDate
+		commits, err := repository.getCommits(context.Background(), "testorg", "repo1", 1, testTimeRange(), options)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if len(commits) != 1 || commits[0].SHA != expected {
+			t.Errorf("Expected only commit %s with UseAuthorDate=%v, got %+v", expected, useAuthorDate, commits)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_ReviewStates(t *testing.T) {
+	search := &fakeSearchService{
+		issues: []*externalGithub.Issue{
+			{Number: externalGithub.Ptr(1), Title: externalGithub.Ptr("Commented"), State: externalGithub.Ptr("open"), User: &externalGithub.User{Login: externalGithub.Ptr("otheruser")}},
+			{Number: externalGithub.Ptr(2), Title: externalGithub.Ptr("Approved"), State: externalGithub.Ptr("open"), User: &externalGithub.User{Login: externalGithub.Ptr("otheruser")}},
+		},
+	}
+	submittedAt := &externalGithub.Timestamp{Time: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)}
+	review := func(id int64, state string) *externalGithub.PullRequestReview {
+		return &externalGithub.PullRequestReview{
+			ID:          externalGithub.Ptr(id),
+			State:       externalGithub.Ptr(state),
+			User:        &externalGithub.User{Login: externalGithub.Ptr("testuser")},
+			SubmittedAt: submittedAt,
+		}
+	}
+	pullRequests := &fakePullRequestsService{
+		reviews: map[int][]*externalGithub.PullRequestReview{
+			1: {review(10, "COMMENTED")},
+			2: {review(20, "APPROVED")},
+		},
+	}
+	repository := &GitHubAPIRepository{search: search, pullRequests: pullRequests, username: "testuser"}
+
+	options := DefaultQueryOptions()
+	options.IncludeAuthored = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.ReviewStates = []string{"APPROVED", "CHANGES_REQUESTED"}
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("Expected two reviewed PRs, got %+v", prs)
+	}
+
+	for _, pr := range prs {
+		switch pr.Number {
+		case 1:
+			if len(pr.Reviews) != 0 {
+				t.Errorf("Expected the COMMENTED review to be excluded, got %+v", pr.Reviews)
+			}
+		case 2:
+			if len(pr.Reviews) != 1 || pr.Reviews[0].State != "APPROVED" {
+				t.Errorf("Expected the APPROVED review to be kept, got %+v", pr.Reviews)
+			}
+		}
+	}
+}
+
+func TestGitHubAPIRepository_ResolveDisplayNames(t *testing.T) {
+	search := &fakeSearchService{
+		issues: []*externalGithub.Issue{
+			{Number: externalGithub.Ptr(1), Title: externalGithub.Ptr("Named"), State: externalGithub.Ptr("open"), User: &externalGithub.User{Login: externalGithub.Ptr("octocat")}},
+			{Number: externalGithub.Ptr(2), Title: externalGithub.Ptr("Unnamed"), State: externalGithub.Ptr("open"), User: &externalGithub.User{Login: externalGithub.Ptr("noname")}},
+			{Number: externalGithub.Ptr(3), Title: externalGithub.Ptr("Named again"), State: externalGithub.Ptr("open"), User: &externalGithub.User{Login: externalGithub.Ptr("octocat")}},
+		},
+	}
+	users := &fakeUsersService{
+		users: map[string]*externalGithub.User{
+			"octocat": {Login: externalGithub.Ptr("octocat"), Name: externalGithub.Ptr("Mona Lisa")},
+			"noname":  {Login: externalGithub.Ptr("noname")},
+		},
+	}
+	repository := &GitHubAPIRepository{search: search, pullRequests: &fakePullRequestsService{}, users: users, username: "testuser"}
+
+	options := DefaultQueryOptions()
+	options.IncludeAuthored = false
+	options.IncludeCommits = false
+	options.IncludeComments = false
+	options.ResolveDisplayNames = true
+	prs, err := repository.GetPullRequests(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	expected := map[int]string{1: "Mona Lisa", 2: "noname", 3: "Mona Lisa"}
+	for _, pr := range prs {
+		if name := pr.AuthorDisplayName(); name != expected[pr.Number] {
+			t.Errorf("Expected PR #%d author %q, got %q", pr.Number, expected[pr.Number], name)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_DirectCommits(t *testing.T) {
+	pushedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	repositories := &fakeRepositoriesService{
+		listed: []*externalGithub.RepositoryCommit{
+			fakeCommit("abc1234", "Hotfix config", pushedAt),
+			fakeCommit("def5678", "Add login form", pushedAt.Add(time.Hour)),
+			fakeCommit("0a1b2c3", "Bump version", pushedAt.Add(2*time.Hour)),
+		},
+	}
+	pullRequests := &fakePullRequestsService{
+		withCommit: map[string][]*externalGithub.PullRequest{
+			"def5678": {{Number: externalGithub.Ptr(42)}},
+		},
+	}
+	repository := &GitHubAPIRepository{repositories: repositories, pullRequests: pullRequests, username: "testuser"}
+
+	// One commit per page, so every page is listed
+	options := DefaultQueryOptions()
+	options.MaxResults = 1
+	commits, err := repository.GetDirectCommits(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	listOptions := repositories.listOptions
+	if listOptions == nil || listOptions.Author != "testuser" || !listOptions.Since.Equal(testTimeRange().Start) || !listOptions.Until.Equal(testTimeRange().End) {
+		t.Errorf("Expected commits listed by author within the time range, got %+v", listOptions)
+	}
+	if len(commits) != 2 || commits[0].SHA != "abc1234" || !commits[0].Timestamp.Equal(pushedAt) || commits[1].SHA != "0a1b2c3" {
+		t.Fatalf("Expected the listed commits without the pull request's, got %+v", commits)
+	}
+
+	report := &ActivityReport{
+		TimeRange:    testTimeRange(),
+		User:         User{Username: "testuser"},
+		Repositories: []Repository{{Name: "repo1", Organization: "testorg", DirectCommits: commits}},
+	}
+	for _, formatter := range []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()} {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+		if !strings.Contains(content.Content, "Direct Commits") || !strings.Contains(content.Content, "Hotfix config") {
+			t.Errorf("Expected a direct commits section, got:\n%s", content.Content)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_AssignedIssues(t *testing.T) {
+	search := &fakeSearchService{
+		issues: []*externalGithub.Issue{{
+			Number:  externalGithub.Ptr(7),
+			Title:   externalGithub.Ptr("Migrate the billing job"),
+			HTMLURL: externalGithub.Ptr("https://github.com/testorg/repo1/issues/7"),
+			State:   externalGithub.Ptr("open"),
+			Labels:  []*externalGithub.Label{{Name: externalGithub.Ptr("backend")}},
+		}},
+	}
+	repository := &GitHubAPIRepository{search: search, username: "testuser"}
+
+	options := DefaultQueryOptions()
+	if _, err := repository.GetAssignedIssues(context.Background(), "testorg", "repo1", testTimeRange(), options); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	options.AssignedIssuesInRange = true
+	issues, err := repository.GetAssignedIssues(context.Background(), "testorg", "repo1", testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if search.queries[0] != "is:issue is:open assignee:testuser repo:testorg/repo1" {
+		t.Errorf("Expected open assigned issues regardless of the time range, got %q", search.queries[0])
+	}
+	if !strings.HasSuffix(search.queries[1], " updated:2023-01-01..2023-01-02") {
+		t.Errorf("Expected the in-range query to be limited to the time range, got %q", search.queries[1])
+	}
+	if len(issues) != 1 || issues[0].Number != 7 || len(issues[0].Labels) != 1 {
+		t.Fatalf("Expected the assigned issue, got %+v", issues)
+	}
+
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return nil, nil
+		},
+		MockGetAssignedIssues: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]Issue, error) {
+			return issues, nil
+		},
+	}
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+	config.QueryOptions.IncludeAssignedIssues = true
+	report, err := NewActivityService(mockRepo, config).GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	for _, formatter := range []ReportFormatter{NewMarkdownFormatter(), NewHTMLFormatter()} {
+		content, err := formatter.Format(report)
+		if err != nil {
+			t.Fatalf("Error formatting report: %v", err)
+		}
+		if !strings.Contains(content.Content, "In Progress") || !strings.Contains(content.Content, "Migrate the billing job") {
+			t.Errorf("Expected an in progress section with the assigned issue, got:\n%s", content.Content)
+		}
+	}
+}
+
+func TestActivityService_SearchForbidden(t *testing.T) {
+	search := &fakeSearchService{
+		err: &externalGithub.ErrorResponse{
+			Response: &http.Response{StatusCode: http.StatusForbidden, Request: &http.Request{}},
+			Message:  "Resource not accessible by personal access token",
+		},
+	}
+	users := &fakeUsersService{
+		users: map[string]*externalGithub.User{"testuser": {Login: externalGithub.Ptr("testuser")}},
+	}
+	repository := &GitHubAPIRepository{search: search, users: users, username: "testuser"}
+
+	for _, repositories := range [][]string{{"repo1", "repo2", "repo3"}, {"repo1"}} {
+		config := &GitHubConfig{
+			Username:     "testuser",
+			Organization: "testorg",
+			Repositories: repositories,
+			QueryOptions: DefaultQueryOptions(),
+		}
+		report, err := NewActivityService(repository, config).GetActivityReport(plug.TimeRange{
+			Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+
+		if report != nil || !errors.Is(err, ErrSearchForbidden) {
+			t.Fatalf("Expected a single search access error without a report, got %v and %+v", err, report)
+		}
+		if !errors.Is(err, ErrAuth) || !strings.Contains(err.Error(), "read access") {
+			t.Errorf("Expected an actionable authentication error, got %v", err)
+		}
+	}
+}
+
+func TestGitHubAPIRepository_ConversationComments(t *testing.T) {
+	commentedAt := &externalGithub.Timestamp{Time: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)}
+	user := &externalGithub.User{Login: externalGithub.Ptr("testuser")}
+	reviewComment := &externalGithub.PullRequestComment{
+		ID:        externalGithub.Ptr(int64(1)),
+		User:      user,
+		Body:      externalGithub.Ptr("Rename this"),
+		Path:      externalGithub.Ptr("main.go"),
+		CreatedAt: commentedAt,
+	}
+	pullRequests := &fakePullRequestsService{
+		// The listing repeats the comment, as overlapping pages can
+		comments: map[int][]*externalGithub.PullRequestComment{1: {reviewComment, reviewComment}},
+	}
+	issues := &fakeIssuesService{
+		comments: map[int][]*externalGithub.IssueComment{1: {
+			{ID: externalGithub.Ptr(int64(2)), User: user, Body: externalGithub.Ptr("Ready for another look"), CreatedAt: commentedAt},
+			{ID: externalGithub.Ptr(int64(3)), User: &externalGithub.User{Login: externalGithub.Ptr("otheruser")}, Body: externalGithub.Ptr("Thanks"), CreatedAt: commentedAt},
+		}},
+	}
+	repository := &GitHubAPIRepository{pullRequests: pullRequests, issues: issues, username: "testuser"}
+
+	options := DefaultQueryOptions()
+	options.IncludeConversationComments = true
+	comments, err := repository.getComments(context.Background(), "testorg", "repo1", 1, testTimeRange(), options)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("Expected the review comment once and the user's conversation comment, got %+v", comments)
+	}
+	if comments[0].ID != 1 || comments[0].Kind != CommentKindReview {
+		t.Errorf("Expected the review comment first, got %+v", comments[0])
+	}
+	if comments[1].ID != 2 || comments[1].Kind != CommentKindConversation {
+		t.Errorf("Expected the conversation comment tagged as such, got %+v", comments[1])
+	}
+
+	// The JSON output, which does not group comments into threads, holds
+	// each comment once
+	report := createTestActivityReport()
+	report.Repositories[0].PullRequests[0].Comments = comments
+	content, err := NewJSONFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if count := strings.Count(content.Content, `"body": "Rename this"`); count != 1 {
+		t.Errorf("Expected the review comment once in the JSON output, got %d times", count)
+	}
+	if !strings.Contains(content.Content, `"kind": "conversation"`) {
+		t.Errorf("Expected the conversation comment kind in the JSON output, got:\n%s", content.Content)
+	}
+}
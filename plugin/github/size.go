@@ -0,0 +1,104 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pull request size categories, from smallest to largest
+const (
+	SizeXS = "XS"
+	SizeS  = "S"
+	SizeM  = "M"
+	SizeL  = "L"
+	SizeXL = "XL"
+)
+
+var sizeCategories = []string{SizeXS, SizeS, SizeM, SizeL, SizeXL}
+
+// SizeThresholds are the numbers of changed lines, additions plus
+// deletions, at which a pull request moves up from XS to S, S to M, M to L,
+// and L to XL
+type SizeThresholds [4]int
+
+// DefaultSizeThresholds are used when no thresholds are configured
+var DefaultSizeThresholds = SizeThresholds{10, 30, 100, 500}
+
+// ParseSizeThresholds parses four increasing, comma-separated line counts,
+// such as "10,30,100,500"
+func ParseSizeThresholds(value string) (SizeThresholds, error) {
+	var thresholds SizeThresholds
+	
+	parts := strings.Split(value, ",")
+	if len(parts) != len(thresholds) {
+		return SizeThresholds{}, fmt.Errorf("expected %d comma-separated line counts, got %q", len(thresholds), value)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return SizeThresholds{}, fmt.Errorf("invalid line count %q", part)
+		}
+		if i > 0 && n <= thresholds[i-1] {
+			return SizeThresholds{}, fmt.Errorf("line counts must increase, got %q", value)
+		}
+		thresholds[i] = n
+	}
+	
+	return thresholds, nil
+}
+
+// Category returns the size category of a pull request changing the given
+// number of lines. Zero thresholds use DefaultSizeThresholds.
+func (t SizeThresholds) Category(changedLines int) string {
+	if t == (SizeThresholds{}) {
+		t = DefaultSizeThresholds
+	}
+	for i, threshold := range t {
+		if changedLines < threshold {
+			return sizeCategories[i]
+		}
+	}
+	return SizeXL
+}
+
+// includeSize reports whether pull requests need their size fetched, which
+// filtering by size implies
+func (o QueryOptions) includeSize() bool {
+	return o.IncludeSize || o.MinSizeCategory != ""
+}
+
+// ParseSizeCategory validates a size category, ignoring case
+func ParseSizeCategory(value string) (string, error) {
+	category := strings.ToUpper(strings.TrimSpace(value))
+	if sizeRank(category) < 0 {
+		return "", fmt.Errorf("unknown size category %q, expected one of %s", value, strings.Join(sizeCategories, ", "))
+	}
+	return category, nil
+}
+
+// sizeRank returns the position of a size category from smallest to largest,
+// or -1 for an unknown or empty category
+func sizeRank(category string) int {
+	for i, c := range sizeCategories {
+		if c == category {
+			return i
+		}
+	}
+	return -1
+}
+
+// filterBySize keeps the pull requests at least as large as minCategory.
+// Pull requests whose size could not be fetched are kept, since they are
+// not known to be smaller.
+func filterBySize(pullRequests []PullRequest, minCategory string) []PullRequest {
+	minRank := sizeRank(minCategory)
+	
+	filtered := make([]PullRequest, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		if pr.SizeCategory == "" || sizeRank(pr.SizeCategory) >= minRank {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
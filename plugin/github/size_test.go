@@ -0,0 +1,96 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestSizeThresholds_Category(t *testing.T) {
+	tests := []struct {
+		changedLines int
+		expected     string
+	}{
+		{0, SizeXS},
+		{9, SizeXS},
+		{10, SizeS},
+		{99, SizeM},
+		{100, SizeL},
+		{500, SizeXL},
+	}
+	for _, test := range tests {
+		if got := (SizeThresholds{}).Category(test.changedLines); got != test.expected {
+			t.Errorf("Expected %d changed lines to be %s, got %s", test.changedLines, test.expected, got)
+		}
+	}
+
+	thresholds, err := ParseSizeThresholds("5, 20, 50, 200")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if got := thresholds.Category(60); got != SizeL {
+		t.Errorf("Expected 60 changed lines to be L with custom thresholds, got %s", got)
+	}
+
+	for _, invalid := range []string{"10,30,100", "10,30,30,500", "a,b,c,d"} {
+		if _, err := ParseSizeThresholds(invalid); err == nil {
+			t.Errorf("Expected an error for thresholds %q", invalid)
+		}
+	}
+	if _, err := ParseSizeCategory("huge"); err == nil {
+		t.Error("Expected an error for an unknown size category")
+	}
+}
+
+func TestActivityService_MinSizeCategory(t *testing.T) {
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			if !options.includeSize() {
+				t.Error("Expected sizes to be fetched when filtering by size")
+			}
+			prs := []PullRequest{
+				{Number: 1, Title: "Typo fix", State: "open", IsAuthored: true, Additions: 3, Deletions: 1},
+				{Number: 2, Title: "New engine", State: "open", IsAuthored: true, Additions: 400, Deletions: 50},
+			}
+			for i := range prs {
+				prs[i].SizeCategory = options.SizeThresholds.Category(prs[i].Additions + prs[i].Deletions)
+			}
+			return prs, nil
+		},
+	}
+
+	queryOptions := DefaultQueryOptions()
+	queryOptions.MinSizeCategory = SizeL
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"testrepo"},
+		QueryOptions: queryOptions,
+	}
+	service := NewActivityService(mockRepo, config)
+	report, err := service.GetActivityReport(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	prs := report.Repositories[0].PullRequests
+	if len(prs) != 1 || prs[0].Number != 2 {
+		t.Fatalf("Expected only the large pull request, got %+v", prs)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Error formatting report: %v", err)
+	}
+	if !strings.Contains(content.Content, "#### [#2] New engine (open) `L`") {
+		t.Errorf("Expected a size badge after the title, got:\n%s", content.Content)
+	}
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	plug "github.com/iures/daivplug"
+)
+
+// TeamReport holds the individual activity reports of several users over the
+// same time range, keeping each user's activity attributed to them
+type TeamReport struct {
+	TimeRange TimeRange         `json:"time_range"`
+	Members   []*ActivityReport `json:"members"`
+}
+
+// GetTeamReport runs a report per username, in order, through the same
+// client. The repository must implement UserScopedRepository. Checkpoints
+// are not used, as they track a single user's report.
+func (s *ActivityService) GetTeamReport(usernames []string, pluginTimeRange plug.TimeRange) (*TeamReport, error) {
+	if len(usernames) == 0 {
+		return nil, fmt.Errorf("at least one username is required")
+	}
+
+	timeRange, err := FromPluginTimeRange(pluginTimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped, ok := s.repository.(UserScopedRepository)
+	if !ok {
+		return nil, fmt.Errorf("team reports are not supported by this repository")
+	}
+
+	team := &TeamReport{TimeRange: timeRange}
+	for _, username := range usernames {
+		config := *s.config
+		config.Username = username
+		config.CheckpointFile = ""
+		config.Resume = false
+
+		report, err := NewActivityService(scoped.ForUser(username), &config).GetActivityReport(pluginTimeRange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get activity for %s: %w", username, err)
+		}
+		team.Members = append(team.Members, report)
+	}
+
+	return team, nil
+}
+
+// FormatTeamMarkdown renders a team report as a digest with a section per
+// user, each holding their report as rendered with the options. Headings of
+// the individual reports are demoted a level to nest under their user.
+func FormatTeamMarkdown(team *TeamReport, options FormatterOptions) (string, error) {
+	// The digest as a whole is encoded once at the end
+	memberOptions := options
+	memberOptions.OmitHeader = true
+	memberOptions.ByteOrderMark = false
+	memberOptions.CRLF = false
+	formatter := &MarkdownFormatter{Options: memberOptions}
+
+	var sb strings.Builder
+	sb.WriteString("# Team Digest\n\n")
+	sb.WriteString(fmt.Sprintf("**Time Range:** %s to %s\n\n",
+		formatReportDate(team.TimeRange.Start, options.Locale),
+		formatReportDate(team.TimeRange.End, options.Locale)))
+
+	for _, member := range team.Members {
+		content, err := formatter.Format(member)
+		if err != nil {
+			return "", fmt.Errorf("failed to format activity for %s: %w", member.User.Username, err)
+		}
+
+		sb.WriteString(fmt.Sprintf("## %s\n\n", member.User.Username))
+		sb.WriteString(demoteMarkdownHeadings(strings.TrimRight(content.Content, "\n")))
+		sb.WriteString("\n\n")
+	}
+
+	return options.encodeText(sb.String()), nil
+}
+
+// demoteMarkdownHeadings turns each heading into one a level deeper
+func demoteMarkdownHeadings(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "##") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
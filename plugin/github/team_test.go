@@ -0,0 +1,132 @@
+package github
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+// newUserMock returns a repository reporting a single authored pull request
+// for the user
+func newUserMock(username string, title string) *MockGitHubRepository {
+	return &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: username}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			return []PullRequest{{Number: 1, Title: title, State: "open", Author: username, IsAuthored: true}}, nil
+		},
+	}
+}
+
+func TestActivityService_GetTeamReport(t *testing.T) {
+	var scopedTo []string
+	mockRepo := &MockGitHubRepository{
+		MockForUser: func(username string) GitHubRepository {
+			scopedTo = append(scopedTo, username)
+			return newUserMock(username, username+"'s change")
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "lead",
+		Organization: "testorg",
+		Repositories: []string{"testrepo"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+	team, err := NewActivityService(mockRepo, config).GetTeamReport([]string{"alice", "bob"}, plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if strings.Join(scopedTo, ",") != "alice,bob" {
+		t.Errorf("Expected a report per user in order, got %v", scopedTo)
+	}
+	if config.Username != "lead" {
+		t.Errorf("Expected the service config to be left unchanged, got username %q", config.Username)
+	}
+
+	content, err := FormatTeamMarkdown(team, FormatterOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	aliceSection := strings.Index(content, "## alice\n")
+	bobSection := strings.Index(content, "## bob\n")
+	if aliceSection < 0 || bobSection < aliceSection {
+		t.Fatalf("Expected labeled sections for alice and then bob, got:\n%s", content)
+	}
+	if alice := content[aliceSection:bobSection]; !strings.Contains(alice, "alice's change") || strings.Contains(alice, "bob's change") {
+		t.Errorf("Expected alice's section to hold only alice's activity, got:\n%s", alice)
+	}
+	if bob := content[bobSection:]; !strings.Contains(bob, "bob's change") || strings.Contains(bob, "alice's change") {
+		t.Errorf("Expected bob's section to hold only bob's activity, got:\n%s", bob)
+	}
+	if !strings.Contains(content, "### Repository: testorg/testrepo") {
+		t.Errorf("Expected repository headings nested under each user, got:\n%s", content)
+	}
+}
+
+func TestActivityService_GetTeamReportCached(t *testing.T) {
+	var calls atomic.Int64
+	mockRepo := &MockGitHubRepository{
+		MockForUser: func(username string) GitHubRepository {
+			scoped := newUserMock(username, username+"'s change")
+			getPullRequests := scoped.MockGetPullRequests
+			scoped.MockGetPullRequests = func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+				calls.Add(1)
+				return getPullRequests(org, repo, timeRange, options)
+			}
+			return scoped
+		},
+	}
+	repository := newCachingRepository(mockRepo, "lead", t.TempDir(), time.Hour)
+
+	config := &GitHubConfig{
+		Username:     "lead",
+		Organization: "testorg",
+		Repositories: []string{"testrepo"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+	run := func() string {
+		t.Helper()
+		team, err := NewActivityService(repository, config).GetTeamReport([]string{"alice", "bob"}, plug.TimeRange{
+			Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		content, err := FormatTeamMarkdown(team, FormatterOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		return content
+	}
+
+	first := run()
+	if calls.Load() != 2 {
+		t.Fatalf("Expected the first run to fetch each user's pull requests, got %d API calls", calls.Load())
+	}
+
+	calls.Store(0)
+	second := run()
+	if calls.Load() != 0 {
+		t.Errorf("Expected the second run to be served from the cache, got %d API calls", calls.Load())
+	}
+	if second != first {
+		t.Errorf("Expected the cached report to match the first.\nFirst:\n%s\nSecond:\n%s", first, second)
+	}
+
+	// Each user's results are cached separately
+	bobSection := strings.Index(second, "## bob\n")
+	if bobSection < 0 || !strings.Contains(second[bobSection:], "bob's change") || strings.Contains(second[bobSection:], "alice's change") {
+		t.Errorf("Expected bob's cached section to hold only bob's activity, got:\n%s", second)
+	}
+}
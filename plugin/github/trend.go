@@ -0,0 +1,133 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+// trendBucketSize is the length of each bucket in an activity trend
+const trendBucketSize = 7 * 24 * time.Hour
+
+// sparkBlocks are the bar heights used to draw a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// TrendBucket holds the activity counts of a single week of a trend
+type TrendBucket struct {
+	TimeRange    TimeRange `json:"time_range"`
+	PullRequests int       `json:"pull_requests"`
+	Commits      int       `json:"commits"`
+	Reviews      int       `json:"reviews"`
+	Comments     int       `json:"comments"`
+}
+
+// Total returns the number of commits, reviews, and comments in the bucket
+func (b TrendBucket) Total() int {
+	return b.Commits + b.Reviews + b.Comments
+}
+
+// ActivityTrend is the user's activity over a longer range, split into
+// weekly buckets
+type ActivityTrend struct {
+	TimeRange TimeRange     `json:"time_range"`
+	User      User          `json:"user"`
+	Buckets   []TrendBucket `json:"buckets"`
+}
+
+// GetActivityTrend splits the time range into weekly buckets, starting at the
+// beginning of the range, and reports the activity counts of each one. The
+// last bucket is shortened to end with the range.
+func (s *ActivityService) GetActivityTrend(pluginTimeRange plug.TimeRange) (*ActivityTrend, error) {
+	timeRange, err := FromPluginTimeRange(pluginTimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &ActivityTrend{TimeRange: timeRange}
+	for _, bucketRange := range weeklyBuckets(timeRange) {
+		report, err := s.GetActivityReport(bucketRange.ToPluginTimeRange())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get activity for %s: %w", bucketRange.Label(), err)
+		}
+
+		trend.User = report.User
+		trend.Buckets = append(trend.Buckets, countTrendBucket(bucketRange, report))
+	}
+
+	return trend, nil
+}
+
+// weeklyBuckets splits the time range into consecutive weeks
+func weeklyBuckets(timeRange TimeRange) []TimeRange {
+	var buckets []TimeRange
+	for start := timeRange.Start; start.Before(timeRange.End); start = start.Add(trendBucketSize) {
+		end := start.Add(trendBucketSize)
+		if end.After(timeRange.End) {
+			end = timeRange.End
+		}
+		buckets = append(buckets, TimeRange{Start: start, End: end})
+	}
+	return buckets
+}
+
+// countTrendBucket counts the activity in a bucket's report
+func countTrendBucket(timeRange TimeRange, report *ActivityReport) TrendBucket {
+	bucket := TrendBucket{TimeRange: timeRange}
+	for _, repo := range report.Repositories {
+		bucket.PullRequests += len(repo.PullRequests)
+		for _, pr := range repo.PullRequests {
+			bucket.Commits += len(pr.Commits)
+			bucket.Reviews += len(pr.Reviews)
+			bucket.Comments += len(pr.Comments)
+		}
+	}
+	return bucket
+}
+
+// Sparkline draws the total activity of each bucket as a bar, scaled to the
+// busiest week
+func (t *ActivityTrend) Sparkline() string {
+	max := 0
+	for _, bucket := range t.Buckets {
+		if bucket.Total() > max {
+			max = bucket.Total()
+		}
+	}
+
+	var sb strings.Builder
+	for _, bucket := range t.Buckets {
+		level := 0
+		if max > 0 {
+			level = bucket.Total() * (len(sparkBlocks) - 1) / max
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
+
+// FormatTrendMarkdown renders an activity trend as a sparkline and a table
+// with one row per week
+func FormatTrendMarkdown(trend *ActivityTrend) string {
+	var sb strings.Builder
+
+	sb.WriteString("# GitHub Activity Trend\n\n")
+	sb.WriteString(fmt.Sprintf("**Time Range:** %s\n\n", trend.TimeRange.Label()))
+	sb.WriteString(fmt.Sprintf("**User:** %s\n\n", trend.User.Username))
+	sb.WriteString(fmt.Sprintf("**Activity:** %s\n\n", trend.Sparkline()))
+
+	sb.WriteString("| Week | Pull Requests | Commits | Reviews | Comments | Total |\n")
+	sb.WriteString("| --- | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, bucket := range trend.Buckets {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %d | %d |\n",
+			bucket.TimeRange.Label(),
+			bucket.PullRequests,
+			bucket.Commits,
+			bucket.Reviews,
+			bucket.Comments,
+			bucket.Total()))
+	}
+
+	return sb.String()
+}
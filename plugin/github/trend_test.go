@@ -0,0 +1,97 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestActivityService_GetActivityTrend(t *testing.T) {
+	weekStarts := []time.Time{
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 9, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 16, 0, 0, 0, 0, time.UTC),
+	}
+	// Number of commits made in each week
+	commitsPerWeek := map[time.Time]int{weekStarts[0]: 3, weekStarts[1]: 0, weekStarts[2]: 1}
+
+	userLookups := 0
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			userLookups++
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange TimeRange, options QueryOptions) ([]PullRequest, error) {
+			count, ok := commitsPerWeek[timeRange.Start]
+			if !ok {
+				t.Errorf("Unexpected bucket %s", timeRange.Label())
+			}
+			if count == 0 {
+				return nil, nil
+			}
+
+			pr := PullRequest{Number: 1, Title: "Test PR", IsAuthored: true}
+			for i := 0; i < count; i++ {
+				pr.Commits = append(pr.Commits, Commit{Message: "Work", Timestamp: timeRange.Start.Add(time.Hour)})
+			}
+			return []PullRequest{pr}, nil
+		},
+	}
+
+	config := &GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: DefaultQueryOptions(),
+	}
+
+	service := NewActivityService(mockRepo, config)
+	trend, err := service.GetActivityTrend(plug.TimeRange{
+		Start: weekStarts[0],
+		End:   weekStarts[2].Add(7 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(trend.Buckets) != 3 {
+		t.Fatalf("Expected 3 weekly buckets, got %d", len(trend.Buckets))
+	}
+	for i, bucket := range trend.Buckets {
+		if !bucket.TimeRange.Start.Equal(weekStarts[i]) {
+			t.Errorf("Expected bucket %d to start %s, got %s", i, weekStarts[i], bucket.TimeRange.Start)
+		}
+		if bucket.Commits != commitsPerWeek[weekStarts[i]] {
+			t.Errorf("Expected %d commits in bucket %d, got %d", commitsPerWeek[weekStarts[i]], i, bucket.Commits)
+		}
+	}
+	if userLookups != 1 {
+		t.Errorf("Expected the user to be looked up once, got %d lookups", userLookups)
+	}
+
+	if sparkline := trend.Sparkline(); sparkline != "█▁▃" {
+		t.Errorf("Expected sparkline %q, got %q", "█▁▃", sparkline)
+	}
+
+	content := FormatTrendMarkdown(trend)
+	if !strings.Contains(content, "| 2023-01-02 to 2023-01-09 | 1 | 3 | 0 | 0 | 3 |") {
+		t.Errorf("Expected a row for the first week, got:\n%s", content)
+	}
+}
+
+func TestWeeklyBuckets_ShortensLastBucket(t *testing.T) {
+	timeRange := TimeRange{
+		Start: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 12, 0, 0, 0, 0, time.UTC),
+	}
+
+	buckets := weeklyBuckets(timeRange)
+	if len(buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(buckets))
+	}
+	if !buckets[1].End.Equal(timeRange.End) {
+		t.Errorf("Expected the last bucket to end with the range, got %s", buckets[1].End)
+	}
+}
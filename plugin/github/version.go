@@ -0,0 +1,7 @@
+package github
+
+// Version is the version of the daiv-github plugin
+const Version = "0.1.0"
+
+// DefaultUserAgent is the user agent sent to the GitHub API when none is configured
+const DefaultUserAgent = "daiv-github/" + Version
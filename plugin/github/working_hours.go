@@ -0,0 +1,184 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Ways to treat activity outside working hours
+const (
+	// WorkingHoursFilter drops commits, reviews, and comments made outside
+	// working hours
+	WorkingHoursFilter = "filter"
+	
+	// WorkingHoursTag keeps them and marks them as after hours
+	WorkingHoursTag = "tag"
+)
+
+// WorkingHours are the hours of the day, and optionally the days of the
+// week, in which activity is expected. The zero value disables the check.
+type WorkingHours struct {
+	// Start and End of the working day as offsets from midnight. An End
+	// before Start spans midnight, as in a 22:00-06:00 shift.
+	Start time.Duration
+	End   time.Duration
+	
+	// WorkDays are the working days of the week. Empty counts every day.
+	WorkDays []time.Weekday
+	
+	// Location is the timezone the hours are in. Nil uses UTC.
+	Location *time.Location
+	
+	// Mode is WorkingHoursFilter or WorkingHoursTag
+	Mode string
+}
+
+// IsZero reports whether no working hours are configured
+func (w WorkingHours) IsZero() bool {
+	return w.Start == w.End
+}
+
+// Contains reports whether t falls within working hours
+func (w WorkingHours) Contains(t time.Time) bool {
+	location := w.Location
+	if location == nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	
+	if len(w.WorkDays) > 0 && !containsWeekday(w.WorkDays, local.Weekday()) {
+		return false
+	}
+	
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	offset := local.Sub(midnight)
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// containsWeekday reports whether day is one of days
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdays maps the short English names of the days of the week
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWorkingHours parses hours such as "09:00-17:30" into the Start and
+// End of WorkingHours
+func ParseWorkingHours(value string) (time.Duration, time.Duration, error) {
+	startValue, endValue, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected HH:MM-HH:MM, got %q", value)
+	}
+	
+	start, err := parseClock(startValue)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(endValue)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("working hours %q start and end at the same time", value)
+	}
+	return start, end, nil
+}
+
+// parseClock parses a time of day such as "09:00" into an offset from
+// midnight
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", strings.TrimSpace(value))
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// ParseWorkDays parses a comma-separated list of days and day ranges, such
+// as "mon-fri" or "mon,wed,fri", into days of the week
+func ParseWorkDays(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		
+		firstName, lastName, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[strings.TrimSpace(firstName)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", strings.TrimSpace(firstName))
+		}
+		if !isRange {
+			days = append(days, first)
+			continue
+		}
+		
+		last, ok := weekdays[strings.TrimSpace(lastName)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", strings.TrimSpace(lastName))
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days = append(days, day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// applyWorkingHours drops the items outside working hours in filter mode,
+// or marks them as after hours in tag mode
+func applyWorkingHours[T any](items []T, hours WorkingHours, timestamp func(T) time.Time, markAfterHours func(*T)) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if hours.Contains(timestamp(item)) {
+			kept = append(kept, item)
+			continue
+		}
+		if hours.Mode == WorkingHoursFilter {
+			continue
+		}
+		markAfterHours(&item)
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// applyWorkingHoursToPullRequest applies working hours to the commits,
+// reviews, and comments of a pull request
+func applyWorkingHoursToPullRequest(pr *PullRequest, hours WorkingHours) {
+	pr.Commits = applyWorkingHoursToCommits(pr.Commits, hours)
+	pr.Reviews = applyWorkingHours(pr.Reviews, hours,
+		func(review Review) time.Time { return review.Timestamp },
+		func(review *Review) { review.AfterHours = true })
+	pr.Comments = applyWorkingHours(pr.Comments, hours,
+		func(comment Comment) time.Time { return comment.Timestamp },
+		func(comment *Comment) { comment.AfterHours = true })
+}
+
+// applyWorkingHoursToCommits applies working hours to commits
+func applyWorkingHoursToCommits(commits []Commit, hours WorkingHours) []Commit {
+	return applyWorkingHours(commits, hours,
+		func(commit Commit) time.Time { return commit.Timestamp },
+		func(commit *Commit) { commit.AfterHours = true })
+}
@@ -0,0 +1,148 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	plug "github.com/iures/daivplug"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	start, end, err := ParseWorkingHours("09:00-17:30")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if start != 9*time.Hour || end != 17*time.Hour+30*time.Minute {
+		t.Errorf("Expected 9h-17h30m, got %v-%v", start, end)
+	}
+
+	for _, value := range []string{"09:00", "9am-5pm", "09:00-09:00"} {
+		if _, _, err := ParseWorkingHours(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestParseWorkDays(t *testing.T) {
+	days, err := ParseWorkDays("mon-fri")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(days) != 5 || days[0] != time.Monday || days[4] != time.Friday {
+		t.Errorf("Expected Monday to Friday, got %v", days)
+	}
+
+	days, err = ParseWorkDays("Sat-Sun, wed")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(days) != 3 || days[0] != time.Saturday || days[1] != time.Sunday || days[2] != time.Wednesday {
+		t.Errorf("Expected Saturday, Sunday and Wednesday, got %v", days)
+	}
+
+	if _, err := ParseWorkDays("mon-funday"); err == nil {
+		t.Error("Expected an error for an unknown day")
+	}
+}
+
+func TestWorkingHours_Contains(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	hours := WorkingHours{
+		Start:    9 * time.Hour,
+		End:      17 * time.Hour,
+		WorkDays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Location: berlin,
+	}
+
+	tests := []struct {
+		name string
+		time time.Time
+		want bool
+	}{
+		{"during the day", time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC), true},
+		{"before start in local time", time.Date(2023, 1, 2, 7, 30, 0, 0, time.UTC), false},
+		{"at end", time.Date(2023, 1, 2, 16, 0, 0, 0, time.UTC), false},
+		{"on a weekend", time.Date(2023, 1, 7, 10, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := hours.Contains(tt.time); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	overnight := WorkingHours{Start: 22 * time.Hour, End: 6 * time.Hour}
+	if !overnight.Contains(time.Date(2023, 1, 2, 23, 0, 0, 0, time.UTC)) || !overnight.Contains(time.Date(2023, 1, 2, 5, 0, 0, 0, time.UTC)) {
+		t.Error("Expected an overnight shift to span midnight")
+	}
+	if overnight.Contains(time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Expected midday to be outside an overnight shift")
+	}
+}
+
+func TestActivityService_WorkingHours(t *testing.T) {
+	duringWork := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+	afterWork := time.Date(2023, 1, 2, 21, 0, 0, 0, time.UTC)
+	mockRepo := &MockGitHubRepository{
+		MockGetUser: func() (*User, error) {
+			return &User{Username: "testuser"}, nil
+		},
+		MockGetPullRequest: func(org string, repo string, number int, timeRange TimeRange, options QueryOptions) (PullRequest, error) {
+			return PullRequest{
+				Number:     number,
+				Title:      "Test PR",
+				IsAuthored: true,
+				Commits: []Commit{
+					{SHA: "day1234", Message: "Daytime commit", Timestamp: duringWork},
+					{SHA: "night12", Message: "Late commit", Timestamp: afterWork},
+				},
+				Reviews: []Review{{ID: 1, State: "APPROVED", Timestamp: afterWork}},
+			}, nil
+		},
+	}
+
+	timeRange := plug.TimeRange{
+		Start: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	config := &GitHubConfig{
+		Username:       "testuser",
+		QueryOptions:   DefaultQueryOptions(),
+		PullRequestURL: "https://github.com/testorg/testrepo/pull/42",
+		WorkingHours:   WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour, Mode: WorkingHoursFilter},
+	}
+	service := NewActivityService(mockRepo, config)
+
+	report, err := service.GetActivityReport(timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	pr := report.Repositories[0].PullRequests[0]
+	if len(pr.Commits) != 1 || pr.Commits[0].SHA != "day1234" || len(pr.Reviews) != 0 {
+		t.Errorf("Expected only the daytime commit in filter mode, got %+v and %+v", pr.Commits, pr.Reviews)
+	}
+
+	config.WorkingHours.Mode = WorkingHoursTag
+	report, err = service.GetActivityReport(timeRange)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	pr = report.Repositories[0].PullRequests[0]
+	if len(pr.Commits) != 2 || pr.Commits[0].AfterHours || !pr.Commits[1].AfterHours {
+		t.Errorf("Expected only the late commit to be tagged, got %+v", pr.Commits)
+	}
+	if len(pr.Reviews) != 1 || !pr.Reviews[0].AfterHours {
+		t.Errorf("Expected the late review to be tagged, got %+v", pr.Reviews)
+	}
+
+	content, err := NewMarkdownFormatter().Format(report)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if strings.Count(content.Content, "after hours") != 2 {
+		t.Errorf("Expected the late commit and review to render as after hours, got:\n%s", content)
+	}
+}
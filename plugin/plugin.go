@@ -1,20 +1,46 @@
 package plugin
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"os/exec"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"daiv-github/plugin/github"
 
 	plug "github.com/iures/daivplug"
+	"golang.org/x/text/language"
 )
 
+// ghCliTokenSource fetches a token from the gh CLI. It is a variable so tests
+// can verify when the CLI is consulted.
+var ghCliTokenSource TokenSource = CLITokenSource{}
+
+// ghCliUser fetches the login of the gh CLI's authenticated account. It is a
+// variable so tests can verify when the CLI is consulted.
+var ghCliUser = func() (string, error) {
+	return runGh(nil, "api", "user", "--jq", ".login")
+}
+
 type GitHubPlugin struct {
 	client    *github.GitHubClient
 	config    *github.GitHubConfig
 	service   *github.ActivityService
 	formatter github.ReportFormatter
+	
+	// File the formatted report is written to instead of being returned
+	outputFile string
+	
+	// Size budget of the formatted report in bytes. Zero is unlimited.
+	maxOutputBytes int
+	
+	// Fingerprint of the report behind the last standup context
+	lastFingerprint string
 }
 
 func New() *GitHubPlugin {
@@ -32,8 +58,8 @@ func (g *GitHubPlugin) Manifest() *plug.PluginManifest {
 				Type:        plug.ConfigTypeString,
 				Key:         "github.username",
 				Name:        "GitHub Username",
-				Description: "Your GitHub username",
-				Required:    true,
+				Description: "Your GitHub username (default: the account the gh CLI is logged in as)",
+				Required:    false,
 			},
 			{
 				Type:        plug.ConfigTypeString,
@@ -42,6 +68,15 @@ func (g *GitHubPlugin) Manifest() *plug.PluginManifest {
 				Description: "The GitHub organization to monitor",
 				Required:    true,
 			},
+			{
+				Type:        plug.ConfigTypePassword,
+				Key:         "github.token",
+				Name:        "GitHub Token",
+				Description: "A personal access token (defaults to GITHUB_TOKEN, GH_TOKEN, or the gh CLI)",
+				Required:    false,
+				Secret:      true,
+				EnvVar:      "GITHUB_TOKEN",
+			},
 			{
 				Type:        plug.ConfigTypeMultiline,
 				Key:         "github.repositories",
@@ -51,30 +86,590 @@ func (g *GitHubPlugin) Manifest() *plug.PluginManifest {
 			},
 			{
 				Type:        plug.ConfigTypeString,
-				Key:         "github.format",
-				Name:        "Report Format",
-				Description: "The format for the activity report (json, markdown, or html)",
+				Key:         "github.pr_url",
+				Name:        "Pull Request URL",
+				Description: "The URL of a single pull request to report on with its full activity, instead of the organization's repositories",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.custom_query",
+				Name:        "Custom Query",
+				Description: "A raw GitHub search query, such as a saved search, to monitor instead of the organization's repositories",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.format",
+				Name:        "Report Format",
+				Description: "The format for the activity report (json, markdown, html, or teams)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.base_branch",
+				Name:        "Base Branch",
+				Description: "The base branch to filter pull requests by, or any for every base branch (default: master)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.base_branch_pattern",
+				Name:        "Base Branch Pattern",
+				Description: "Only include pull requests whose base branch matches this glob (e.g. release/*), overriding the base branch",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_authored",
+				Name:        "Include Authored PRs",
+				Description: "Whether to include authored pull requests (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_reviewed",
+				Name:        "Include Reviewed PRs",
+				Description: "Whether to include reviewed pull requests (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.head_branch_prefix",
+				Name:        "Head Branch Prefix",
+				Description: "Only include pull requests whose head branch starts with this prefix (e.g. feat/<user>/)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.title_prefix",
+				Name:        "Title Prefix",
+				Description: "Only include pull requests whose title starts with this prefix (e.g. [PROJ-)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.title_regex",
+				Name:        "Title Pattern",
+				Description: "Only include pull requests whose title matches this regular expression",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.body_fields",
+				Name:        "Body Fields",
+				Description: "Fields to extract from pull request bodies, as a JSON object of field name to regular expression whose first group is the value (e.g. {\"Risk\": \"Risk:\\\\s*(\\\\w+)\"})",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.milestone",
+				Name:        "Milestone",
+				Description: "Only include pull requests in this milestone",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_review_decision",
+				Name:        "Include Review Decision",
+				Description: "Whether to include the overall review decision of each pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_self_reviews",
+				Name:        "Include Self-Reviews",
+				Description: "Whether to show your reviews of your own pull requests under authored pull requests (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.latest_review_per_author",
+				Name:        "Latest Review Per Author",
+				Description: "Whether to keep only each reviewer's most recent review of a pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.use_author_date",
+				Name:        "Use Author Date",
+				Description: "Whether to place commits in the time range by their author date instead of their committer date (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.review_states",
+				Name:        "Review States",
+				Description: "Comma-separated review states that count as review activity (e.g. APPROVED, CHANGES_REQUESTED)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.resolve_display_names",
+				Name:        "Resolve Display Names",
+				Description: "Whether to show pull request authors by their display name instead of their login (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_direct_commits",
+				Name:        "Include Direct Commits",
+				Description: "Whether to list your commits on each repository's default branch, including pushes without a pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_feedback_received",
+				Name:        "Include Feedback Received",
+				Description: "Whether to list the reviews and review comments others left on your pull requests (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_metadata_actions",
+				Name:        "Include Metadata Actions",
+				Description: "Whether to list and count the labels, retitles, and assignments you made on pull requests as activity (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_assigned_issues",
+				Name:        "Include Assigned Issues",
+				Description: "Whether to list the open issues assigned to you in each repository as in progress (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.assigned_issues_in_range",
+				Name:        "Assigned Issues In Range",
+				Description: "Whether to only list assigned issues updated in the time range (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_project_status",
+				Name:        "Include Project Status",
+				Description: "Whether to show the Status of each pull request on its GitHub Projects boards (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.path_prefix",
+				Name:        "Path Prefix",
+				Description: "Only include commits and review comments touching files under this path (e.g. services/api/)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.exclude_paths",
+				Name:        "Exclude Paths",
+				Description: "Comma-separated glob patterns of files whose review comments are left out (e.g. go.sum, *.pb.go)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_closed_by_user",
+				Name:        "Include Closed By User",
+				Description: "Whether to include other authors' pull requests you closed without merging (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.only_requested_reviews",
+				Name:        "Only Requested Reviews",
+				Description: "Whether to only include reviewed pull requests you were requested to review (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.only_reviewed_in_range",
+				Name:        "Only Reviewed In Range",
+				Description: "Whether to drop reviewed pull requests without a review by you in the time range (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_mergeable",
+				Name:        "Include Mergeable",
+				Description: "Whether to flag open pull requests that have merge conflicts (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_size",
+				Name:        "Include Size",
+				Description: "Whether to label pull requests with a size category (XS/S/M/L/XL) by their changed lines (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.size_thresholds",
+				Name:        "Size Thresholds",
+				Description: "Changed lines at which pull requests move up to S, M, L, and XL, comma-separated (default: 10,30,100,500)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.min_size",
+				Name:        "Minimum Size",
+				Description: "Only report pull requests of this size category or larger (XS/S/M/L/XL)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_files",
+				Name:        "Include Files",
+				Description: "Whether to list the files changed by each pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.max_files",
+				Name:        "Max Files",
+				Description: "Maximum number of changed files listed per pull request (default: 20)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.max_concurrency",
+				Name:        "Max Concurrency",
+				Description: "Maximum number of concurrent API calls made to enrich pull requests across a report (default: 4)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_unresolved_threads",
+				Name:        "Include Unresolved Threads",
+				Description: "Whether to count unresolved review threads of each pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_conversation_comments",
+				Name:        "Include Conversation Comments",
+				Description: "Whether to include your comments on the conversation tab of pull requests alongside review comments (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.query.include_threads_started",
+				Name:        "Include Threads Started",
+				Description: "Whether to count the review threads you started on each pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.user_agent",
+				Name:        "User Agent",
+				Description: "The user agent sent to the GitHub API (default: daiv-github/<version>)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.link_commits",
+				Name:        "Link Commits",
+				Description: "Whether to render commits with a short SHA linking to GitHub (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.max_bytes",
+				Name:        "Max Output Bytes",
+				Description: "Size budget of the report in bytes; larger reports drop commit and comment bodies and then the oldest pull requests to fit",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.file",
+				Name:        "Output File",
+				Description: "File to write the formatted report to, instead of including it in the standup context",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.show_verification",
+				Name:        "Show Verification",
+				Description: "Whether to mark each commit as verified or unverified by its signature (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.combined_prs",
+				Name:        "Combined Pull Requests",
+				Description: "Whether to list authored, reviewed, and closed pull requests in one section per repository (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.commit_types",
+				Name:        "Commit Types",
+				Description: "Whether to tally the conventional commit types (feat, fix, ...) of each pull request (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.relative_times",
+				Name:        "Relative Times",
+				Description: "Whether to render timestamps relative to now, such as 3h ago (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.relative_times_threshold",
+				Name:        "Relative Times Threshold",
+				Description: "Age beyond which timestamps are rendered in full, such as 48h (default: 168h)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.task_list",
+				Name:        "Task List",
+				Description: "Whether to render pull requests in Markdown output as task list items, checked once merged or closed (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.short_links",
+				Name:        "Short Links",
+				Description: "Whether to render pull request links as org/repo#123 text instead of raw URLs (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.emoji",
+				Name:        "State Emoji",
+				Description: "Whether to prefix pull request titles in Markdown output with an emoji for their state (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.dependencies",
+				Name:        "Dependencies",
+				Description: "Whether to link the pull requests each pull request depends on, as noted in its body (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.author_avatars",
+				Name:        "Author Avatars",
+				Description: "Whether to show each pull request author's avatar and profile link in HTML output (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.heatmap",
+				Name:        "Activity Heatmap",
+				Description: "Whether to render a weekday by hour heatmap of your activity in HTML output (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.footer",
+				Name:        "Generated Footer",
+				Description: "Whether to end reports with when they were generated, their time range, and the plugin version (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.compact_json",
+				Name:        "Compact JSON",
+				Description: "Whether to render JSON output without indentation or newlines (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.bom",
+				Name:        "Byte Order Mark",
+				Description: "Whether to start Markdown output with a UTF-8 byte order mark (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.line_endings",
+				Name:        "Line Endings",
+				Description: "Line endings of Markdown output: lf or crlf (default: lf)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.omit_header",
+				Name:        "Omit Header",
+				Description: "Whether to leave out the report title and metadata when embedding the output (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.link_references",
+				Name:        "Link References",
+				Description: "Whether to render #123 and GH-123 references in commits, reviews, and comments as links (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.summary",
+				Name:        "Summary",
+				Description: "Whether to render a roll-up of pull request counts per active repository (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.max_repos_in_summary",
+				Name:        "Max Repositories In Summary",
+				Description: "Maximum number of repositories listed in the summary, most active first (0 for no limit)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.locale",
+				Name:        "Locale",
+				Description: "Language tag for weekday and month names in rendered dates (e.g. en, fr, de-CH)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.fast_fail",
+				Name:        "Fast Fail",
+				Description: "Whether to abort the report on the first error instead of skipping failed repositories (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.rate_limit",
+				Name:        "Rate Limit",
+				Description: "Maximum API requests per second, optionally followed by a burst size (e.g. 5 or 5,10). Unlimited when unset",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.rate_limit_strategy",
+				Name:        "Rate Limit Strategy",
+				Description: "What to do when GitHub's rate limit runs out mid-run: wait until it resets, or fail (wait/fail)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.debug_dump_dir",
+				Name:        "Debug Dump Directory",
+				Description: "Directory to write raw API response bodies to for debugging",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.post_merge",
+				Name:        "Post-Merge Activity",
+				Description: "What to do with reviews and comments made after a pull request was merged: tag or filter (default: keep them unmarked)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.working_hours",
+				Name:        "Working Hours",
+				Description: "Working hours as HH:MM-HH:MM (e.g. 09:00-17:00); activity outside them is tagged or filtered",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.working_days",
+				Name:        "Working Days",
+				Description: "Working days for the working hours (e.g. mon-fri or mon,wed,fri; default: every day)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.working_hours_timezone",
+				Name:        "Working Hours Timezone",
+				Description: "IANA timezone of the working hours (e.g. Europe/Berlin; default: UTC)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.working_hours_mode",
+				Name:        "Working Hours Mode",
+				Description: "What to do with activity outside working hours: tag or filter (default: tag)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.activity.authored",
+				Name:        "Authored Activity",
+				Description: "Comma-separated activity that makes an authored pull request reportable (commits, reviews, comments, state)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.activity.reviewed",
+				Name:        "Reviewed Activity",
+				Description: "Comma-separated activity that makes a reviewed pull request reportable (commits, reviews, comments, state)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.min_activity_items",
+				Name:        "Minimum Activity Items",
+				Description: "Minimum number of commits, reviews, and comments for a full report; quieter periods get a short message (0 disables)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.output.open_prs_only",
+				Name:        "Open Pull Requests Only",
+				Description: "Whether to report only pull requests that are still open, for what's in flight (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.explain",
+				Name:        "Explain",
+				Description: "Whether to annotate each pull request with why it was included (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.archived_repos",
+				Name:        "Archived Repositories",
+				Description: "How to handle archived or disabled repositories: skip or tag (default: not checked)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.canonical_repo_names",
+				Name:        "Canonical Repository Names",
+				Description: "Whether to resolve configured repository names to their canonical casing (true/false)",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.cache_dir",
+				Name:        "Cache Directory",
+				Description: "Directory to cache search and enrichment results in, so reports run again soon after reuse them",
+				Required:    false,
+			},
+			{
+				Type:        plug.ConfigTypeString,
+				Key:         "github.cache_ttl",
+				Name:        "Cache TTL",
+				Description: "How long cached results are used, such as 30m (default: 1h)",
 				Required:    false,
 			},
 			{
 				Type:        plug.ConfigTypeString,
-				Key:         "github.query.base_branch",
-				Name:        "Base Branch",
-				Description: "The base branch to filter pull requests by (default: master)",
+				Key:         "github.total_budget",
+				Name:        "Total Time Budget",
+				Description: "Maximum time a report may take, such as 2m, after which the remaining repositories are skipped",
 				Required:    false,
 			},
 			{
 				Type:        plug.ConfigTypeString,
-				Key:         "github.query.include_authored",
-				Name:        "Include Authored PRs",
-				Description: "Whether to include authored pull requests (true/false)",
+				Key:         "github.checkpoint_file",
+				Name:        "Checkpoint File",
+				Description: "File used to record completed repositories so an interrupted report can be resumed",
 				Required:    false,
 			},
 			{
 				Type:        plug.ConfigTypeString,
-				Key:         "github.query.include_reviewed",
-				Name:        "Include Reviewed PRs",
-				Description: "Whether to include reviewed pull requests (true/false)",
+				Key:         "github.resume",
+				Name:        "Resume From Checkpoint",
+				Description: "Whether to skip repositories already recorded in the checkpoint file (true/false)",
 				Required:    false,
 			},
 		},
@@ -82,9 +677,9 @@ func (g *GitHubPlugin) Manifest() *plug.PluginManifest {
 }
 
 func (g *GitHubPlugin) Initialize(settings map[string]any) error {
-	token, err := getGhCliToken()
+	token, err := resolveToken(settings)
 	if err != nil {
-		return fmt.Errorf("failed to get gh cli token: %w", err)
+		return err
 	}
 
 	reposStr, ok := settings["github.repositories"].(string)
@@ -97,9 +692,9 @@ func (g *GitHubPlugin) Initialize(settings map[string]any) error {
 		repos[i] = strings.TrimSpace(repo)
 	}
 
-	username, ok := settings["github.username"].(string)
-	if !ok {
-		return fmt.Errorf("username is required")
+	username, err := resolveUsername(settings)
+	if err != nil {
+		return err
 	}
 	
 	org, ok := settings["github.organization"].(string)
@@ -111,8 +706,17 @@ func (g *GitHubPlugin) Initialize(settings map[string]any) error {
 	queryOptions := github.DefaultQueryOptions()
 
 	// Override with user-provided options if available
-	if baseBranch, ok := settings["github.query.base_branch"].(string); ok && baseBranch != "" {
-		queryOptions.BaseBranch = baseBranch
+	// An explicitly empty base branch disables base filtering, like "any"
+	if baseBranch, ok := settings["github.query.base_branch"].(string); ok {
+		queryOptions.BaseBranch = strings.TrimSpace(baseBranch)
+	}
+
+	if basePattern, ok := settings["github.query.base_branch_pattern"].(string); ok && strings.TrimSpace(basePattern) != "" {
+		basePattern = strings.TrimSpace(basePattern)
+		if _, err := path.Match(basePattern, ""); err != nil {
+			return fmt.Errorf("invalid github.query.base_branch_pattern %q: %w", basePattern, err)
+		}
+		queryOptions.BaseBranchPattern = basePattern
 	}
 
 	if includeAuthored, ok := settings["github.query.include_authored"].(string); ok && includeAuthored != "" {
@@ -123,6 +727,178 @@ func (g *GitHubPlugin) Initialize(settings map[string]any) error {
 		queryOptions.IncludeReviewed = includeReviewed == "true"
 	}
 
+	if headBranchPrefix, ok := settings["github.query.head_branch_prefix"].(string); ok && headBranchPrefix != "" {
+		queryOptions.HeadBranchPrefix = headBranchPrefix
+	}
+
+	if titlePrefix, ok := settings["github.query.title_prefix"].(string); ok && titlePrefix != "" {
+		queryOptions.TitlePrefix = titlePrefix
+	}
+
+	if titleRegex, ok := settings["github.query.title_regex"].(string); ok && titleRegex != "" {
+		pattern, err := regexp.Compile(titleRegex)
+		if err != nil {
+			return fmt.Errorf("invalid github.query.title_regex: %w", err)
+		}
+		queryOptions.TitlePattern = pattern
+	}
+
+	if bodyFields, ok := settings["github.query.body_fields"]; ok && bodyFields != nil && bodyFields != "" {
+		patterns, err := parseBodyFieldPatterns(bodyFields)
+		if err != nil {
+			return fmt.Errorf("invalid github.query.body_fields: %w", err)
+		}
+		queryOptions.BodyFieldPatterns = patterns
+	}
+
+	if milestone, ok := settings["github.query.milestone"].(string); ok && milestone != "" {
+		queryOptions.Milestone = milestone
+	}
+
+	if includeReviewDecision, ok := settings["github.query.include_review_decision"].(string); ok && includeReviewDecision != "" {
+		queryOptions.IncludeReviewDecision = includeReviewDecision == "true"
+	}
+
+	if includeSelfReviews, ok := settings["github.query.include_self_reviews"].(string); ok && includeSelfReviews != "" {
+		queryOptions.IncludeSelfReviews = includeSelfReviews == "true"
+	}
+
+	if latestReviewPerAuthor, ok := settings["github.query.latest_review_per_author"].(string); ok && latestReviewPerAuthor != "" {
+		queryOptions.LatestReviewPerAuthor = latestReviewPerAuthor == "true"
+	}
+
+	if useAuthorDate, ok := settings["github.query.use_author_date"].(string); ok && useAuthorDate != "" {
+		queryOptions.UseAuthorDate = useAuthorDate == "true"
+	}
+
+	if reviewStates, ok := settings["github.query.review_states"].(string); ok && reviewStates != "" {
+		for _, state := range strings.Split(reviewStates, ",") {
+			state = strings.ToUpper(strings.TrimSpace(state))
+			if state == "" {
+				continue
+			}
+			switch state {
+			case "APPROVED", "CHANGES_REQUESTED", "COMMENTED", "DISMISSED":
+				queryOptions.ReviewStates = append(queryOptions.ReviewStates, state)
+			default:
+				return fmt.Errorf("invalid github.query.review_states state: %q", state)
+			}
+		}
+	}
+
+	if resolveDisplayNames, ok := settings["github.query.resolve_display_names"].(string); ok && resolveDisplayNames != "" {
+		queryOptions.ResolveDisplayNames = resolveDisplayNames == "true"
+	}
+
+	if includeDirectCommits, ok := settings["github.query.include_direct_commits"].(string); ok && includeDirectCommits != "" {
+		queryOptions.IncludeDirectCommits = includeDirectCommits == "true"
+	}
+
+	if includeFeedbackReceived, ok := settings["github.query.include_feedback_received"].(string); ok && includeFeedbackReceived != "" {
+		queryOptions.IncludeFeedbackReceived = includeFeedbackReceived == "true"
+	}
+
+	if includeMetadataActions, ok := settings["github.query.include_metadata_actions"].(string); ok && includeMetadataActions != "" {
+		queryOptions.IncludeMetadataActions = includeMetadataActions == "true"
+	}
+
+	if includeAssignedIssues, ok := settings["github.query.include_assigned_issues"].(string); ok && includeAssignedIssues != "" {
+		queryOptions.IncludeAssignedIssues = includeAssignedIssues == "true"
+	}
+
+	if assignedIssuesInRange, ok := settings["github.query.assigned_issues_in_range"].(string); ok && assignedIssuesInRange != "" {
+		queryOptions.AssignedIssuesInRange = assignedIssuesInRange == "true"
+	}
+
+	if includeProjectStatus, ok := settings["github.query.include_project_status"].(string); ok && includeProjectStatus != "" {
+		queryOptions.IncludeProjectStatus = includeProjectStatus == "true"
+	}
+
+	if pathPrefix, ok := settings["github.query.path_prefix"].(string); ok && pathPrefix != "" {
+		queryOptions.PathPrefix = pathPrefix
+	}
+
+	if excludePaths, ok := settings["github.query.exclude_paths"].(string); ok && excludePaths != "" {
+		for _, pattern := range strings.Split(excludePaths, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid github.query.exclude_paths pattern %q: %w", pattern, err)
+			}
+			queryOptions.ExcludePaths = append(queryOptions.ExcludePaths, pattern)
+		}
+	}
+
+	if includeClosedByUser, ok := settings["github.query.include_closed_by_user"].(string); ok && includeClosedByUser != "" {
+		queryOptions.IncludeClosedByUser = includeClosedByUser == "true"
+	}
+
+	if onlyRequested, ok := settings["github.query.only_requested_reviews"].(string); ok && onlyRequested != "" {
+		queryOptions.OnlyRequestedReviews = onlyRequested == "true"
+	}
+
+	if onlyReviewedInRange, ok := settings["github.query.only_reviewed_in_range"].(string); ok && onlyReviewedInRange != "" {
+		queryOptions.OnlyReviewedInRange = onlyReviewedInRange == "true"
+	}
+
+	if includeMergeable, ok := settings["github.query.include_mergeable"].(string); ok && includeMergeable != "" {
+		queryOptions.IncludeMergeable = includeMergeable == "true"
+	}
+
+	if includeSize, ok := settings["github.query.include_size"].(string); ok && includeSize != "" {
+		queryOptions.IncludeSize = includeSize == "true"
+	}
+
+	if sizeThresholds, ok := settings["github.query.size_thresholds"].(string); ok && sizeThresholds != "" {
+		thresholds, err := github.ParseSizeThresholds(sizeThresholds)
+		if err != nil {
+			return fmt.Errorf("invalid github.query.size_thresholds: %w", err)
+		}
+		queryOptions.SizeThresholds = thresholds
+	}
+
+	if minSize, ok := settings["github.query.min_size"].(string); ok && minSize != "" {
+		category, err := github.ParseSizeCategory(minSize)
+		if err != nil {
+			return fmt.Errorf("invalid github.query.min_size: %w", err)
+		}
+		queryOptions.MinSizeCategory = category
+	}
+
+	if includeFiles, ok := settings["github.query.include_files"].(string); ok && includeFiles != "" {
+		queryOptions.IncludeFiles = includeFiles == "true"
+	}
+
+	if maxFiles, ok := settings["github.query.max_files"].(string); ok && maxFiles != "" {
+		max, err := strconv.Atoi(maxFiles)
+		if err != nil || max < 1 {
+			return fmt.Errorf("invalid github.query.max_files: %q", maxFiles)
+		}
+		queryOptions.MaxFiles = max
+	}
+
+	if maxConcurrency, ok := settings["github.query.max_concurrency"].(string); ok && maxConcurrency != "" {
+		max, err := strconv.Atoi(maxConcurrency)
+		if err != nil || max < 1 {
+			return fmt.Errorf("invalid github.query.max_concurrency: %q", maxConcurrency)
+		}
+		queryOptions.MaxConcurrency = max
+	}
+
+	if includeUnresolvedThreads, ok := settings["github.query.include_unresolved_threads"].(string); ok && includeUnresolvedThreads != "" {
+		queryOptions.IncludeUnresolvedThreads = includeUnresolvedThreads == "true"
+	}
+
+	if includeConversationComments, ok := settings["github.query.include_conversation_comments"].(string); ok && includeConversationComments != "" {
+		queryOptions.IncludeConversationComments = includeConversationComments == "true"
+	}
+
+	if includeThreadsStarted, ok := settings["github.query.include_threads_started"].(string); ok && includeThreadsStarted != "" {
+		queryOptions.IncludeThreadsStarted = includeThreadsStarted == "true"
+	}
+
 	// Create the config
 	config := &github.GitHubConfig{
 		Username:     username,
@@ -132,6 +908,162 @@ func (g *GitHubPlugin) Initialize(settings map[string]any) error {
 		QueryOptions: queryOptions,
 	}
 
+	if userAgent, ok := settings["github.user_agent"].(string); ok && userAgent != "" {
+		config.UserAgent = userAgent
+	}
+
+	if customQuery, ok := settings["github.custom_query"].(string); ok && strings.TrimSpace(customQuery) != "" {
+		config.CustomQuery = strings.TrimSpace(customQuery)
+	}
+
+	if prURL, ok := settings["github.pr_url"].(string); ok && strings.TrimSpace(prURL) != "" {
+		if _, _, _, err := github.ParsePullRequestURL(prURL); err != nil {
+			return fmt.Errorf("invalid github.pr_url: %w", err)
+		}
+		config.PullRequestURL = strings.TrimSpace(prURL)
+	}
+
+	if fastFail, ok := settings["github.fast_fail"].(string); ok && fastFail != "" {
+		config.FastFail = fastFail == "true"
+	}
+
+	if dumpDir, ok := settings["github.debug_dump_dir"].(string); ok && dumpDir != "" {
+		config.DebugDumpDir = dumpDir
+	}
+
+	if rateLimit, ok := settings["github.rate_limit"].(string); ok && rateLimit != "" {
+		requestsPerSecond, burst, err := parseRateLimit(rateLimit)
+		if err != nil {
+			return err
+		}
+		config.RateLimit = requestsPerSecond
+		config.RateLimitBurst = burst
+	}
+
+	if strategy, ok := settings["github.rate_limit_strategy"].(string); ok && strategy != "" {
+		switch strategy {
+		case github.RateLimitStrategyWait, github.RateLimitStrategyFail:
+			config.RateLimitStrategy = strategy
+		default:
+			return fmt.Errorf("invalid github.rate_limit_strategy: %q", strategy)
+		}
+	}
+
+	if postMerge, ok := settings["github.post_merge"].(string); ok && postMerge != "" {
+		switch postMerge {
+		case github.PostMergeTag, github.PostMergeFilter:
+			config.PostMerge = postMerge
+		default:
+			return fmt.Errorf("invalid github.post_merge: %q", postMerge)
+		}
+	}
+
+	if workingHours, ok := settings["github.working_hours"].(string); ok && workingHours != "" {
+		start, end, err := github.ParseWorkingHours(workingHours)
+		if err != nil {
+			return fmt.Errorf("invalid github.working_hours: %w", err)
+		}
+		config.WorkingHours = github.WorkingHours{Start: start, End: end, Mode: github.WorkingHoursTag}
+
+		if workingDays, ok := settings["github.working_days"].(string); ok && workingDays != "" {
+			days, err := github.ParseWorkDays(workingDays)
+			if err != nil {
+				return fmt.Errorf("invalid github.working_days: %w", err)
+			}
+			config.WorkingHours.WorkDays = days
+		}
+
+		if timezone, ok := settings["github.working_hours_timezone"].(string); ok && timezone != "" {
+			location, err := time.LoadLocation(timezone)
+			if err != nil {
+				return fmt.Errorf("invalid github.working_hours_timezone: %q", timezone)
+			}
+			config.WorkingHours.Location = location
+		}
+
+		if mode, ok := settings["github.working_hours_mode"].(string); ok && mode != "" {
+			switch mode {
+			case github.WorkingHoursTag, github.WorkingHoursFilter:
+				config.WorkingHours.Mode = mode
+			default:
+				return fmt.Errorf("invalid github.working_hours_mode: %q", mode)
+			}
+		}
+	}
+
+	if authoredActivity, ok := settings["github.activity.authored"].(string); ok && authoredActivity != "" {
+		criteria, err := github.ParseActivityCriteria(authoredActivity)
+		if err != nil {
+			return fmt.Errorf("invalid github.activity.authored: %w", err)
+		}
+		config.AuthoredActivity = criteria
+	}
+
+	if reviewedActivity, ok := settings["github.activity.reviewed"].(string); ok && reviewedActivity != "" {
+		criteria, err := github.ParseActivityCriteria(reviewedActivity)
+		if err != nil {
+			return fmt.Errorf("invalid github.activity.reviewed: %w", err)
+		}
+		config.ReviewedActivity = criteria
+	}
+
+	if minActivityItems, ok := settings["github.min_activity_items"].(string); ok && minActivityItems != "" {
+		min, err := strconv.Atoi(minActivityItems)
+		if err != nil || min < 0 {
+			return fmt.Errorf("invalid github.min_activity_items: %q", minActivityItems)
+		}
+		config.MinActivityItems = min
+	}
+
+	if openOnly, ok := settings["github.output.open_prs_only"].(string); ok && openOnly != "" {
+		config.OnlyOpen = openOnly == "true"
+	}
+
+	if explain, ok := settings["github.explain"].(string); ok && explain != "" {
+		config.Explain = explain == "true"
+	}
+
+	if archivedRepos, ok := settings["github.archived_repos"].(string); ok && archivedRepos != "" {
+		switch archivedRepos {
+		case github.ArchivedReposSkip, github.ArchivedReposTag:
+			config.ArchivedRepos = archivedRepos
+		default:
+			return fmt.Errorf("invalid github.archived_repos: %q", archivedRepos)
+		}
+	}
+
+	if canonicalNames, ok := settings["github.canonical_repo_names"].(string); ok && canonicalNames != "" {
+		config.CanonicalRepoNames = canonicalNames == "true"
+	}
+
+	if cacheDir, ok := settings["github.cache_dir"].(string); ok && cacheDir != "" {
+		config.CacheDir = cacheDir
+	}
+
+	if cacheTTL, ok := settings["github.cache_ttl"].(string); ok && cacheTTL != "" {
+		ttl, err := time.ParseDuration(cacheTTL)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid github.cache_ttl: %q", cacheTTL)
+		}
+		config.CacheTTL = ttl
+	}
+
+	if totalBudget, ok := settings["github.total_budget"].(string); ok && totalBudget != "" {
+		budget, err := time.ParseDuration(totalBudget)
+		if err != nil || budget <= 0 {
+			return fmt.Errorf("invalid github.total_budget: %q", totalBudget)
+		}
+		config.TotalBudget = budget
+	}
+
+	if checkpointFile, ok := settings["github.checkpoint_file"].(string); ok && checkpointFile != "" {
+		config.CheckpointFile = checkpointFile
+	}
+
+	if resume, ok := settings["github.resume"].(string); ok && resume != "" {
+		config.Resume = resume == "true"
+	}
+
 	// Create the client
 	client, err := github.NewGitHubClient(config)
 	if err != nil {
@@ -150,18 +1082,149 @@ func (g *GitHubPlugin) Initialize(settings map[string]any) error {
 		format = "markdown" // Default to markdown if not specified
 	}
 
+	formatterOptions := github.FormatterOptions{}
+
+	if linkCommits, ok := settings["github.output.link_commits"].(string); ok && linkCommits != "" {
+		formatterOptions.LinkCommits = linkCommits == "true"
+	}
+
+	if showVerification, ok := settings["github.output.show_verification"].(string); ok && showVerification != "" {
+		formatterOptions.ShowVerification = showVerification == "true"
+	}
+
+	if combinedPRs, ok := settings["github.output.combined_prs"].(string); ok && combinedPRs != "" {
+		formatterOptions.CombinedPRs = combinedPRs == "true"
+	}
+
+	if commitTypes, ok := settings["github.output.commit_types"].(string); ok && commitTypes != "" {
+		formatterOptions.ShowCommitTypes = commitTypes == "true"
+	}
+
+	if relativeTimes, ok := settings["github.output.relative_times"].(string); ok && relativeTimes != "" {
+		formatterOptions.RelativeTimes = relativeTimes == "true"
+	}
+
+	if threshold, ok := settings["github.output.relative_times_threshold"].(string); ok && threshold != "" {
+		duration, err := time.ParseDuration(threshold)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid github.output.relative_times_threshold: %q", threshold)
+		}
+		formatterOptions.RelativeTimesThreshold = duration
+	}
+
+	if taskList, ok := settings["github.output.task_list"].(string); ok && taskList != "" {
+		formatterOptions.TaskList = taskList == "true"
+	}
+
+	if shortLinks, ok := settings["github.output.short_links"].(string); ok && shortLinks != "" {
+		formatterOptions.ShortLinks = shortLinks == "true"
+	}
+
+	if emoji, ok := settings["github.output.emoji"].(string); ok && emoji != "" {
+		formatterOptions.UseEmoji = emoji == "true"
+	}
+
+	if dependencies, ok := settings["github.output.dependencies"].(string); ok && dependencies != "" {
+		formatterOptions.ShowDependencies = dependencies == "true"
+	}
+
+	if authorAvatars, ok := settings["github.output.author_avatars"].(string); ok && authorAvatars != "" {
+		formatterOptions.ShowAuthorAvatars = authorAvatars == "true"
+	}
+
+	if heatmap, ok := settings["github.output.heatmap"].(string); ok && heatmap != "" {
+		formatterOptions.Heatmap = heatmap == "true"
+	}
+
+	if footer, ok := settings["github.output.footer"].(string); ok && footer != "" {
+		formatterOptions.Footer = footer == "true"
+	}
+
+	if compactJSON, ok := settings["github.output.compact_json"].(string); ok && compactJSON != "" {
+		formatterOptions.CompactJSON = compactJSON == "true"
+	}
+
+	if bom, ok := settings["github.output.bom"].(string); ok && bom != "" {
+		formatterOptions.ByteOrderMark = bom == "true"
+	}
+
+	if lineEndings, ok := settings["github.output.line_endings"].(string); ok && lineEndings != "" {
+		switch strings.ToLower(lineEndings) {
+		case "lf":
+			formatterOptions.CRLF = false
+		case "crlf":
+			formatterOptions.CRLF = true
+		default:
+			return fmt.Errorf("invalid github.output.line_endings: %q", lineEndings)
+		}
+	}
+
+	if omitHeader, ok := settings["github.output.omit_header"].(string); ok && omitHeader != "" {
+		formatterOptions.OmitHeader = omitHeader == "true"
+	}
+
+	if linkReferences, ok := settings["github.output.link_references"].(string); ok && linkReferences != "" {
+		formatterOptions.LinkReferences = linkReferences == "true"
+	}
+
+	if includeSummary, ok := settings["github.output.summary"].(string); ok && includeSummary != "" {
+		formatterOptions.IncludeSummary = includeSummary == "true"
+	}
+
+	if maxRepos, ok := settings["github.output.max_repos_in_summary"].(string); ok && maxRepos != "" {
+		max, err := strconv.Atoi(maxRepos)
+		if err != nil || max < 0 {
+			return fmt.Errorf("invalid github.output.max_repos_in_summary: %q", maxRepos)
+		}
+		formatterOptions.MaxReposInSummary = max
+	}
+
+	if locale, ok := settings["github.locale"].(string); ok && locale != "" {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return fmt.Errorf("invalid github.locale: %w", err)
+		}
+		formatterOptions.Locale = tag
+	}
+
+	g.formatter = newFormatter(format, formatterOptions)
+
+	if outputFile, ok := settings["github.output.file"].(string); ok && outputFile != "" {
+		g.outputFile = outputFile
+	}
+
+	if maxBytes, ok := settings["github.output.max_bytes"].(string); ok && maxBytes != "" {
+		value, err := strconv.Atoi(maxBytes)
+		if err != nil || value < 0 {
+			return fmt.Errorf("invalid github.output.max_bytes: %q", maxBytes)
+		}
+		g.maxOutputBytes = value
+	}
+
+	return nil
+}
+
+// newFormatter returns the formatter for the given format, defaulting to Markdown
+func newFormatter(format string, options github.FormatterOptions) github.ReportFormatter {
 	switch format {
 	case "json":
-		g.formatter = github.NewJSONFormatter()
+		return &github.JSONFormatter{Compact: options.CompactJSON}
 	case "html":
-		g.formatter = github.NewHTMLFormatter()
-	case "markdown":
-		g.formatter = github.NewMarkdownFormatter()
+		return &github.HTMLFormatter{Options: options}
+	case "teams":
+		return &github.TeamsFormatter{Options: options}
 	default:
-		g.formatter = github.NewMarkdownFormatter()
+		return &github.MarkdownFormatter{Options: options}
 	}
+}
 
-	return nil
+// HealthCheck confirms the configured credentials can reach the GitHub API
+// without generating a full report
+func (g *GitHubPlugin) HealthCheck() (*github.HealthStatus, error) {
+	if g.client == nil {
+		return nil, fmt.Errorf("plugin is not initialized")
+	}
+	return g.client.Ping(context.Background())
 }
 
 func (g *GitHubPlugin) Shutdown() error {
@@ -174,9 +1237,28 @@ func (g *GitHubPlugin) GetStandupContext(timeRange plug.TimeRange) (plug.Standup
 	if err != nil {
 		return plug.StandupContext{}, fmt.Errorf("failed to get activity report: %w", err)
 	}
-	
-	// Format the report using the configured formatter
-	formattedContent, err := g.formatter.Format(report)
+	g.lastFingerprint = report.Fingerprint()
+
+	// A nearly empty report is noise, so summarize quiet periods instead
+	if count := report.ActivityItemCount(); count < g.config.MinActivityItems {
+		return plug.StandupContext{
+			PluginName: g.Name(),
+			Content:    quietPeriodMessage(count),
+		}, nil
+	}
+
+	if g.outputFile != "" {
+		if err := writeReportFile(g.outputFile, g.formatter, report, g.maxOutputBytes); err != nil {
+			return plug.StandupContext{}, err
+		}
+		return plug.StandupContext{
+			PluginName: g.Name(),
+			Content:    fmt.Sprintf("GitHub activity report written to %s.", g.outputFile),
+		}, nil
+	}
+
+	// Format the report using the configured formatter, within the budget
+	formattedContent, err := github.FormatWithinBudget(g.formatter, report, g.maxOutputBytes)
 	if err != nil {
 		return plug.StandupContext{}, fmt.Errorf("failed to format activity report: %w", err)
 	}
@@ -187,14 +1269,138 @@ func (g *GitHubPlugin) GetStandupContext(timeRange plug.TimeRange) (plug.Standup
 	}, nil
 }
 
-func getGhCliToken() (string, error) {
-	cmd := exec.Command("gh", "auth", "token")
-	output, err := cmd.Output()
+// LastFingerprint returns the fingerprint of the activity report behind the
+// last standup context, or an empty string before the first one. Hosts can
+// compare it across runs to skip re-posting an unchanged report.
+func (g *GitHubPlugin) LastFingerprint() string {
+	return g.lastFingerprint
+}
+
+// writeReportFile formats the report into the file at path, trimmed to
+// maxBytes like the standup context. Without a budget it is streamed when the
+// formatter supports it, so large reports are not buffered in memory.
+func writeReportFile(path string, formatter github.ReportFormatter, report *github.ActivityReport, maxBytes int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	if streaming, ok := formatter.(github.StreamingFormatter); ok && maxBytes <= 0 {
+		err = streaming.FormatTo(file, report)
+	} else {
+		var formattedContent *github.FormattedContent
+		formattedContent, err = github.FormatWithinBudget(formatter, report, maxBytes)
+		if err == nil {
+			_, err = file.WriteString(formattedContent.Content)
+		}
+	}
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("gh cli error: %s", string(exitErr.Stderr))
+		file.Close()
+		return fmt.Errorf("failed to write activity report: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write activity report: %w", err)
+	}
+	return nil
+}
+
+// parseBodyFieldPatterns compiles a github.query.body_fields setting, given
+// either as a map of field name to regular expression or as a JSON object
+func parseBodyFieldPatterns(value any) (map[string]*regexp.Regexp, error) {
+	sources := make(map[string]string)
+	switch value := value.(type) {
+	case string:
+		if err := json.Unmarshal([]byte(value), &sources); err != nil {
+			return nil, fmt.Errorf("expected a JSON object of field name to regular expression: %w", err)
+		}
+	case map[string]any:
+		for name, source := range value {
+			pattern, ok := source.(string)
+			if !ok {
+				return nil, fmt.Errorf("pattern of field %q is not a string", name)
+			}
+			sources[name] = pattern
+		}
+	case map[string]string:
+		sources = value
+	default:
+		return nil, fmt.Errorf("expected a map of field name to regular expression, got %T", value)
+	}
+
+	patterns := make(map[string]*regexp.Regexp, len(sources))
+	for name, source := range sources {
+		pattern, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", name, err)
+		}
+		patterns[name] = pattern
+	}
+	return patterns, nil
+}
+
+// parseRateLimit parses a github.rate_limit setting of the form
+// "<requests per second>[,<burst>]". The burst defaults to one request.
+func parseRateLimit(value string) (float64, int, error) {
+	rateStr, burstStr, hasBurst := strings.Cut(value, ",")
+
+	requestsPerSecond, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+	if err != nil || requestsPerSecond <= 0 {
+		return 0, 0, fmt.Errorf("invalid github.rate_limit: %q", value)
+	}
+
+	burst := 1
+	if hasBurst {
+		burst, err = strconv.Atoi(strings.TrimSpace(burstStr))
+		if err != nil || burst < 1 {
+			return 0, 0, fmt.Errorf("invalid github.rate_limit burst: %q", value)
+		}
+	}
+
+	return requestsPerSecond, burst, nil
+}
+
+// quietPeriodMessage describes a report below the minimum activity threshold
+func quietPeriodMessage(count int) string {
+	return fmt.Sprintf("Quiet period: only %d GitHub activity item(s) (commits, reviews, and comments) in the specified time range.", count)
+}
+
+// resolveToken returns the GitHub token from the first available source: the
+// github.token setting, the GITHUB_TOKEN or GH_TOKEN environment variables,
+// and finally the gh CLI. The CLI is never invoked when a token is already
+// available, so the plugin works as a library on machines without gh.
+func resolveToken(settings map[string]any) (string, error) {
+	if token, ok := settings["github.token"].(string); ok && strings.TrimSpace(token) != "" {
+		return strings.TrimSpace(token), nil
+	}
+
+	for _, envVar := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := strings.TrimSpace(os.Getenv(envVar)); token != "" {
+			return token, nil
 		}
-		return "", fmt.Errorf("failed to execute gh cli: %v", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+
+	token, err := ghCliTokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get gh cli token: %w", err)
+	}
+	return token, nil
+}
+
+// resolveUsername returns the github.username setting, falling back to the
+// account the gh CLI is logged in as. The CLI is only invoked when the
+// setting is absent.
+func resolveUsername(settings map[string]any) (string, error) {
+	if username, ok := settings["github.username"].(string); ok && strings.TrimSpace(username) != "" {
+		return strings.TrimSpace(username), nil
+	}
+
+	username, err := ghCliUser()
+	if err != nil {
+		return "", fmt.Errorf("username is required: failed to get gh cli user: %w", err)
+	}
+	if username == "" {
+		return "", fmt.Errorf("username is required")
+	}
+	return username, nil
 }
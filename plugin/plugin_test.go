@@ -0,0 +1,375 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"daiv-github/plugin/github"
+
+	plug "github.com/iures/daivplug"
+)
+
+// stubGhCliToken replaces the gh CLI token lookup for the duration of a test
+func stubGhCliToken(t *testing.T, fn func() (string, error)) {
+	t.Helper()
+
+	original := ghCliTokenSource
+	ghCliTokenSource = TokenSourceFunc(fn)
+	t.Cleanup(func() { ghCliTokenSource = original })
+}
+
+// stubGhCliUser replaces the gh CLI user lookup for the duration of a test
+func stubGhCliUser(t *testing.T, fn func() (string, error)) {
+	t.Helper()
+
+	original := ghCliUser
+	ghCliUser = fn
+	t.Cleanup(func() { ghCliUser = original })
+}
+
+// testSettings returns the minimal settings required by Initialize
+func testSettings() map[string]any {
+	return map[string]any{
+		"github.username":     "testuser",
+		"github.organization": "testorg",
+		"github.repositories": "repo1, repo2",
+	}
+}
+
+func TestInitialize_ConfiguredTokenSkipsGhCli(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	called := false
+	stubGhCliToken(t, func() (string, error) {
+		called = true
+		return "", errors.New("gh should not be executed")
+	})
+
+	settings := testSettings()
+	settings["github.token"] = "configured-token"
+
+	p := New()
+	if err := p.Initialize(settings); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if called {
+		t.Error("Expected gh cli not to be executed when github.token is set")
+	}
+
+	if p.config.Token != "configured-token" {
+		t.Errorf("Expected token 'configured-token', got '%s'", p.config.Token)
+	}
+}
+
+func TestResolveToken(t *testing.T) {
+	t.Run("Environment token skips gh cli", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "env-token")
+		stubGhCliToken(t, func() (string, error) {
+			t.Error("Expected gh cli not to be executed when GITHUB_TOKEN is set")
+			return "", nil
+		})
+
+		token, err := resolveToken(map[string]any{})
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if token != "env-token" {
+			t.Errorf("Expected token 'env-token', got '%s'", token)
+		}
+	})
+
+	t.Run("Falls back to gh cli", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		t.Setenv("GH_TOKEN", "")
+		stubGhCliToken(t, func() (string, error) {
+			return "cli-token", nil
+		})
+
+		token, err := resolveToken(map[string]any{})
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		if token != "cli-token" {
+			t.Errorf("Expected token 'cli-token', got '%s'", token)
+		}
+	})
+
+	t.Run("Reports gh cli failure", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		t.Setenv("GH_TOKEN", "")
+		stubGhCliToken(t, func() (string, error) {
+			return "", errors.New("gh not found")
+		})
+
+		if _, err := resolveToken(map[string]any{}); err == nil {
+			t.Error("Expected an error but got nil")
+		}
+	})
+}
+
+func TestGetStandupContext_QuietPeriod(t *testing.T) {
+	config := &github.GitHubConfig{
+		Username:         "testuser",
+		Organization:     "testorg",
+		Repositories:     []string{"repo1"},
+		QueryOptions:     github.DefaultQueryOptions(),
+		MinActivityItems: 5,
+	}
+	repository := &github.MockGitHubRepository{
+		MockGetUser: func() (*github.User, error) {
+			return &github.User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange github.TimeRange, options github.QueryOptions) ([]github.PullRequest, error) {
+			return []github.PullRequest{
+				{
+					Number:     1,
+					Title:      "Typo fix",
+					IsAuthored: true,
+					Commits:    []github.Commit{{SHA: "abc", Message: "Fix typo"}},
+				},
+			}, nil
+		},
+	}
+
+	p := &GitHubPlugin{
+		config:    config,
+		service:   github.NewActivityService(repository, config),
+		formatter: github.NewMarkdownFormatter(),
+	}
+
+	standupContext, err := p.GetStandupContext(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if standupContext.Content != quietPeriodMessage(1) {
+		t.Errorf("Expected the quiet period message, got:\n%s", standupContext.Content)
+	}
+}
+
+func TestGetStandupContext_OutputFile(t *testing.T) {
+	config := &github.GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: github.DefaultQueryOptions(),
+	}
+	repository := &github.MockGitHubRepository{
+		MockGetUser: func() (*github.User, error) {
+			return &github.User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange github.TimeRange, options github.QueryOptions) ([]github.PullRequest, error) {
+			return []github.PullRequest{{Number: 1, Title: "Add feature", IsAuthored: true}}, nil
+		},
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "report.json")
+	p := &GitHubPlugin{
+		config:     config,
+		service:    github.NewActivityService(repository, config),
+		formatter:  github.NewJSONFormatter(),
+		outputFile: outputFile,
+	}
+
+	standupContext, err := p.GetStandupContext(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !strings.Contains(standupContext.Content, outputFile) {
+		t.Errorf("Expected the content to mention the output file, got: %s", standupContext.Content)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Error reading output file: %v", err)
+	}
+	if !strings.Contains(string(content), `"title": "Add feature"`) {
+		t.Errorf("Expected the report in the output file, got:\n%s", content)
+	}
+	if p.LastFingerprint() == "" {
+		t.Errorf("Expected the report fingerprint to be recorded")
+	}
+}
+
+func TestGetStandupContext_OutputFileWithinBudget(t *testing.T) {
+	config := &github.GitHubConfig{
+		Username:     "testuser",
+		Organization: "testorg",
+		Repositories: []string{"repo1"},
+		QueryOptions: github.DefaultQueryOptions(),
+	}
+	repository := &github.MockGitHubRepository{
+		MockGetUser: func() (*github.User, error) {
+			return &github.User{Username: "testuser"}, nil
+		},
+		MockGetPullRequests: func(org string, repo string, timeRange github.TimeRange, options github.QueryOptions) ([]github.PullRequest, error) {
+			return []github.PullRequest{{
+				Number:     1,
+				Title:      "Add feature",
+				IsAuthored: true,
+				Commits:    []github.Commit{{SHA: "abc123", Message: "Add feature\n\n" + strings.Repeat("Lorem ipsum ", 500)}},
+			}}, nil
+		},
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "report.json")
+	p := &GitHubPlugin{
+		config:         config,
+		service:        github.NewActivityService(repository, config),
+		formatter:      github.NewJSONFormatter(),
+		outputFile:     outputFile,
+		maxOutputBytes: 2000,
+	}
+
+	_, err := p.GetStandupContext(plug.TimeRange{
+		Start: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Error reading output file: %v", err)
+	}
+	if len(content) > 2000 || strings.Contains(string(content), "Lorem ipsum") {
+		t.Errorf("Expected the output file trimmed to the budget, got %d bytes:\n%s", len(content), content)
+	}
+	if !strings.Contains(string(content), `"title": "Add feature"`) {
+		t.Errorf("Expected the pull request kept in the output file, got:\n%s", content)
+	}
+}
+
+func TestInitialize_UsernameFromGhCli(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	stubGhCliUser(t, func() (string, error) {
+		return "cli-user", nil
+	})
+
+	settings := testSettings()
+	delete(settings, "github.username")
+
+	p := New()
+	if err := p.Initialize(settings); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if p.config.Username != "cli-user" {
+		t.Errorf("Expected username 'cli-user', got '%s'", p.config.Username)
+	}
+}
+
+func TestInitialize_ConfiguredUsernameSkipsGhCli(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	stubGhCliUser(t, func() (string, error) {
+		t.Error("Expected gh cli not to be executed when github.username is set")
+		return "", nil
+	})
+
+	p := New()
+	if err := p.Initialize(testSettings()); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if p.config.Username != "testuser" {
+		t.Errorf("Expected username 'testuser', got '%s'", p.config.Username)
+	}
+}
+
+func TestResolveUsername_GhCliFailure(t *testing.T) {
+	stubGhCliUser(t, func() (string, error) {
+		return "", errors.New("gh not found")
+	})
+
+	if _, err := resolveUsername(map[string]any{}); err == nil {
+		t.Error("Expected an error but got nil")
+	}
+}
+
+func TestInitialize_TitleRegex(t *testing.T) {
+	settings := testSettings()
+	settings["github.token"] = "configured-token"
+	settings["github.query.title_regex"] = `^\[PROJ-\d+\]`
+
+	p := New()
+	if err := p.Initialize(settings); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if p.config.QueryOptions.TitlePattern == nil || !p.config.QueryOptions.TitlePattern.MatchString("[PROJ-1] Fix") {
+		t.Errorf("Expected the title pattern to be compiled, got %v", p.config.QueryOptions.TitlePattern)
+	}
+
+	settings["github.query.title_regex"] = `[PROJ-`
+	if err := New().Initialize(settings); err == nil {
+		t.Error("Expected an error for an invalid title regex")
+	}
+}
+
+func TestInitialize_BodyFields(t *testing.T) {
+	settings := testSettings()
+	settings["github.token"] = "configured-token"
+	settings["github.query.body_fields"] = `{"Risk": "Risk:\\s*(\\w+)"}`
+
+	p := New()
+	if err := p.Initialize(settings); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if pattern := p.config.QueryOptions.BodyFieldPatterns["Risk"]; pattern == nil || !pattern.MatchString("Risk: high") {
+		t.Errorf("Expected the Risk pattern to be compiled, got %v", p.config.QueryOptions.BodyFieldPatterns)
+	}
+
+	settings["github.query.body_fields"] = map[string]any{"Rollback": `Rollback:\s*(\w+)`}
+	if err := New().Initialize(settings); err != nil {
+		t.Errorf("Expected a map of patterns to be accepted, got: %v", err)
+	}
+
+	settings["github.query.body_fields"] = map[string]any{"Risk": `Risk: (`}
+	if err := New().Initialize(settings); err == nil {
+		t.Error("Expected an error for an invalid body field regex")
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	testCases := []struct {
+		value             string
+		requestsPerSecond float64
+		burst             int
+		wantErr           bool
+	}{
+		{value: "5", requestsPerSecond: 5, burst: 1},
+		{value: "0.5, 10", requestsPerSecond: 0.5, burst: 10},
+		{value: "0", wantErr: true},
+		{value: "fast", wantErr: true},
+		{value: "5,0", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		requestsPerSecond, burst, err := parseRateLimit(tc.value)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("Expected an error for %q", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected no error for %q but got: %v", tc.value, err)
+			continue
+		}
+		if requestsPerSecond != tc.requestsPerSecond || burst != tc.burst {
+			t.Errorf("Expected %v requests/s with burst %d for %q, got %v and %d", tc.requestsPerSecond, tc.burst, tc.value, requestsPerSecond, burst)
+		}
+	}
+}